@@ -17,6 +17,7 @@ import (
 	"gigme/backend/internal/integrations"
 	tochkaapi "gigme/backend/internal/integrations/tochka"
 	"gigme/backend/internal/logging"
+	"gigme/backend/internal/models"
 	"gigme/backend/internal/repository"
 
 	"github.com/go-chi/chi/v5"
@@ -50,6 +51,9 @@ func main() {
 
 	repo := repository.New(pool)
 	telegram := integrations.NewTelegramClient(cfg.TelegramToken)
+	if cfg.Webhook.Secret == "" {
+		logger.Warn("telegram_webhook_secret_missing", "detail", "TELEGRAM_WEBHOOK_SECRET is not set; webhook updates are rejected")
+	}
 	var tochkaClient *tochkaapi.Client
 	if cfg.Tochka.ClientID != "" && cfg.Tochka.ClientSecret != "" {
 		tokenManager := tochkaapi.NewTokenManager(tochkaapi.TokenManagerConfig{
@@ -91,6 +95,8 @@ func main() {
 	r.Get("/media/events/{id}/{index}", h.EventMedia)
 	r.Get("/landing/events", h.LandingEvents)
 	r.Get("/landing/content", h.LandingContent)
+	r.Get("/events/viewport", h.MapViewport)
+	r.Get("/events/tiles/{z}/{x}/{y}", h.MapTile)
 
 	r.Post("/auth/telegram", h.AuthTelegram)
 	r.Post("/auth/vk/start", h.AuthVKStart)
@@ -100,10 +106,14 @@ func main() {
 	r.Get("/auth/standalone", h.StandaloneAuthPage)
 	r.Post("/auth/standalone/exchange", h.StandaloneAuthExchange)
 	r.Post("/telegram/webhook", h.TelegramWebhook)
+	r.Get("/calendar/{token}.ics", h.CalendarFeed)
+	r.Get("/email/confirm", h.ConfirmEmail)
+	r.Get("/web-push/public-key", h.WebPushPublicKey)
 
 	r.Group(func(r chi.Router) {
 		r.Use(middleware.OptionalAuthMiddleware(cfg.JWTSecret))
 		r.Post("/logs/client", h.ClientLogs)
+		r.Get("/events/{id}/calendar.ics", h.EventCalendar)
 	})
 
 	r.Group(func(r chi.Router) {
@@ -112,12 +122,31 @@ func main() {
 		r.Get("/me", h.Me)
 		r.Post("/me/location", h.UpdateLocation)
 		r.Post("/me/push-token", h.UpsertPushToken)
+		r.Post("/me/web-push", h.UpsertWebPushSubscription)
+		r.Delete("/me/web-push", h.DeleteWebPushSubscription)
+		r.Get("/me/calendar", h.CalendarFeedInfo)
+		r.Post("/me/contacts", h.UploadContacts)
+		r.Delete("/me/contacts", h.ClearContacts)
+		r.Post("/me/phone", h.RequestPhoneVerification)
+		r.Get("/me/friends", h.ListFriends)
+		r.Get("/me/following", h.ListFollowing)
+		r.Get("/me/searches", h.ListSavedSearches)
+		r.Post("/me/searches", h.CreateSavedSearch)
+		r.Patch("/me/searches/{id}", h.UpdateSavedSearch)
+		r.Delete("/me/searches/{id}", h.DeleteSavedSearch)
+		r.Get("/me/notifications", h.GetNotificationSettings)
+		r.Patch("/me/notifications", h.UpdateNotificationSettings)
+		r.Get("/me/privacy", h.GetPrivacySettings)
+		r.Patch("/me/privacy", h.UpdatePrivacySettings)
+		r.Post("/me/calendar/rotate", h.RotateCalendarFeed)
 		r.Get("/referrals/my-code", h.ReferralCode)
 		r.Post("/referrals/claim", h.ClaimReferral)
-		r.Post("/events", h.CreateEvent)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events", h.CreateEvent)
 		r.Get("/events/mine", h.MyEvents)
 		r.Get("/events/nearby", h.NearbyEvents)
 		r.Get("/events/feed", h.Feed)
+		r.Get("/events/friends", h.FriendsEvents)
+		r.Get("/events/search", h.SearchEvents)
 		r.Get("/events/{id}", h.GetEvent)
 		r.Get("/events/{id}/products", h.ListEventProducts)
 		r.Post("/events/{id}/join", h.JoinEvent)
@@ -125,15 +154,33 @@ func main() {
 		r.Post("/events/{id}/like", h.LikeEvent)
 		r.Delete("/events/{id}/like", h.UnlikeEvent)
 		r.Get("/events/{id}/comments", h.ListEventComments)
-		r.Post("/events/{id}/comments", h.AddEventComment)
-		r.Post("/events/{id}/promote", h.PromoteEvent)
+		r.With(middleware.RequireNoBan(models.BanScopeComments)).Post("/events/{id}/comments", h.AddEventComment)
+		r.With(middleware.RequireNoBan(models.BanScopeComments)).Patch("/comments/{id}", h.EditEventComment)
+		r.With(middleware.RequireNoBan(models.BanScopeComments)).Post("/comments/{id}/reactions", h.AddCommentReaction)
+		r.With(middleware.RequireNoBan(models.BanScopeComments)).Delete("/comments/{id}/reactions", h.RemoveCommentReaction)
+		r.Get("/events/{id}/reviews", h.ListEventReviews)
+		r.With(middleware.RequireNoBan(models.BanScopeComments)).Post("/events/{id}/reviews", h.ReviewEvent)
+		r.Post("/events/{id}/report", h.ReportEvent)
+		r.Post("/comments/{id}/report", h.ReportComment)
+		r.Get("/users/{id}", h.GetUserProfile)
+		r.Post("/users/{id}/follow", h.FollowUser)
+		r.Delete("/users/{id}/follow", h.UnfollowUser)
+		r.Post("/users/{id}/report", h.ReportUser)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events/{id}/promote", h.PromoteEvent)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events/{id}/publish", h.PublishEvent)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events/{id}/cancel", h.CancelEvent)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events/{id}/reschedule", h.RescheduleEvent)
+		r.Get("/events/{id}/staff", h.ListEventStaff)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/events/{id}/staff", h.AddEventStaff)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Delete("/events/{id}/staff/{userId}", h.RemoveEventStaff)
+		r.Post("/events/{id}/participants/{userId}/check-in", h.CheckInParticipant)
 		r.Post("/media/presign", h.PresignMedia)
 		r.Post("/media/upload", h.UploadMedia)
-		r.Post("/wallet/topup/token", h.TopupToken)
-		r.Post("/wallet/topup/card", h.TopupCard)
+		r.With(middleware.RequireNoBan(models.BanScopePurchases)).Post("/wallet/topup/token", h.TopupToken)
+		r.With(middleware.RequireNoBan(models.BanScopePurchases)).Post("/wallet/topup/card", h.TopupCard)
 		r.Get("/payments/settings", h.GetPaymentSettings)
-		r.Post("/orders", h.CreateOrder)
-		r.Post("/payments/sbp/qr/create", h.CreateSBPQRCodePayment)
+		r.With(middleware.RequireNoBan(models.BanScopePurchases)).Post("/orders", h.CreateOrder)
+		r.With(middleware.RequireNoBan(models.BanScopePurchases)).Post("/payments/sbp/qr/create", h.CreateSBPQRCodePayment)
 		r.Get("/payments/sbp/qr/{orderId}/status", h.GetSBPQRCodePaymentStatus)
 		r.Get("/orders/my", h.ListMyOrders)
 		r.Get("/tickets/my", h.ListMyTickets)
@@ -145,10 +192,13 @@ func main() {
 		r.Get("/admin/users/{id}", h.GetAdminUser)
 		r.Post("/admin/users/{id}/block", h.BlockUser)
 		r.Post("/admin/users/{id}/unblock", h.UnblockUser)
+		r.Post("/admin/users/{id}/bans", h.CreateAdminUserBan)
+		r.Post("/admin/users/{id}/bans/{banId}/lift", h.LiftAdminUserBan)
 		r.Post("/admin/broadcasts", h.CreateBroadcast)
 		r.Post("/admin/broadcasts/{id}/start", h.StartBroadcast)
 		r.Get("/admin/broadcasts", h.ListBroadcasts)
 		r.Get("/admin/broadcasts/{id}", h.GetBroadcast)
+		r.Get("/admin/notifications", h.ListAdminNotifications)
 		r.Get("/admin/parser/sources", h.ListParserSources)
 		r.Post("/admin/parser/sources", h.CreateParserSource)
 		r.Patch("/admin/parser/sources/{id}", h.UpdateParserSource)
@@ -161,14 +211,24 @@ func main() {
 		r.Delete("/admin/parser/events/{id}", h.DeleteParsedEvent)
 		r.Post("/admin/events/{id}/hide", h.HideEvent)
 		r.Post("/admin/events/{id}/landing", h.SetEventLandingPublished)
+		r.Get("/admin/events/{id}/announcement-preview", h.PreviewEventAnnouncement)
 		r.Post("/admin/landing/content", h.UpsertLandingContent)
-		r.Patch("/admin/events/{id}", h.UpdateEventAdmin)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Patch("/admin/events/{id}", h.UpdateEventAdmin)
 		r.Delete("/admin/events/{id}", h.DeleteEventAdmin)
 		r.Delete("/admin/comments/{id}", h.DeleteEventCommentAdmin)
+		r.Get("/admin/reviews", h.ListAdminReviews)
+		r.Post("/admin/reviews/{id}/moderate", h.ModerateReviewAdmin)
+		r.Delete("/admin/reviews/{id}", h.DeleteReviewAdmin)
+		r.Get("/admin/reports", h.ListAdminReports)
+		r.Post("/admin/reports/{type}/{id}/resolve", h.ResolveAdminReport)
+		r.Get("/admin/content-filter", h.ListAdminContentFilter)
+		r.Post("/admin/content-filter/{id}/resolve", h.ResolveAdminContentFilter)
 		r.Get("/admin/orders", h.ListAdminOrders)
 		r.Get("/admin/orders/{id}", h.GetAdminOrder)
 		r.Post("/admin/orders/{orderId}/confirm", h.ConfirmOrder)
 		r.Delete("/admin/orders/{id}", h.DeleteAdminOrder)
+		r.Get("/admin/refunds", h.ListAdminRefunds)
+		r.Post("/admin/refunds/{orderId}/resolve", h.ResolveAdminRefund)
 		r.Get("/admin/bot/messages", h.ListAdminBotMessages)
 		r.Post("/admin/bot/messages/reply", h.ReplyAdminBotMessage)
 		r.Post("/admin/tickets/redeem", h.AdminRedeemTicket)
@@ -187,6 +247,20 @@ func main() {
 		r.Post("/admin/promo-codes", h.CreateAdminPromoCode)
 		r.Patch("/admin/promo-codes/{id}", h.PatchAdminPromoCode)
 		r.Delete("/admin/promo-codes/{id}", h.DeleteAdminPromoCode)
+		r.Get("/organizer/orders", h.ListOrganizerOrders)
+		r.Get("/organizer/stats", h.OrganizerStats)
+		r.Get("/organizer/products/tickets", h.ListOrganizerTicketProducts)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/organizer/products/tickets", h.CreateOrganizerTicketProduct)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Patch("/organizer/products/tickets/{id}", h.PatchOrganizerTicketProduct)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Delete("/organizer/products/tickets/{id}", h.DeleteOrganizerTicketProduct)
+		r.Get("/organizer/products/transfers", h.ListOrganizerTransferProducts)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/organizer/products/transfers", h.CreateOrganizerTransferProduct)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Patch("/organizer/products/transfers/{id}", h.PatchOrganizerTransferProduct)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Delete("/organizer/products/transfers/{id}", h.DeleteOrganizerTransferProduct)
+		r.Get("/organizer/promo-codes", h.ListOrganizerPromoCodes)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Post("/organizer/promo-codes", h.CreateOrganizerPromoCode)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Patch("/organizer/promo-codes/{id}", h.PatchOrganizerPromoCode)
+		r.With(middleware.RequireNoBan(models.BanScopeEvents)).Delete("/organizer/promo-codes/{id}", h.DeleteOrganizerPromoCode)
 	})
 
 	srv := &http.Server{
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/http/handlers"
+	"gigme/backend/internal/integrations"
+)
+
+// main is the executable entry point.
+func main() {
+	urlFlag := flag.String("url", "", "webhook url (default TELEGRAM_WEBHOOK_URL or API_PUBLIC_URL + /telegram/webhook)")
+	dropPendingFlag := flag.Bool("drop-pending", false, "drop updates Telegram has queued for the bot")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "config error: %v\n", err)
+		os.Exit(2)
+	}
+	if cfg.Webhook.Secret == "" {
+		fmt.Fprintln(os.Stderr, "TELEGRAM_WEBHOOK_SECRET is required")
+		os.Exit(2)
+	}
+	webhookURL, err := resolveWebhookURL(*urlFlag, cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	telegram := integrations.NewTelegramClient(cfg.TelegramToken)
+	if err := telegram.SetWebhook(webhookURL, cfg.Webhook.Secret, handlers.TelegramAllowedUpdates, *dropPendingFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "setWebhook error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("webhook set to %s (updates: %s)\n", webhookURL, strings.Join(handlers.TelegramAllowedUpdates, ", "))
+}
+
+// resolveWebhookURL picks the webhook url from the flag or the config and
+// checks it is an https url as Telegram requires.
+func resolveWebhookURL(flagValue string, cfg *config.Config) (string, error) {
+	webhookURL := strings.TrimSpace(flagValue)
+	if webhookURL == "" {
+		webhookURL = cfg.Webhook.URL
+	}
+	if webhookURL == "" && strings.TrimSpace(cfg.APIPublicURL) != "" {
+		webhookURL = strings.TrimRight(strings.TrimSpace(cfg.APIPublicURL), "/") + "/telegram/webhook"
+	}
+	if webhookURL == "" {
+		return "", fmt.Errorf("webhook url is required: pass -url or set TELEGRAM_WEBHOOK_URL")
+	}
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return "", fmt.Errorf("webhook url must be an https url: %s", webhookURL)
+	}
+	return webhookURL, nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gigme/backend/internal/repository"
+)
+
+const liftExpiredBansInterval = time.Minute
+
+// liftExpiredBans lifts bans whose expiry time has passed.
+func liftExpiredBans(ctx context.Context, repo *repository.Repository, now time.Time, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	lifted, err := repo.LiftExpiredBans(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	if lifted > 0 {
+		logger.Info("expired_bans_lifted", "count", lifted)
+	}
+	return lifted, nil
+}
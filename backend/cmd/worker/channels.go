@@ -0,0 +1,373 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/integrations"
+	"gigme/backend/internal/integrations/push"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+// deliveryChannel delivers a rendered notification over one channel. Deliver
+// returns a skipError when the recipient can't be reached over the channel.
+type deliveryChannel interface {
+	Name() string
+	Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error
+}
+
+// skipError reports that a channel has no way to reach the recipient.
+type skipError struct {
+	reason string
+}
+
+// Error handles internal error behavior.
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// skipChannel returns a skipError with the reason.
+func skipChannel(reason string) error {
+	return &skipError{reason: reason}
+}
+
+// notificationRouter delivers notifications over the configured channels.
+type notificationRouter struct {
+	channels map[string]deliveryChannel
+	now      func() time.Time
+}
+
+// newNotificationRouter builds the channels enabled by the config. Telegram is
+// always enabled; the rest need their credentials.
+func newNotificationRouter(cfg *config.Config, repo *repository.Repository, telegram TelegramSender, logger *slog.Logger) *notificationRouter {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	channels := []deliveryChannel{&telegramChannel{sender: telegram, store: repo}}
+	if pusher := newPushNotifier(cfg.Push, logger); pusher != nil {
+		channels = append(channels, &pushChannel{notifier: pusher, store: repo})
+	}
+	if cfg.Push.WebPushKeyFile != "" {
+		key, err := os.ReadFile(cfg.Push.WebPushKeyFile)
+		if err == nil {
+			var sender *push.WebPushSender
+			sender, err = push.NewWebPushSender(push.WebPushConfig{PrivateKey: string(key), Subject: cfg.Push.WebPushSubject}, nil)
+			if err == nil {
+				channels = append(channels, &webPushChannel{sender: sender, store: repo})
+			}
+		}
+		if err != nil {
+			logger.Warn("web_push_disabled", "error", err)
+		}
+	}
+	if cfg.VKGroupToken != "" {
+		channels = append(channels, &vkChannel{messenger: integrations.NewVKMessenger(cfg.VKGroupToken)})
+	}
+	if cfg.SMTP.Host != "" && cfg.SMTP.From != "" {
+		channels = append(channels, &emailChannel{mailer: integrations.NewMailer(integrations.SMTPConfig{
+			Host:     cfg.SMTP.Host,
+			Port:     cfg.SMTP.Port,
+			Username: cfg.SMTP.Username,
+			Password: cfg.SMTP.Password,
+			From:     cfg.SMTP.From,
+		})})
+	}
+	router := newRouter(channels...)
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		names = append(names, channel.Name())
+	}
+	logger.Info("notification_channels", "channels", names)
+	return router
+}
+
+// newRouter creates a router over channels.
+func newRouter(channels ...deliveryChannel) *notificationRouter {
+	byName := make(map[string]deliveryChannel, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+	return &notificationRouter{channels: byName, now: time.Now}
+}
+
+// route returns the channels to try for a recipient: their preferred channels
+// first, then the rest in the default order.
+func (r *notificationRouter) route(recipient repository.NotificationRecipient) []deliveryChannel {
+	order := make([]string, 0, len(models.NotificationChannels))
+	for _, name := range recipient.Channels {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	for _, name := range models.NotificationChannels {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	out := make([]deliveryChannel, 0, len(order))
+	for _, name := range order {
+		if channel, ok := r.channels[name]; ok {
+			out = append(out, channel)
+		}
+	}
+	return out
+}
+
+// deliver tries channels in routing order until one delivers the message. It
+// returns the result of every channel tried, whether the message was
+// delivered and the last delivery error; the error is nil when no channel
+// could reach the recipient at all.
+func (r *notificationRouter) deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage, logger *slog.Logger) ([]models.NotificationDelivery, bool, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	deliveries := make([]models.NotificationDelivery, 0, len(r.channels))
+	var lastErr error
+	for _, channel := range r.route(recipient) {
+		err := channel.Deliver(ctx, recipient, job, message)
+		delivery := models.NotificationDelivery{Channel: channel.Name(), Status: models.DeliveryStatusSent, At: r.now()}
+		var skip *skipError
+		switch {
+		case err == nil:
+			deliveries = append(deliveries, delivery)
+			return deliveries, true, nil
+		case errors.As(err, &skip):
+			delivery.Status = models.DeliveryStatusSkipped
+			delivery.Error = skip.reason
+		default:
+			delivery.Status = models.DeliveryStatusFailed
+			delivery.Error = truncateRunes(err.Error(), 500)
+			lastErr = err
+			logger.Warn("job_channel_failed", "job_id", job.ID, "user_id", job.UserID, "channel", channel.Name(), "error", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, false, lastErr
+}
+
+// telegramUnreachableStore records users the bot can't message.
+type telegramUnreachableStore interface {
+	MarkTelegramUnreachable(ctx context.Context, userID int64, reason string) error
+}
+
+// telegramChannel delivers notifications as Telegram bot messages.
+type telegramChannel struct {
+	sender TelegramSender
+	store  telegramUnreachableStore
+}
+
+// Name returns the channel name.
+func (c *telegramChannel) Name() string {
+	return models.NotificationChannelTelegram
+}
+
+// Deliver sends the message with the first photo Telegram accepts, falling
+// back to plain text. A user who blocked the bot is marked unreachable and
+// skipped from then on.
+func (c *telegramChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	if recipient.TelegramID <= 0 {
+		return skipChannel("no telegram account")
+	}
+	if recipient.TelegramUnreachable {
+		return skipChannel("telegram unreachable")
+	}
+	markup := notificationMarkup(job, message)
+	photoCaption := truncateRunes(message.Text, 1024)
+	if photoCaption == "" {
+		photoCaption = message.Text
+	}
+	for _, photoURL := range message.PhotoURLs {
+		err := c.sender.SendPhotoWithMarkup(recipient.TelegramID, photoURL, photoCaption, markup)
+		if err == nil {
+			return nil
+		}
+		if integrations.IsTelegramUnreachable(err) {
+			return c.unreachable(ctx, recipient.UserID, err)
+		}
+	}
+	err := c.sender.SendMessageWithMarkup(recipient.TelegramID, message.Text, markup)
+	if integrations.IsTelegramUnreachable(err) {
+		return c.unreachable(ctx, recipient.UserID, err)
+	}
+	return err
+}
+
+// unreachable marks the user unreachable on Telegram and skips the channel.
+func (c *telegramChannel) unreachable(ctx context.Context, userID int64, sendErr error) error {
+	if c.store != nil {
+		if err := c.store.MarkTelegramUnreachable(ctx, userID, sendErr.Error()); err != nil {
+			return err
+		}
+	}
+	return skipChannel("telegram unreachable: " + sendErr.Error())
+}
+
+// notificationMarkup builds the inline keyboard of a Telegram notification:
+// the primary web app button, extra buttons and the mute button.
+func notificationMarkup(job models.NotificationJob, message notificationMessage) *integrations.ReplyMarkup {
+	var keyboard [][]integrations.InlineKeyboardButton
+	if message.ButtonURL != "" {
+		keyboard = append(keyboard, []integrations.InlineKeyboardButton{{
+			Text:   message.ButtonText,
+			WebApp: &integrations.WebAppInfo{URL: message.ButtonURL},
+		}})
+	}
+	for _, button := range message.Buttons {
+		if button.URL == "" {
+			continue
+		}
+		keyboard = append(keyboard, []integrations.InlineKeyboardButton{{
+			Text:   button.Text,
+			WebApp: &integrations.WebAppInfo{URL: button.URL},
+		}})
+	}
+	if row := muteButtonRow(job.Kind); row != nil {
+		keyboard = append(keyboard, row)
+	}
+	if len(keyboard) == 0 {
+		return nil
+	}
+	return &integrations.ReplyMarkup{InlineKeyboard: keyboard}
+}
+
+// pushChannel delivers notifications to mobile push tokens.
+type pushChannel struct {
+	notifier *pushNotifier
+	store    pushTokenStore
+}
+
+// Name returns the channel name.
+func (c *pushChannel) Name() string {
+	return models.NotificationChannelPush
+}
+
+// Deliver sends the message to every active token of the recipient.
+func (c *pushChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	sent, err := c.notifier.deliver(ctx, c.store, recipient.UserID, pushMessage(job, message), nil)
+	switch {
+	case sent > 0:
+		return nil
+	case err != nil:
+		return err
+	default:
+		return skipChannel("no active push tokens")
+	}
+}
+
+// webPushStore is the part of the repository Web Push delivery needs.
+type webPushStore interface {
+	ListActiveWebPushSubscriptions(ctx context.Context, userID int64) ([]models.WebPushSubscription, error)
+	DeactivateWebPushSubscription(ctx context.Context, endpoint string) error
+}
+
+// webPushChannel delivers notifications to browser push subscriptions.
+type webPushChannel struct {
+	sender *push.WebPushSender
+	store  webPushStore
+}
+
+// Name returns the channel name.
+func (c *webPushChannel) Name() string {
+	return models.NotificationChannelWebPush
+}
+
+// Deliver sends the message to every active subscription of the recipient and
+// deactivates subscriptions the push service reports as gone.
+func (c *webPushChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	subs, err := c.store.ListActiveWebPushSubscriptions(ctx, recipient.UserID)
+	if err != nil {
+		return err
+	}
+	msg := pushMessage(job, message)
+	sent := 0
+	var lastErr error
+	for _, sub := range subs {
+		err := c.sender.Send(ctx, push.WebPushSubscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, msg)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, push.ErrUnregistered):
+			if err := c.store.DeactivateWebPushSubscription(ctx, sub.Endpoint); err != nil {
+				lastErr = err
+			}
+		default:
+			lastErr = err
+		}
+	}
+	switch {
+	case sent > 0:
+		return nil
+	case lastErr != nil:
+		return lastErr
+	default:
+		return skipChannel("no active web push subscriptions")
+	}
+}
+
+// vkChannel delivers notifications as messages of the VK community.
+type vkChannel struct {
+	messenger *integrations.VKMessenger
+}
+
+// Name returns the channel name.
+func (c *vkChannel) Name() string {
+	return models.NotificationChannelVK
+}
+
+// Deliver sends the message to VK users, whose telegram id holds the negated VK id.
+func (c *vkChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	if recipient.TelegramID >= 0 {
+		return skipChannel("no vk account")
+	}
+	err := c.messenger.SendMessage(ctx, -recipient.TelegramID, plainTextWithLinks(message), job.ID)
+	var apiErr *integrations.VKAPIError
+	if errors.As(err, &apiErr) && apiErr.Code == integrations.VKMessagesDenied {
+		return skipChannel("vk messages not allowed by user")
+	}
+	return err
+}
+
+// emailChannel delivers notifications by email.
+type emailChannel struct {
+	mailer *integrations.Mailer
+}
+
+// Name returns the channel name.
+func (c *emailChannel) Name() string {
+	return models.NotificationChannelEmail
+}
+
+// Deliver emails the message with its first line as the subject.
+func (c *emailChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	if recipient.Email == "" {
+		return skipChannel("no email address")
+	}
+	subject, _, _ := strings.Cut(message.Text, "\n")
+	return c.mailer.Send(ctx, recipient.Email, truncateRunes(subject, 120), plainTextWithLinks(message))
+}
+
+// plainTextWithLinks renders a message for channels without buttons, listing
+// button links after the text.
+func plainTextWithLinks(message notificationMessage) string {
+	lines := []string{message.Text}
+	if message.ButtonURL != "" {
+		lines = append(lines, "", fmt.Sprintf("%s: %s", message.ButtonText, message.ButtonURL))
+	}
+	if len(message.Buttons) > 0 && message.ButtonURL == "" {
+		lines = append(lines, "")
+	}
+	for _, button := range message.Buttons {
+		if button.URL != "" {
+			lines = append(lines, fmt.Sprintf("%s: %s", button.Text, button.URL))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/integrations"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+// fakeChannel returns a fixed result and records calls.
+type fakeChannel struct {
+	name  string
+	err   error
+	calls int
+}
+
+// Name returns the channel name.
+func (c *fakeChannel) Name() string {
+	return c.name
+}
+
+// Deliver returns the configured error.
+func (c *fakeChannel) Deliver(ctx context.Context, recipient repository.NotificationRecipient, job models.NotificationJob, message notificationMessage) error {
+	c.calls++
+	return c.err
+}
+
+// fakeTelegramSender records sent messages and fails photos.
+type fakeTelegramSender struct {
+	chatIDs []int64
+	markup  *integrations.ReplyMarkup
+	err     error
+}
+
+// SendMessageWithMarkup records the message.
+func (s *fakeTelegramSender) SendMessageWithMarkup(chatID int64, text string, markup *integrations.ReplyMarkup) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.chatIDs = append(s.chatIDs, chatID)
+	s.markup = markup
+	return nil
+}
+
+// SendPhotoWithMarkup fails so the text fallback is used.
+func (s *fakeTelegramSender) SendPhotoWithMarkup(chatID int64, photoURL, caption string, markup *integrations.ReplyMarkup) error {
+	return errors.New("bad photo")
+}
+
+// fakeUnreachableStore records users marked unreachable on Telegram.
+type fakeUnreachableStore struct {
+	userIDs []int64
+}
+
+// MarkTelegramUnreachable records the user.
+func (s *fakeUnreachableStore) MarkTelegramUnreachable(ctx context.Context, userID int64, reason string) error {
+	s.userIDs = append(s.userIDs, userID)
+	return nil
+}
+
+// TestNotificationRouterRoute verifies notification router route behavior.
+func TestNotificationRouterRoute(t *testing.T) {
+	router := newRouter(
+		&fakeChannel{name: models.NotificationChannelEmail},
+		&fakeChannel{name: models.NotificationChannelTelegram},
+		&fakeChannel{name: models.NotificationChannelPush},
+	)
+	var names []string
+	for _, channel := range router.route(repository.NotificationRecipient{Channels: []string{"email", "vk"}}) {
+		names = append(names, channel.Name())
+	}
+	want := []string{"email", "telegram", "push"}
+	if len(names) != len(want) {
+		t.Fatalf("unexpected route: %v", names)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("unexpected route: %v", names)
+		}
+	}
+}
+
+// TestNotificationRouterDeliverFallback verifies notification router deliver fallback behavior.
+func TestNotificationRouterDeliverFallback(t *testing.T) {
+	telegram := &fakeChannel{name: models.NotificationChannelTelegram, err: skipChannel("no telegram account")}
+	pushCh := &fakeChannel{name: models.NotificationChannelPush, err: errors.New("fcm down")}
+	email := &fakeChannel{name: models.NotificationChannelEmail}
+	router := newRouter(telegram, pushCh, email)
+	at := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	router.now = func() time.Time { return at }
+
+	deliveries, delivered, err := router.deliver(context.Background(), repository.NotificationRecipient{UserID: 1}, models.NotificationJob{ID: 5}, notificationMessage{Text: "hi"}, nil)
+	if !delivered || err != nil {
+		t.Fatalf("expected delivery, got delivered=%v err=%v", delivered, err)
+	}
+	if len(deliveries) != 3 {
+		t.Fatalf("unexpected deliveries: %+v", deliveries)
+	}
+	if deliveries[0].Status != models.DeliveryStatusSkipped || deliveries[0].Error != "no telegram account" {
+		t.Fatalf("unexpected telegram delivery: %+v", deliveries[0])
+	}
+	if deliveries[1].Status != models.DeliveryStatusFailed || deliveries[1].Error != "fcm down" {
+		t.Fatalf("unexpected push delivery: %+v", deliveries[1])
+	}
+	if deliveries[2].Channel != "email" || deliveries[2].Status != models.DeliveryStatusSent || !deliveries[2].At.Equal(at) {
+		t.Fatalf("unexpected email delivery: %+v", deliveries[2])
+	}
+
+	email.err = skipChannel("no email address")
+	_, delivered, err = router.deliver(context.Background(), repository.NotificationRecipient{UserID: 1}, models.NotificationJob{ID: 5}, notificationMessage{Text: "hi"}, nil)
+	if delivered || err == nil || err.Error() != "fcm down" {
+		t.Fatalf("expected push failure, got delivered=%v err=%v", delivered, err)
+	}
+
+	pushCh.err = skipChannel("no active push tokens")
+	deliveries, delivered, err = router.deliver(context.Background(), repository.NotificationRecipient{UserID: 1}, models.NotificationJob{ID: 5}, notificationMessage{Text: "hi"}, nil)
+	if delivered || err != nil || len(deliveries) != 3 {
+		t.Fatalf("expected unreachable recipient, got delivered=%v err=%v deliveries=%+v", delivered, err, deliveries)
+	}
+}
+
+// TestDeliveryOutcome verifies delivery outcome behavior.
+func TestDeliveryOutcome(t *testing.T) {
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	job := models.NotificationJob{Attempts: 1}
+
+	if status, attempts, lastError, next := deliveryOutcome(job, true, nil, now); status != "sent" || attempts != 1 || lastError != "" || next != nil {
+		t.Fatalf("unexpected sent outcome: %s %d %q %v", status, attempts, lastError, next)
+	}
+	if status, attempts, lastError, next := deliveryOutcome(job, false, nil, now); status != "failed" || attempts != 2 || lastError != "no delivery channel" || next != nil {
+		t.Fatalf("unexpected unreachable outcome: %s %d %q %v", status, attempts, lastError, next)
+	}
+	status, attempts, lastError, next := deliveryOutcome(job, false, errors.New("timeout"), now)
+	if status != "pending" || attempts != 2 || lastError != "timeout" || next == nil || !next.Equal(now.Add(4*time.Minute)) {
+		t.Fatalf("unexpected retry outcome: %s %d %q %v", status, attempts, lastError, next)
+	}
+	if status, _, _, next := deliveryOutcome(models.NotificationJob{Attempts: 2}, false, errors.New("timeout"), now); status != "failed" || next != nil {
+		t.Fatalf("expected final failure, got %s %v", status, next)
+	}
+	flood := &integrations.TelegramAPIError{Method: "sendMessage", StatusCode: 429, RetryAfter: 10 * time.Minute}
+	if _, _, _, next := deliveryOutcome(job, false, flood, now); next == nil || !next.Equal(now.Add(10*time.Minute)) {
+		t.Fatalf("expected retry after the flood limit, got %v", next)
+	}
+}
+
+// TestTelegramChannelDeliver verifies telegram channel deliver behavior.
+func TestTelegramChannelDeliver(t *testing.T) {
+	sender := &fakeTelegramSender{}
+	channel := &telegramChannel{sender: sender}
+	job := models.NotificationJob{ID: 1, Kind: "event_created"}
+	message := notificationMessage{
+		Text:       "Новое событие",
+		PhotoURLs:  []string{"https://example.com/photo.jpg"},
+		ButtonURL:  "https://spacefestival.fun/space_app?eventId=9",
+		ButtonText: "Открыть",
+	}
+
+	var skip *skipError
+	if err := channel.Deliver(context.Background(), repository.NotificationRecipient{TelegramID: -42}, job, message); !errors.As(err, &skip) {
+		t.Fatalf("expected skip for vk user, got %v", err)
+	}
+	if err := channel.Deliver(context.Background(), repository.NotificationRecipient{TelegramID: 42}, job, message); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if len(sender.chatIDs) != 1 || sender.chatIDs[0] != 42 {
+		t.Fatalf("unexpected chats: %v", sender.chatIDs)
+	}
+	if sender.markup == nil || len(sender.markup.InlineKeyboard) != 2 || sender.markup.InlineKeyboard[0][0].WebApp == nil {
+		t.Fatalf("unexpected markup: %+v", sender.markup)
+	}
+}
+
+// TestPlainTextWithLinks verifies plain text with links behavior.
+func TestPlainTextWithLinks(t *testing.T) {
+	got := plainTextWithLinks(notificationMessage{
+		Text:       "Дайджест",
+		ButtonURL:  "https://spacefestival.fun/space_app",
+		ButtonText: "Открыть",
+		Buttons:    []notificationButton{{Text: "Rave", URL: "https://spacefestival.fun/space_app?eventId=9"}, {Text: "Empty"}},
+	})
+	want := "Дайджест\n\nОткрыть: https://spacefestival.fun/space_app\nRave: https://spacefestival.fun/space_app?eventId=9"
+	if got != want {
+		t.Fatalf("unexpected text:\n%s", got)
+	}
+	if got := plainTextWithLinks(notificationMessage{Text: "Спасибо!"}); got != "Спасибо!" {
+		t.Fatalf("unexpected plain text: %q", got)
+	}
+}
+
+// TestTelegramChannelMarksUnreachable verifies telegram channel marks unreachable behavior.
+func TestTelegramChannelMarksUnreachable(t *testing.T) {
+	sender := &fakeTelegramSender{err: &integrations.TelegramAPIError{
+		Method:      "sendMessage",
+		StatusCode:  403,
+		Description: "Forbidden: bot was blocked by the user",
+	}}
+	store := &fakeUnreachableStore{}
+	channel := &telegramChannel{sender: sender, store: store}
+	recipient := repository.NotificationRecipient{UserID: 7, TelegramID: 42}
+
+	var skip *skipError
+	err := channel.Deliver(context.Background(), recipient, models.NotificationJob{ID: 1}, notificationMessage{Text: "hi"})
+	if !errors.As(err, &skip) || len(store.userIDs) != 1 || store.userIDs[0] != 7 {
+		t.Fatalf("expected blocked user to be skipped and marked, got %v %v", err, store.userIDs)
+	}
+
+	sender.err = nil
+	recipient.TelegramUnreachable = true
+	if err := channel.Deliver(context.Background(), recipient, models.NotificationJob{ID: 1}, notificationMessage{Text: "hi"}); !errors.As(err, &skip) || len(sender.chatIDs) != 0 {
+		t.Fatalf("expected unreachable user to be skipped without sending, got %v %v", err, sender.chatIDs)
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+const (
+	digestInterval         = 5 * time.Minute
+	digestHorizon          = 7 * 24 * time.Hour
+	maxDigestUsersPerRun   = 200
+	maxDigestTitleRunes    = 80
+	maxDigestButtonRunes   = 40
+	defaultDigestEventSize = 8
+)
+
+// digestOptions builds weekly digest selection options from the config.
+func digestOptions(cfg *config.Config) repository.DigestOptions {
+	size := cfg.Digest.Size
+	if size <= 0 {
+		size = defaultDigestEventSize
+	}
+	radius := 0
+	if cfg.Digest.RadiusKm > 0 {
+		radius = int(cfg.Digest.RadiusKm * 1000)
+	}
+	ranking := cfg.FeedRanking
+	return repository.DigestOptions{
+		Ranking: repository.FeedRanking{
+			DistanceWeight:   ranking.DistanceWeight,
+			TimeWeight:       ranking.TimeWeight,
+			PopularityWeight: ranking.PopularityWeight,
+			AffinityWeight:   ranking.AffinityWeight,
+			DistanceScaleKm:  ranking.DistanceScaleKm,
+			TimeScaleHours:   ranking.TimeScaleHours,
+		},
+		SocialWeight: cfg.Digest.SocialWeight,
+		RadiusMeters: radius,
+		Horizon:      digestHorizon,
+		Size:         size,
+	}
+}
+
+// enqueueWeeklyDigests enqueues weekly digests of users whose digest time has
+// come. Users without matching events are marked as served without a job so
+// they are not picked again until next week.
+func enqueueWeeklyDigests(ctx context.Context, repo *repository.Repository, opts repository.DigestOptions, apiBaseURL string, now time.Time, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	userIDs, err := repo.ListDueDigestUsers(ctx, now, maxDigestUsersPerRun)
+	if err != nil {
+		return 0, err
+	}
+	enqueued := 0
+	for _, userID := range userIDs {
+		events, err := repo.ListDigestEvents(ctx, userID, now, opts)
+		if err != nil {
+			logger.Warn("digest_events_failed", "user_id", userID, "error", err)
+			continue
+		}
+		payload := digestPayload(events, apiBaseURL)
+		if err := repo.EnqueueWeeklyDigest(ctx, userID, now, payload); err != nil {
+			logger.Warn("digest_enqueue_failed", "user_id", userID, "error", err)
+			continue
+		}
+		if payload != nil {
+			enqueued++
+		}
+	}
+	if len(userIDs) > 0 {
+		logger.Info("weekly_digests_enqueued", "due", len(userIDs), "enqueued", enqueued)
+	}
+	return enqueued, nil
+}
+
+// digestPayload builds the weekly_digest job payload, or nil when there are no events.
+func digestPayload(events []models.Event, apiBaseURL string) map[string]interface{} {
+	if len(events) == 0 {
+		return nil
+	}
+	items := make([]interface{}, 0, len(events))
+	for _, event := range events {
+		item := map[string]interface{}{
+			"eventId":  event.ID,
+			"title":    event.Title,
+			"startsAt": event.StartsAt.Format(time.RFC3339),
+		}
+		if event.AddressLabel != "" {
+			item["addressLabel"] = event.AddressLabel
+		}
+		items = append(items, item)
+	}
+	payload := map[string]interface{}{"events": items}
+	if apiBaseURL = strings.TrimSpace(apiBaseURL); apiBaseURL != "" {
+		payload["apiBaseUrl"] = apiBaseURL
+	}
+	return payload
+}
+
+// buildDigestNotification renders a weekly digest as a numbered list with a
+// button to each event.
+func buildDigestNotification(job models.NotificationJob, baseURL string) notificationMessage {
+	rawEvents, _ := job.Payload["events"].([]interface{})
+	lines := []string{"Подборка событий на неделю"}
+	buttons := make([]notificationButton, 0, len(rawEvents))
+	for _, raw := range rawEvents {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		title := strings.TrimSpace(payloadString(item, "title"))
+		if title == "" {
+			continue
+		}
+		number := len(buttons) + 1
+		line := fmt.Sprintf("%d. %s", number, truncateRunes(title, maxDigestTitleRunes))
+		if startsAt := formatStartsAt(payloadString(item, "startsAt")); startsAt != "" {
+			line += " — " + startsAt
+		}
+		if address := strings.TrimSpace(payloadString(item, "addressLabel")); address != "" {
+			line += "\n   " + truncateRunes(address, maxDigestTitleRunes)
+		}
+		lines = append(lines, line)
+		buttons = append(buttons, notificationButton{
+			Text: fmt.Sprintf("%d. %s", number, truncateRunes(title, maxDigestButtonRunes)),
+			URL:  buildEventURL(baseURL, payloadInt64(item, "eventId")),
+		})
+	}
+	if len(buttons) == 0 {
+		return notificationMessage{}
+	}
+	return notificationMessage{
+		Text:    strings.Join(lines, "\n"),
+		Buttons: buttons,
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/models"
+)
+
+// TestDigestPayload verifies digest payload behavior.
+func TestDigestPayload(t *testing.T) {
+	if payload := digestPayload(nil, "https://api.example.com"); payload != nil {
+		t.Fatalf("expected nil payload, got %v", payload)
+	}
+	startsAt := time.Date(2026, 3, 14, 19, 0, 0, 0, time.UTC)
+	payload := digestPayload([]models.Event{
+		{ID: 5, Title: "Jazz", StartsAt: startsAt, AddressLabel: "Club"},
+		{ID: 6, Title: "Lecture", StartsAt: startsAt.Add(24 * time.Hour)},
+	}, " https://api.example.com ")
+	events, ok := payload["events"].([]interface{})
+	if !ok || len(events) != 2 {
+		t.Fatalf("unexpected events: %v", payload["events"])
+	}
+	first := events[0].(map[string]interface{})
+	if first["eventId"] != int64(5) || first["startsAt"] != "2026-03-14T19:00:00Z" || first["addressLabel"] != "Club" {
+		t.Fatalf("unexpected first event: %v", first)
+	}
+	if _, ok := events[1].(map[string]interface{})["addressLabel"]; ok {
+		t.Fatalf("unexpected address of second event")
+	}
+	if payload["apiBaseUrl"] != "https://api.example.com" {
+		t.Fatalf("unexpected api base url: %v", payload["apiBaseUrl"])
+	}
+}
+
+// TestBuildNotificationWeeklyDigest verifies build notification weekly digest behavior.
+func TestBuildNotificationWeeklyDigest(t *testing.T) {
+	msg := buildNotification(models.NotificationJob{
+		Kind: "weekly_digest",
+		Payload: map[string]interface{}{"events": []interface{}{
+			map[string]interface{}{"eventId": float64(5), "title": "Jazz", "startsAt": "2026-03-14T19:00:00Z", "addressLabel": "Club"},
+			map[string]interface{}{"eventId": float64(6), "title": " "},
+			map[string]interface{}{"eventId": float64(7), "title": "Lecture"},
+		}},
+	}, "https://spacefestival.fun", "")
+	if !strings.HasPrefix(msg.Text, "Подборка событий на неделю") {
+		t.Fatalf("unexpected heading: %q", msg.Text)
+	}
+	if !strings.Contains(msg.Text, "1. Jazz — 2026-03-14 19:00\n   Club") || !strings.Contains(msg.Text, "2. Lecture") {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+	if len(msg.Buttons) != 2 || msg.Buttons[1].Text != "2. Lecture" || !strings.Contains(msg.Buttons[1].URL, "eventId=7") {
+		t.Fatalf("unexpected buttons: %+v", msg.Buttons)
+	}
+	if msg.ButtonURL != "" {
+		t.Fatalf("unexpected primary button: %q", msg.ButtonURL)
+	}
+
+	empty := buildNotification(models.NotificationJob{Kind: "weekly_digest"}, "https://spacefestival.fun", "")
+	if empty.Text != "" {
+		t.Fatalf("expected empty digest to be rejected, got %q", empty.Text)
+	}
+}
+
+// TestDigestOptions verifies digest options behavior.
+func TestDigestOptions(t *testing.T) {
+	cfg := &config.Config{
+		FeedRanking: config.FeedRankingConfig{DistanceWeight: 2},
+		Digest:      config.DigestConfig{RadiusKm: 1.5, SocialWeight: 0.5},
+	}
+	opts := digestOptions(cfg)
+	if opts.RadiusMeters != 1500 || opts.Size != defaultDigestEventSize || opts.Horizon != digestHorizon {
+		t.Fatalf("unexpected options: %+v", opts)
+	}
+	if opts.Ranking.DistanceWeight != 2 || opts.SocialWeight != 0.5 {
+		t.Fatalf("unexpected weights: %+v", opts)
+	}
+}
@@ -50,12 +50,55 @@ func main() {
 
 	repo := repository.New(pool)
 	telegram := integrations.NewTelegramClient(cfg.TelegramToken)
+	announcePolicy := repository.NewAnnouncementPolicy(cfg.Announce.RadiusKm, cfg.Announce.SeenDays, cfg.Announce.DailyCap, cfg.Announce.FollowedDailyCap)
+	digestOpts := digestOptions(cfg)
+	router := newNotificationRouter(cfg, repo, telegram, logger)
 
 	logger.Info("worker_started")
 	rateLimiter := time.NewTicker(time.Second / 20)
 	defer rateLimiter.Stop()
+	var lastSeriesRun time.Time
+	var lastReviewRun time.Time
+	var lastBanRun time.Time
+	var lastDigestRun time.Time
+	var lastTelegramUpdatesRun time.Time
 	for {
 		didWork := false
+		if time.Since(lastSeriesRun) >= seriesMaterializeInterval {
+			lastSeriesRun = time.Now()
+			if _, err := materializeEventSeries(ctx, repo, cfg.SeriesWeeks, lastSeriesRun, logger); err != nil {
+				logger.Warn("materialize_series_error", "error", err)
+			}
+		}
+		if time.Since(lastReviewRun) >= reviewRequestInterval {
+			lastReviewRun = time.Now()
+			if _, err := requestEventReviews(ctx, repo, cfg.ReviewHours, lastReviewRun, logger); err != nil {
+				logger.Warn("review_requests_error", "error", err)
+			}
+		}
+		if time.Since(lastBanRun) >= liftExpiredBansInterval {
+			lastBanRun = time.Now()
+			if _, err := liftExpiredBans(ctx, repo, lastBanRun, logger); err != nil {
+				logger.Warn("lift_expired_bans_error", "error", err)
+			}
+		}
+		if time.Since(lastDigestRun) >= digestInterval {
+			lastDigestRun = time.Now()
+			if _, err := enqueueWeeklyDigests(ctx, repo, digestOpts, cfg.APIPublicURL, lastDigestRun, logger); err != nil {
+				logger.Warn("weekly_digests_error", "error", err)
+			}
+		}
+		if time.Since(lastTelegramUpdatesRun) >= pruneTelegramUpdatesInterval {
+			lastTelegramUpdatesRun = time.Now()
+			if _, err := pruneTelegramUpdates(ctx, repo, lastTelegramUpdatesRun, logger); err != nil {
+				logger.Warn("prune_telegram_updates_error", "error", err)
+			}
+		}
+		if published, err := publishScheduledEvents(ctx, repo, cfg.APIPublicURL, announcePolicy, time.Now(), logger); err != nil {
+			logger.Warn("publish_scheduled_events_error", "error", err)
+		} else if published > 0 {
+			didWork = true
+		}
 		if err := repo.RequeueStaleProcessing(ctx, 10*time.Minute); err != nil {
 			logger.Warn("requeue_stale_jobs_error", "error", err)
 		}
@@ -68,7 +111,7 @@ func main() {
 		if len(jobs) > 0 {
 			didWork = true
 			for _, job := range jobs {
-				if err := handleJob(ctx, repo, telegram, cfg.BaseURL, cfg.APIPublicURL, job, logger); err != nil {
+				if err := handleJob(ctx, repo, router, cfg.BaseURL, cfg.APIPublicURL, job, logger); err != nil {
 					logger.Error("job_failed", "job_id", job.ID, "error", err)
 				}
 			}
@@ -101,80 +144,81 @@ type TelegramSender interface {
 	SendPhotoWithMarkup(chatID int64, photoURL, caption string, markup *integrations.ReplyMarkup) error
 }
 
-// handleJob handles job.
-func handleJob(ctx context.Context, repo *repository.Repository, telegram TelegramSender, baseURL, apiBaseURL string, job models.NotificationJob, logger *slog.Logger) error {
+// handleJob handles job. The notification is routed over the user's channels
+// with fallback, and the result of every channel tried is recorded on the job.
+func handleJob(ctx context.Context, repo *repository.Repository, router *notificationRouter, baseURL, apiBaseURL string, job models.NotificationJob, logger *slog.Logger) error {
 	if logger == nil {
 		logger = slog.Default()
 	}
 	logger.Info("job_processing", "job_id", job.ID, "kind", job.Kind, "user_id", job.UserID, "event_id", job.EventID, "run_at", job.RunAt)
-	chatID, err := repo.GetUserTelegramID(ctx, job.UserID)
+	recipient, err := repo.GetNotificationRecipient(ctx, job.UserID)
 	if err != nil {
 		return repo.UpdateNotificationJobStatus(ctx, job.ID, "failed", job.Attempts+1, err.Error(), nil)
 	}
 
+	settings, err := repo.GetNotificationSettings(ctx, job.UserID)
+	if err != nil {
+		logger.Warn("job_settings_failed", "job_id", job.ID, "user_id", job.UserID, "error", err)
+	} else if skip, deferUntil := planDelivery(job, settings, time.Now()); skip != "" {
+		logger.Info("job_skipped", "job_id", job.ID, "kind", job.Kind, "user_id", job.UserID, "reason", skip)
+		return repo.UpdateNotificationJobStatus(ctx, job.ID, "skipped", job.Attempts, skip, nil)
+	} else if deferUntil != nil {
+		logger.Info("job_deferred", "job_id", job.ID, "kind", job.Kind, "user_id", job.UserID, "until", *deferUntil)
+		return repo.UpdateNotificationJobStatus(ctx, job.ID, "pending", job.Attempts, "", deferUntil)
+	}
+
 	message := buildNotification(job, baseURL, apiBaseURL)
 	if message.Text == "" {
 		return repo.UpdateNotificationJobStatus(ctx, job.ID, "failed", job.Attempts+1, "unknown job kind", nil)
 	}
 
-	var markup *integrations.ReplyMarkup
-	if message.ButtonURL != "" {
-		markup = &integrations.ReplyMarkup{
-			InlineKeyboard: [][]integrations.InlineKeyboardButton{
-				{
-					{
-						Text:   message.ButtonText,
-						WebApp: &integrations.WebAppInfo{URL: message.ButtonURL},
-					},
-				},
-			},
-		}
+	deliveries, delivered, sendErr := router.deliver(ctx, recipient, job, message, logger)
+	status, attempts, lastError, nextRun := deliveryOutcome(job, delivered, sendErr, time.Now())
+	if err := repo.RecordNotificationJobResult(ctx, job.ID, status, attempts, lastError, nextRun, deliveries); err != nil {
+		return err
 	}
-
-	var sendErr error
-	sent := false
-	photoCaption := truncateRunes(message.Text, 1024)
-	if photoCaption == "" {
-		photoCaption = message.Text
+	if status == "sent" {
+		logger.Info("job_sent", "job_id", job.ID, "kind", job.Kind, "user_id", job.UserID, "channel", deliveries[len(deliveries)-1].Channel)
 	}
-	for _, photoURL := range message.PhotoURLs {
-		sendErr = telegram.SendPhotoWithMarkup(chatID, photoURL, photoCaption, markup)
-		if sendErr == nil {
-			sent = true
-			break
-		}
-		logger.Warn("job_photo_send_failed", "job_id", job.ID, "photo_url", photoURL, "error", sendErr)
+	return nil
+}
+
+// deliveryOutcome returns the job status after a delivery attempt. Failed
+// deliveries are retried with backoff, or after the Telegram retry_after when
+// longer, up to three attempts; a job no channel can deliver fails right away.
+func deliveryOutcome(job models.NotificationJob, delivered bool, sendErr error, now time.Time) (string, int, string, *time.Time) {
+	if delivered {
+		return "sent", job.Attempts, "", nil
 	}
-	if !sent {
-		sendErr = telegram.SendMessageWithMarkup(chatID, message.Text, markup)
-		if sendErr == nil {
-			sent = true
-		}
+	attempts := job.Attempts + 1
+	if sendErr == nil {
+		return "failed", attempts, "no delivery channel", nil
 	}
-
-	if !sent && sendErr != nil {
-		attempts := job.Attempts + 1
-		if attempts >= 3 {
-			return repo.UpdateNotificationJobStatus(ctx, job.ID, "failed", attempts, sendErr.Error(), nil)
-		}
-		delay := time.Duration(1<<attempts) * time.Minute
-		nextRun := time.Now().Add(delay)
-		return repo.UpdateNotificationJobStatus(ctx, job.ID, "pending", attempts, sendErr.Error(), &nextRun)
+	if attempts >= 3 {
+		return "failed", attempts, sendErr.Error(), nil
 	}
-
-	if err := repo.UpdateNotificationJobStatus(ctx, job.ID, "sent", job.Attempts, "", nil); err != nil {
-		return err
+	delay := time.Duration(1<<attempts) * time.Minute
+	if retryAfter, ok := integrations.TelegramRetryAfter(sendErr); ok && retryAfter > delay {
+		delay = retryAfter
 	}
-	logger.Info("job_sent", "job_id", job.ID, "kind", job.Kind, "user_id", job.UserID)
-	return nil
+	nextRun := now.Add(delay)
+	return "pending", attempts, sendErr.Error(), &nextRun
 }
 
-// notificationMessage represents notification message.
+// notificationMessage represents notification message. Buttons are extra
+// web app buttons, one per keyboard row, after the primary button.
 type notificationMessage struct {
 	Text       string
 	PhotoURLs  []string
 	ButtonURL  string
 	ButtonText string
+	Buttons    []notificationButton
+}
+
+// notificationButton represents a web app button of a notification.
+type notificationButton struct {
+	Text string
+	URL  string
 }
 
 // buildNotification builds notification.
@@ -186,7 +230,13 @@ func buildNotification(job models.NotificationJob, baseURL, apiBaseURL string) n
 		return buildEventCard(job, baseURL, apiBaseURL, "Новое событие")
 	case "event_nearby":
 		return buildEventCard(job, baseURL, apiBaseURL, "Событие рядом")
-	case "comment_added":
+	case "event_followed":
+		return buildEventCard(job, baseURL, apiBaseURL, followedEventHeading(payloadString(job.Payload, "creatorName")))
+	case "saved_search_alert":
+		return buildEventCard(job, baseURL, apiBaseURL, savedSearchHeading(payloadString(job.Payload, "searchName")))
+	case "weekly_digest":
+		return buildDigestNotification(job, baseURL)
+	case "comment_added", "comment_reply", "comment_mention":
 		return buildCommentNotification(job, baseURL, apiBaseURL)
 	case "joined":
 		return notificationMessage{
@@ -194,12 +244,55 @@ func buildNotification(job models.NotificationJob, baseURL, apiBaseURL string) n
 			ButtonURL:  eventURL,
 			ButtonText: buttonText(eventURL),
 		}
+	case "reminder":
+		return notificationMessage{
+			Text:       withTitle(reminderText(reminderOffsetMinutes(job, time.Now())), title),
+			ButtonURL:  eventURL,
+			ButtonText: buttonText(eventURL),
+		}
 	case "reminder_60m":
 		return notificationMessage{
-			Text:       withTitle("Напоминание: событие начнётся через 60 минут", title),
+			Text:       withTitle(reminderText(60), title),
+			ButtonURL:  eventURL,
+			ButtonText: buttonText(eventURL),
+		}
+	case "event_canceled":
+		lines := []string{withTitle("Событие отменено", title)}
+		if reason := strings.TrimSpace(payloadString(job.Payload, "reason")); reason != "" {
+			lines = append(lines, fmt.Sprintf("Причина: %s", truncateRunes(reason, 300)))
+		}
+		if payloadString(job.Payload, "refund") == "true" {
+			lines = append(lines, "Оплаченные билеты будут возвращены.")
+		}
+		return notificationMessage{
+			Text:       strings.Join(lines, "\n"),
+			ButtonURL:  eventURL,
+			ButtonText: buttonText(eventURL),
+		}
+	case "event_rescheduled":
+		lines := []string{withTitle("Событие перенесено", title)}
+		oldStartsAt := formatStartsAt(payloadString(job.Payload, "oldStartsAt"))
+		startsAt := formatStartsAt(payloadString(job.Payload, "startsAt"))
+		if oldStartsAt != "" && startsAt != "" {
+			lines = append(lines, fmt.Sprintf("Было: %s", oldStartsAt), fmt.Sprintf("Стало: %s", startsAt))
+		} else if startsAt != "" {
+			lines = append(lines, fmt.Sprintf("Новое время: %s", startsAt))
+		}
+		return notificationMessage{
+			Text:       strings.Join(lines, "\n"),
 			ButtonURL:  eventURL,
 			ButtonText: buttonText(eventURL),
 		}
+	case "review_request":
+		return notificationMessage{
+			Text:       withTitle("Как прошло событие? Поставьте оценку от 1 до 5", title),
+			ButtonURL:  eventURL,
+			ButtonText: "Оценить",
+		}
+	case "event_updated":
+		return buildEventUpdatedNotification(job, baseURL)
+	case "report_resolved":
+		return notificationMessage{Text: reportResolvedText(job)}
 	case "payment_confirmed":
 		orderID := strings.TrimSpace(payloadString(job.Payload, "orderId"))
 		amount := strings.TrimSpace(payloadString(job.Payload, "amount"))
@@ -228,6 +321,41 @@ func buildNotification(job models.NotificationJob, baseURL, apiBaseURL string) n
 	}
 }
 
+// reportResolvedText returns the outcome message sent to a reporter.
+func reportResolvedText(job models.NotificationJob) string {
+	subject := "жалобу"
+	switch payloadString(job.Payload, "targetType") {
+	case "event":
+		subject = "жалобу на событие"
+	case "comment":
+		subject = "жалобу на комментарий"
+	case "user":
+		subject = "жалобу на пользователя"
+	}
+	if payloadString(job.Payload, "status") == "actioned" {
+		return fmt.Sprintf("Спасибо! Мы рассмотрели вашу %s и приняли меры.", subject)
+	}
+	return fmt.Sprintf("Мы рассмотрели вашу %s и не нашли нарушений правил.", subject)
+}
+
+// followedEventHeading returns the heading of an event_followed card.
+func followedEventHeading(creatorName string) string {
+	creatorName = strings.TrimSpace(creatorName)
+	if creatorName == "" {
+		return "Новое событие от организатора, на которого вы подписаны"
+	}
+	return "Новое событие от " + truncateRunes(creatorName, 64)
+}
+
+// savedSearchHeading returns the heading of a saved_search_alert card.
+func savedSearchHeading(searchName string) string {
+	searchName = strings.TrimSpace(searchName)
+	if searchName == "" {
+		return "Новое событие по вашему сохранённому поиску"
+	}
+	return fmt.Sprintf("Новое событие по поиску «%s»", truncateRunes(searchName, 64))
+}
+
 // buildEventCard builds event card.
 func buildEventCard(job models.NotificationJob, baseURL, apiBaseURL, heading string) notificationMessage {
 	title := payloadString(job.Payload, "title")
@@ -262,6 +390,18 @@ func buildEventCard(job models.NotificationJob, baseURL, apiBaseURL, heading str
 	}
 }
 
+// commentHeading returns the first line of a comment notification of kind.
+func commentHeading(kind string) string {
+	switch kind {
+	case "comment_reply":
+		return "Ответ на ваш комментарий"
+	case "comment_mention":
+		return "Вас упомянули в комментарии"
+	default:
+		return "Новый комментарий к событию"
+	}
+}
+
 // buildCommentNotification builds comment notification.
 func buildCommentNotification(job models.NotificationJob, baseURL, apiBaseURL string) notificationMessage {
 	title := payloadString(job.Payload, "title")
@@ -274,11 +414,7 @@ func buildCommentNotification(job models.NotificationJob, baseURL, apiBaseURL st
 		comment = truncateRunes(comment, 200)
 	}
 	lines := make([]string, 0, 3)
-	if title != "" {
-		lines = append(lines, fmt.Sprintf("Новый комментарий к событию: %s", title))
-	} else {
-		lines = append(lines, "Новый комментарий к событию")
-	}
+	lines = append(lines, withTitle(commentHeading(job.Kind), title))
 	if comment != "" {
 		lines = append(lines, fmt.Sprintf("%s: %s", commenter, comment))
 	} else if commenter != "" {
@@ -294,6 +430,53 @@ func buildCommentNotification(job models.NotificationJob, baseURL, apiBaseURL st
 	}
 }
 
+// eventChangeLabels names fields of event_updated changes.
+var eventChangeLabels = map[string]string{
+	"startsAt": "Начало",
+	"endsAt":   "Окончание",
+	"location": "Место на карте",
+	"address":  "Адрес",
+	"capacity": "Количество мест",
+}
+
+// buildEventUpdatedNotification builds a before/after summary of event changes.
+func buildEventUpdatedNotification(job models.NotificationJob, baseURL string) notificationMessage {
+	lines := []string{withTitle("Событие изменено", payloadString(job.Payload, "title"))}
+	rawChanges, _ := job.Payload["changes"].([]interface{})
+	for _, raw := range rawChanges {
+		change, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := payloadString(change, "field")
+		label, ok := eventChangeLabels[field]
+		if !ok {
+			continue
+		}
+		before := payloadString(change, "before")
+		after := payloadString(change, "after")
+		if field == "startsAt" || field == "endsAt" {
+			before = formatStartsAt(before)
+			after = formatStartsAt(after)
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s → %s", label, changeValue(before), changeValue(after)))
+	}
+	eventURL := buildEventURL(baseURL, extractEventID(job))
+	return notificationMessage{
+		Text:       strings.Join(lines, "\n"),
+		ButtonURL:  eventURL,
+		ButtonText: buttonText(eventURL),
+	}
+}
+
+// changeValue renders an empty change value as a dash.
+func changeValue(value string) string {
+	if strings.TrimSpace(value) == "" {
+		return "—"
+	}
+	return truncateRunes(value, 120)
+}
+
 // payloadString handles payload string.
 func payloadString(payload map[string]interface{}, key string) string {
 	if payload == nil {
@@ -695,6 +878,13 @@ func handleBroadcastJob(ctx context.Context, repo *repository.Repository, telegr
 		if sendErr == nil {
 			return repo.UpdateAdminBroadcastJobStatus(ctx, job.ID, "sent", attempts, "")
 		}
+		if integrations.IsTelegramUnreachable(sendErr) {
+			// Retrying can't reach a user who blocked the bot; leave them out of later broadcasts.
+			if err := repo.MarkTelegramUnreachable(ctx, job.TargetUserID, sendErr.Error()); err != nil {
+				logger.Warn("broadcast_mark_unreachable_failed", "job_id", job.ID, "user_id", job.TargetUserID, "error", err)
+			}
+			return repo.UpdateAdminBroadcastJobStatus(ctx, job.ID, "failed", attempts, "telegram unreachable: "+sendErr.Error())
+		}
 		if markup != nil {
 			// Keep broadcast delivery resilient: fallback to plain text when markup is rejected.
 			plainErr := telegram.SendMessageWithMarkup(chatID, payload.Message, nil)
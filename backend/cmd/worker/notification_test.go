@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"gigme/backend/internal/models"
@@ -97,3 +98,149 @@ func containsString(values []string, target string) bool {
 	}
 	return false
 }
+
+// TestBuildNotificationEventRescheduledShowsOldAndNewTime verifies build notification event rescheduled shows old and new time behavior.
+func TestBuildNotificationEventRescheduledShowsOldAndNewTime(t *testing.T) {
+	eventID := int64(7)
+	job := models.NotificationJob{
+		Kind:    "event_rescheduled",
+		EventID: &eventID,
+		Payload: map[string]interface{}{
+			"eventId":     eventID,
+			"title":       "Jam",
+			"oldStartsAt": "2026-03-01T18:00:00Z",
+			"startsAt":    "2026-03-02T19:30:00Z",
+		},
+	}
+
+	msg := buildNotification(job, "https://spacefestival.fun", "")
+
+	if !strings.Contains(msg.Text, "Было: 2026-03-01 18:00") || !strings.Contains(msg.Text, "Стало: 2026-03-02 19:30") {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+	if msg.ButtonURL == "" {
+		t.Fatalf("expected event button")
+	}
+}
+
+// TestBuildNotificationEventCanceledMentionsRefund verifies build notification event canceled mentions refund behavior.
+func TestBuildNotificationEventCanceledMentionsRefund(t *testing.T) {
+	job := models.NotificationJob{
+		Kind: "event_canceled",
+		Payload: map[string]interface{}{
+			"eventId": float64(7),
+			"title":   "Jam",
+			"reason":  "Weather",
+			"refund":  true,
+		},
+	}
+
+	msg := buildNotification(job, "", "")
+
+	if !strings.HasPrefix(msg.Text, "Событие отменено: Jam") {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+	if !strings.Contains(msg.Text, "Причина: Weather") || !strings.Contains(msg.Text, "возвращены") {
+		t.Fatalf("expected reason and refund note, got %q", msg.Text)
+	}
+}
+
+// TestBuildNotificationEventUpdatedListsChanges verifies build notification event updated lists changes behavior.
+func TestBuildNotificationEventUpdatedListsChanges(t *testing.T) {
+	job := models.NotificationJob{
+		Kind: "event_updated",
+		Payload: map[string]interface{}{
+			"eventId": float64(7),
+			"title":   "Jam",
+			"changes": []interface{}{
+				map[string]interface{}{"field": "startsAt", "before": "2026-03-01T18:00:00Z", "after": "2026-03-01T19:00:00Z"},
+				map[string]interface{}{"field": "address", "before": "", "after": "Main hall"},
+				map[string]interface{}{"field": "unknown", "before": "a", "after": "b"},
+			},
+		},
+	}
+
+	msg := buildNotification(job, "", "")
+
+	want := "Событие изменено: Jam\nНачало: 2026-03-01 18:00 → 2026-03-01 19:00\nАдрес: — → Main hall"
+	if msg.Text != want {
+		t.Fatalf("unexpected text:\n%s", msg.Text)
+	}
+}
+
+// TestBuildNotificationCommentKinds verifies build notification comment kinds behavior.
+func TestBuildNotificationCommentKinds(t *testing.T) {
+	cases := map[string]string{
+		"comment_added":   "Новый комментарий к событию: Jam",
+		"comment_reply":   "Ответ на ваш комментарий: Jam",
+		"comment_mention": "Вас упомянули в комментарии: Jam",
+	}
+	for kind, heading := range cases {
+		job := models.NotificationJob{
+			Kind: kind,
+			Payload: map[string]interface{}{
+				"eventId":       float64(7),
+				"title":         "Jam",
+				"comment":       "see you @anna",
+				"commenterName": "Bob",
+			},
+		}
+
+		msg := buildNotification(job, "", "")
+
+		want := heading + "\nBob: see you @anna"
+		if msg.Text != want {
+			t.Fatalf("%s: unexpected text: %q", kind, msg.Text)
+		}
+	}
+}
+
+// TestBuildNotificationReportResolved verifies build notification report resolved behavior.
+func TestBuildNotificationReportResolved(t *testing.T) {
+	job := models.NotificationJob{
+		Kind:    "report_resolved",
+		Payload: map[string]interface{}{"targetType": "comment", "targetId": float64(5), "status": "actioned"},
+	}
+	if msg := buildNotification(job, "", ""); msg.Text != "Спасибо! Мы рассмотрели вашу жалобу на комментарий и приняли меры." {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+
+	job.Payload["status"] = "dismissed"
+	if msg := buildNotification(job, "", ""); msg.Text != "Мы рассмотрели вашу жалобу на комментарий и не нашли нарушений правил." {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+}
+
+// TestBuildNotificationEventFollowedNamesCreator verifies build notification event followed names creator behavior.
+func TestBuildNotificationEventFollowedNamesCreator(t *testing.T) {
+	job := models.NotificationJob{
+		Kind:    "event_followed",
+		Payload: map[string]interface{}{"eventId": float64(7), "title": "Techno night", "creatorName": "Anna K"},
+	}
+	msg := buildNotification(job, "", "")
+	if !strings.Contains(msg.Text, "Новое событие от Anna K") || !strings.Contains(msg.Text, "Techno night") {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+
+	delete(job.Payload, "creatorName")
+	msg = buildNotification(job, "", "")
+	if !strings.Contains(msg.Text, "на которого вы подписаны") {
+		t.Fatalf("unexpected text without creator: %q", msg.Text)
+	}
+}
+
+// TestBuildNotificationSavedSearchAlertNamesSearch verifies build notification saved search alert names search behavior.
+func TestBuildNotificationSavedSearchAlertNamesSearch(t *testing.T) {
+	eventID := int64(12)
+	msg := buildNotification(models.NotificationJob{
+		Kind:    "saved_search_alert",
+		EventID: &eventID,
+		Payload: map[string]interface{}{"title": "Rave", "searchName": "Техно рядом"},
+	}, "https://spacefestival.fun", "")
+	if !strings.Contains(msg.Text, "«Техно рядом»") || !strings.Contains(msg.Text, "Rave") {
+		t.Fatalf("unexpected text: %q", msg.Text)
+	}
+	if got := savedSearchHeading(" "); got != "Новое событие по вашему сохранённому поиску" {
+		t.Fatalf("unexpected fallback heading: %q", got)
+	}
+}
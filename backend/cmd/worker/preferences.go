@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/integrations"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/recurrence"
+	"gigme/backend/internal/repository"
+)
+
+// planDelivery applies notification settings of the recipient to a job. It
+// returns a reason when the job must be skipped, or a time to defer the job to
+// when it falls into quiet hours. Reminders of events that already started,
+// or that quiet hours would push past the start, are skipped.
+func planDelivery(job models.NotificationJob, settings models.NotificationSettings, now time.Time) (string, *time.Time) {
+	category := models.NotificationCategory(job.Kind)
+	for _, muted := range settings.Muted {
+		if category != "" && muted == category {
+			return "muted", nil
+		}
+	}
+	startsAt, startErr := time.Parse(time.RFC3339, payloadString(job.Payload, "startsAt"))
+	isReminder := category == models.NotificationCategoryReminders && startErr == nil
+	if isReminder && !now.Before(startsAt) {
+		return "started", nil
+	}
+	until, quiet := quietHoursEnd(settings, deliveryTimezone(job, settings), now)
+	if !quiet {
+		return "", nil
+	}
+	if isReminder && !until.Before(startsAt) {
+		return "quiet_hours", nil
+	}
+	return "", &until
+}
+
+// deliveryTimezone returns the timezone quiet hours are read in: the user's,
+// then the event's from the job payload, then models.DefaultTimezone.
+func deliveryTimezone(job models.NotificationJob, settings models.NotificationSettings) string {
+	if tz := strings.TrimSpace(settings.Timezone); tz != "" {
+		return tz
+	}
+	if tz := strings.TrimSpace(payloadString(job.Payload, "timezone")); tz != "" {
+		return tz
+	}
+	return models.DefaultTimezone
+}
+
+// quietHoursEnd reports whether now falls into the quiet hours of the user in
+// timezone and when they end.
+func quietHoursEnd(settings models.NotificationSettings, timezone string, now time.Time) (time.Time, bool) {
+	start, okStart := repository.ParseClockMinutes(settings.QuietHoursStart)
+	end, okEnd := repository.ParseClockMinutes(settings.QuietHoursEnd)
+	if !okStart || !okEnd || start == end {
+		return time.Time{}, false
+	}
+	local := now.In(recurrence.LoadLocation(timezone))
+	minute := local.Hour()*60 + local.Minute()
+	inside := minute >= start && minute < end
+	if start > end {
+		inside = minute >= start || minute < end
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, local.Location())
+	if !until.After(local) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+// muteButtonRow returns the keyboard row that mutes the category of a job, or
+// nil when the kind can't be muted.
+func muteButtonRow(kind string) []integrations.InlineKeyboardButton {
+	category := models.NotificationCategory(kind)
+	if category == "" {
+		return nil
+	}
+	return []integrations.InlineKeyboardButton{{
+		Text:         "Не присылать такие уведомления",
+		CallbackData: "mute:" + category,
+	}}
+}
+
+// reminderOffsetMinutes returns the offset the reminder text is rendered
+// with. A reminder sent well after its scheduled time, e.g. deferred past
+// quiet hours, gets the time actually left until the start, in whole hours
+// from two hours on.
+func reminderOffsetMinutes(job models.NotificationJob, now time.Time) int {
+	offset := int(payloadInt64(job.Payload, "offsetMinutes"))
+	startsAt, err := time.Parse(time.RFC3339, payloadString(job.Payload, "startsAt"))
+	if err != nil {
+		return offset
+	}
+	left := startsAt.Sub(now)
+	if left >= time.Duration(offset)*time.Minute-5*time.Minute {
+		return offset
+	}
+	if left >= 2*time.Hour {
+		return int(left/time.Hour) * 60
+	}
+	if left <= 0 {
+		return 0
+	}
+	return int(left.Round(time.Minute) / time.Minute)
+}
+
+// reminderText returns the reminder heading for an offset in minutes.
+func reminderText(offsetMinutes int) string {
+	switch {
+	case offsetMinutes <= 0:
+		return "Напоминание: событие скоро начнётся"
+	case offsetMinutes%(24*60) == 0:
+		days := offsetMinutes / (24 * 60)
+		return fmt.Sprintf("Напоминание: событие начнётся через %d %s", days, pluralRu(days, "день", "дня", "дней"))
+	case offsetMinutes%60 == 0:
+		hours := offsetMinutes / 60
+		return fmt.Sprintf("Напоминание: событие начнётся через %d %s", hours, pluralRu(hours, "час", "часа", "часов"))
+	default:
+		return fmt.Sprintf("Напоминание: событие начнётся через %d %s", offsetMinutes, pluralRu(offsetMinutes, "минуту", "минуты", "минут"))
+	}
+}
+
+// pluralRu picks the Russian plural form for n.
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
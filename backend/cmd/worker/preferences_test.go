@@ -0,0 +1,134 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestPlanDeliverySkipsMutedCategory verifies plan delivery skips muted category behavior.
+func TestPlanDeliverySkipsMutedCategory(t *testing.T) {
+	settings := models.NotificationSettings{Muted: []string{models.NotificationCategoryComments}}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if skip, _ := planDelivery(models.NotificationJob{Kind: "comment_reply"}, settings, now); skip != "muted" {
+		t.Fatalf("expected muted comment to be skipped, got %q", skip)
+	}
+	if skip, until := planDelivery(models.NotificationJob{Kind: "joined"}, settings, now); skip != "" || until != nil {
+		t.Fatalf("expected joined to be sent, got %q %v", skip, until)
+	}
+}
+
+// TestPlanDeliveryDefersQuietHours verifies plan delivery defers quiet hours behavior.
+func TestPlanDeliveryDefersQuietHours(t *testing.T) {
+	settings := models.NotificationSettings{QuietHoursStart: "23:00", QuietHoursEnd: "08:00", Timezone: "Europe/Moscow"}
+	// 21:30 UTC is 00:30 in Moscow.
+	now := time.Date(2026, 5, 1, 21, 30, 0, 0, time.UTC)
+	skip, until := planDelivery(models.NotificationJob{Kind: "event_created"}, settings, now)
+	if skip != "" || until == nil {
+		t.Fatalf("expected job to be deferred, got %q %v", skip, until)
+	}
+	if want := time.Date(2026, 5, 2, 5, 0, 0, 0, time.UTC); !until.Equal(want) {
+		t.Fatalf("expected deferral to %s, got %s", want, until.UTC())
+	}
+
+	daytime := time.Date(2026, 5, 1, 9, 0, 0, 0, time.UTC)
+	if skip, until := planDelivery(models.NotificationJob{Kind: "event_created"}, settings, daytime); skip != "" || until != nil {
+		t.Fatalf("expected daytime job to be sent, got %q %v", skip, until)
+	}
+}
+
+// TestPlanDeliverySkipsReminderAfterStart verifies plan delivery skips reminder after start behavior.
+func TestPlanDeliverySkipsReminderAfterStart(t *testing.T) {
+	settings := models.NotificationSettings{QuietHoursStart: "22:00", QuietHoursEnd: "07:00"}
+	now := time.Date(2026, 5, 1, 23, 0, 0, 0, time.UTC)
+	job := models.NotificationJob{
+		Kind:    "reminder",
+		Payload: map[string]interface{}{"startsAt": "2026-05-02T00:00:00+00:00", "offsetMinutes": float64(60)},
+	}
+	if skip, _ := planDelivery(job, settings, now); skip != "quiet_hours" {
+		t.Fatalf("expected reminder to be skipped, got %q", skip)
+	}
+	job.Payload["startsAt"] = "2026-05-02T12:00:00+00:00"
+	if skip, until := planDelivery(job, settings, now); skip != "" || until == nil {
+		t.Fatalf("expected reminder to be deferred, got %q %v", skip, until)
+	}
+}
+
+// TestPlanDeliveryTimezoneFallback verifies quiet hours fall back to the event and the default timezone.
+func TestPlanDeliveryTimezoneFallback(t *testing.T) {
+	settings := models.NotificationSettings{QuietHoursStart: "23:00", QuietHoursEnd: "08:00"}
+	// 21:30 UTC is 00:30 in Moscow and 06:30 in Vladivostok.
+	now := time.Date(2026, 5, 1, 21, 30, 0, 0, time.UTC)
+	skip, until := planDelivery(models.NotificationJob{Kind: "event_created"}, settings, now)
+	if skip != "" || until == nil || !until.Equal(time.Date(2026, 5, 2, 5, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected deferral to 08:00 Moscow, got %q %v", skip, until)
+	}
+	job := models.NotificationJob{Kind: "event_created", Payload: map[string]interface{}{"timezone": "Asia/Vladivostok"}}
+	if skip, until := planDelivery(job, settings, now); skip != "" || until == nil || !until.Equal(time.Date(2026, 5, 1, 22, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected deferral to 08:00 Vladivostok, got %q %v", skip, until)
+	}
+	settings.Timezone = "UTC"
+	if skip, until := planDelivery(job, settings, now); skip != "" || until != nil {
+		t.Fatalf("expected the user timezone to win, got %q %v", skip, until)
+	}
+}
+
+// TestPlanDeliverySkipsStartedReminder verifies plan delivery skips reminders of started events.
+func TestPlanDeliverySkipsStartedReminder(t *testing.T) {
+	job := models.NotificationJob{
+		Kind:    "reminder",
+		Payload: map[string]interface{}{"startsAt": "2026-05-01T12:00:00+00:00", "offsetMinutes": float64(60)},
+	}
+	if skip, _ := planDelivery(job, models.NotificationSettings{}, time.Date(2026, 5, 1, 12, 5, 0, 0, time.UTC)); skip != "started" {
+		t.Fatalf("expected started reminder to be skipped, got %q", skip)
+	}
+}
+
+// TestReminderOffsetMinutes verifies late reminders are rendered with the time left.
+func TestReminderOffsetMinutes(t *testing.T) {
+	job := models.NotificationJob{
+		Kind:    "reminder",
+		Payload: map[string]interface{}{"startsAt": "2026-05-02T12:00:00+00:00", "offsetMinutes": float64(24 * 60)},
+	}
+	if got := reminderOffsetMinutes(job, time.Date(2026, 5, 1, 12, 1, 0, 0, time.UTC)); got != 24*60 {
+		t.Fatalf("expected the scheduled offset, got %d", got)
+	}
+	if got := reminderOffsetMinutes(job, time.Date(2026, 5, 2, 5, 0, 0, 0, time.UTC)); got != 7*60 {
+		t.Fatalf("expected 7 hours left, got %d", got)
+	}
+	if got := reminderOffsetMinutes(job, time.Date(2026, 5, 2, 11, 20, 0, 0, time.UTC)); got != 40 {
+		t.Fatalf("expected 40 minutes left, got %d", got)
+	}
+}
+
+// TestBuildNotificationReminderUsesOffset verifies build notification reminder uses offset behavior.
+func TestBuildNotificationReminderUsesOffset(t *testing.T) {
+	eventID := int64(5)
+	msg := buildNotification(models.NotificationJob{
+		Kind:    "reminder",
+		EventID: &eventID,
+		Payload: map[string]interface{}{"title": "Jam", "offsetMinutes": float64(24 * 60)},
+	}, "https://spacefestival.fun", "")
+	if !strings.Contains(msg.Text, "через 1 день") || !strings.Contains(msg.Text, "Jam") {
+		t.Fatalf("unexpected reminder text: %q", msg.Text)
+	}
+	if got := reminderText(120); !strings.Contains(got, "через 2 часа") {
+		t.Fatalf("unexpected reminder text: %q", got)
+	}
+	if got := reminderText(15); !strings.Contains(got, "через 15 минут") {
+		t.Fatalf("unexpected reminder text: %q", got)
+	}
+}
+
+// TestMuteButtonRow verifies mute button row behavior.
+func TestMuteButtonRow(t *testing.T) {
+	row := muteButtonRow("event_nearby")
+	if len(row) != 1 || row[0].CallbackData != "mute:"+models.NotificationCategoryNewEvents {
+		t.Fatalf("unexpected mute row: %+v", row)
+	}
+	if row := muteButtonRow("unknown"); row != nil {
+		t.Fatalf("expected no mute row for unknown kind, got %+v", row)
+	}
+}
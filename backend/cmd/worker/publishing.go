@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+const maxScheduledPublishesPerRun = 100
+
+// publishScheduledEvents publishes due drafts and announces the public ones.
+func publishScheduledEvents(ctx context.Context, repo *repository.Repository, apiBaseURL string, policy repository.AnnouncementPolicy, now time.Time, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	events, err := repo.PublishDueEvents(ctx, now, maxScheduledPublishesPerRun)
+	if err != nil {
+		return 0, err
+	}
+	for _, event := range announcedEvents(events) {
+		payload := map[string]interface{}{
+			"eventId":  event.ID,
+			"title":    event.Title,
+			"startsAt": event.StartsAt.Format(time.RFC3339),
+		}
+		if apiBaseURL = strings.TrimSpace(apiBaseURL); apiBaseURL != "" {
+			payload["apiBaseUrl"] = apiBaseURL
+		}
+		if event.AddressLabel != "" {
+			payload["addressLabel"] = event.AddressLabel
+		}
+		if event.ThumbnailURL != "" {
+			payload["photoUrl"] = event.ThumbnailURL
+		}
+		counts, err := repo.EnqueueEventAnnouncements(ctx, event.ID, now, payload, policy)
+		if err != nil {
+			logger.Warn("scheduled_publish_notify_failed", "event_id", event.ID, "error", err)
+			continue
+		}
+		logger.Info("scheduled_publish_notify_enqueued", "event_id", event.ID, "followers", counts.Followers, "all_events", counts.AllEvents, "nearby", counts.Nearby)
+		alerts, err := repo.EnqueueSavedSearchAlerts(ctx, event.ID, now, payload)
+		if err != nil {
+			logger.Warn("scheduled_publish_alerts_failed", "event_id", event.ID, "error", err)
+			continue
+		}
+		logger.Info("scheduled_publish_alerts_enqueued", "event_id", event.ID, "count", alerts)
+	}
+	if len(events) > 0 {
+		logger.Info("scheduled_events_published", "count", len(events))
+	}
+	return len(events), nil
+}
+
+// announcedEvents returns public published events that need an announcement.
+// A series is announced once, by its earliest published occurrence.
+func announcedEvents(events []models.Event) []models.Event {
+	sorted := make([]models.Event, 0, len(events))
+	for _, event := range events {
+		if !event.IsPrivate {
+			sorted = append(sorted, event)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].StartsAt.Before(sorted[j].StartsAt)
+	})
+	seenSeries := make(map[int64]struct{})
+	out := make([]models.Event, 0, len(sorted))
+	for _, event := range sorted {
+		if event.SeriesID != nil {
+			if _, ok := seenSeries[*event.SeriesID]; ok {
+				continue
+			}
+			seenSeries[*event.SeriesID] = struct{}{}
+		}
+		out = append(out, event)
+	}
+	return out
+}
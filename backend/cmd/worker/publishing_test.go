@@ -0,0 +1,27 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestAnnouncedEvents verifies announced events behavior.
+func TestAnnouncedEvents(t *testing.T) {
+	base := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+	seriesID := int64(7)
+	events := []models.Event{
+		{ID: 3, SeriesID: &seriesID, StartsAt: base.AddDate(0, 0, 7)},
+		{ID: 2, SeriesID: &seriesID, StartsAt: base},
+		{ID: 4, IsPrivate: true, StartsAt: base},
+		{ID: 5, StartsAt: base.Add(time.Hour)},
+	}
+	got := announcedEvents(events)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 announced events, got %d", len(got))
+	}
+	if got[0].ID != 2 || got[1].ID != 5 {
+		t.Fatalf("unexpected announced events %d, %d", got[0].ID, got[1].ID)
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/integrations/push"
+	"gigme/backend/internal/models"
+)
+
+const (
+	maxPushTitleRunes = 100
+	maxPushBodyRunes  = 500
+)
+
+// pushTokenStore is the part of the repository push delivery needs.
+type pushTokenStore interface {
+	ListActivePushTokens(ctx context.Context, userID int64) ([]models.UserPushToken, error)
+	DeactivatePushToken(ctx context.Context, token string) error
+}
+
+// pushNotifier delivers notifications to the mobile push tokens of a user.
+// Senders are keyed by token platform.
+type pushNotifier struct {
+	senders map[string]push.Sender
+}
+
+// newPushNotifier builds push senders from the config. Android, web and ios
+// tokens are FCM registration tokens, which the app also gets on iOS; raw
+// APNs device tokens are registered as ios_apns and go through APNs. It
+// returns nil when no sender is configured.
+func newPushNotifier(cfg config.PushConfig, logger *slog.Logger) *pushNotifier {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	senders := make(map[string]push.Sender)
+	if cfg.FCMCredentialsFile != "" {
+		fcmCfg, err := push.LoadFCMConfig(cfg.FCMCredentialsFile)
+		if err == nil {
+			fcmCfg.BaseURL = cfg.FCMBaseURL
+			var sender *push.FCMSender
+			if sender, err = push.NewFCMSender(fcmCfg, nil); err == nil {
+				senders["android"] = sender
+				senders["web"] = sender
+				senders["ios"] = sender
+			}
+		}
+		if err != nil {
+			logger.Warn("push_fcm_disabled", "error", err)
+		}
+	}
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err == nil {
+			var sender *push.APNsSender
+			sender, err = push.NewAPNsSender(push.APNsConfig{
+				KeyID:      cfg.APNsKeyID,
+				TeamID:     cfg.APNsTeamID,
+				BundleID:   cfg.APNsBundleID,
+				PrivateKey: string(key),
+				BaseURL:    cfg.APNsBaseURL,
+			}, nil)
+			if err == nil {
+				senders["ios_apns"] = sender
+			}
+		}
+		if err != nil {
+			logger.Warn("push_apns_disabled", "error", err)
+		}
+	}
+	if len(senders) == 0 {
+		return nil
+	}
+	return &pushNotifier{senders: senders}
+}
+
+// deliver sends msg to every active token of the user and returns how many
+// devices got it. Tokens the push service reports as unregistered are
+// deactivated; the last other error is returned.
+func (p *pushNotifier) deliver(ctx context.Context, store pushTokenStore, userID int64, msg push.Message, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	tokens, err := store.ListActivePushTokens(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	var lastErr error
+	for _, token := range tokens {
+		sender, ok := p.senders[token.Platform]
+		if !ok {
+			continue
+		}
+		err := sender.Send(ctx, token.Token, msg)
+		switch {
+		case err == nil:
+			sent++
+		case errors.Is(err, push.ErrUnregistered):
+			logger.Info("push_token_unregistered", "user_id", userID, "platform", token.Platform, "error", err)
+			if err := store.DeactivatePushToken(ctx, token.Token); err != nil {
+				logger.Warn("push_token_deactivate_failed", "user_id", userID, "error", err)
+			}
+		default:
+			lastErr = err
+		}
+	}
+	return sent, lastErr
+}
+
+// pushMessage converts a notification into a push message: the first line
+// becomes the title and the rest the body. Data carries what the app needs to
+// open the notification target.
+func pushMessage(job models.NotificationJob, message notificationMessage) push.Message {
+	title, body, _ := strings.Cut(message.Text, "\n")
+	data := map[string]string{
+		"kind":  job.Kind,
+		"jobId": strconv.FormatInt(job.ID, 10),
+	}
+	if eventID := extractEventID(job); eventID > 0 {
+		data["eventId"] = strconv.FormatInt(eventID, 10)
+	}
+	if message.ButtonURL != "" {
+		data["url"] = message.ButtonURL
+	}
+	return push.Message{
+		Title: truncateRunes(strings.TrimSpace(title), maxPushTitleRunes),
+		Body:  truncateRunes(strings.TrimSpace(body), maxPushBodyRunes),
+		Data:  data,
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gigme/backend/internal/integrations/push"
+	"gigme/backend/internal/models"
+)
+
+// fakePushTokenStore keeps push tokens in memory.
+type fakePushTokenStore struct {
+	tokens      []models.UserPushToken
+	deactivated []string
+}
+
+// ListActivePushTokens lists active push tokens.
+func (s *fakePushTokenStore) ListActivePushTokens(ctx context.Context, userID int64) ([]models.UserPushToken, error) {
+	return s.tokens, nil
+}
+
+// DeactivatePushToken records a deactivated token.
+func (s *fakePushTokenStore) DeactivatePushToken(ctx context.Context, token string) error {
+	s.deactivated = append(s.deactivated, token)
+	return nil
+}
+
+// pemKey PEM encodes a private key in PKCS #8.
+func pemKey(t *testing.T, key interface{}) string {
+	t.Helper()
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// TestPushNotifierDeliver verifies push notifier deliver behavior.
+func TestPushNotifierDeliver(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	var fcmTokens []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/token":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "expires_in": 3600})
+		case r.URL.Path == "/v1/projects/demo/messages:send":
+			var req struct {
+				Message struct {
+					Token string `json:"token"`
+				} `json:"message"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			fcmTokens = append(fcmTokens, req.Message.Token)
+			_, _ = w.Write([]byte(`{}`))
+		case strings.HasPrefix(r.URL.Path, "/3/device/"):
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	fcm, err := push.NewFCMSender(push.FCMConfig{
+		ProjectID:   "demo",
+		ClientEmail: "push@example.com",
+		PrivateKey:  pemKey(t, rsaKey),
+		TokenURL:    srv.URL + "/token",
+		BaseURL:     srv.URL,
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("fcm sender: %v", err)
+	}
+	apns, err := push.NewAPNsSender(push.APNsConfig{
+		KeyID:      "KEY",
+		TeamID:     "TEAM",
+		BundleID:   "fun.spacefestival.app",
+		PrivateKey: pemKey(t, ecKey),
+		BaseURL:    srv.URL,
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("apns sender: %v", err)
+	}
+
+	store := &fakePushTokenStore{tokens: []models.UserPushToken{
+		{UserID: 1, Platform: "android", Token: "android-token"},
+		{UserID: 1, Platform: "ios", Token: "ios-fcm-token"},
+		{UserID: 1, Platform: "ios_apns", Token: "apns-token"},
+		{UserID: 1, Platform: "symbian", Token: "other-token"},
+	}}
+	notifier := &pushNotifier{senders: map[string]push.Sender{"android": fcm, "web": fcm, "ios": fcm, "ios_apns": apns}}
+	sent, err := notifier.deliver(context.Background(), store, 1, push.Message{Title: "Title"}, nil)
+	if err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	if sent != 2 || len(fcmTokens) != 2 || fcmTokens[0] != "android-token" || fcmTokens[1] != "ios-fcm-token" {
+		t.Fatalf("unexpected delivery: sent=%d fcm=%v", sent, fcmTokens)
+	}
+	if len(store.deactivated) != 1 || store.deactivated[0] != "apns-token" {
+		t.Fatalf("unexpected deactivated tokens: %v", store.deactivated)
+	}
+}
+
+// TestPushMessage verifies push message behavior.
+func TestPushMessage(t *testing.T) {
+	eventID := int64(9)
+	job := models.NotificationJob{ID: 3, Kind: "event_created", EventID: &eventID}
+	msg := pushMessage(job, notificationMessage{
+		Text:      "Новое событие\nRave\n2026-03-14 19:00",
+		ButtonURL: "https://spacefestival.fun/space_app?eventId=9",
+	})
+	if msg.Title != "Новое событие" || msg.Body != "Rave\n2026-03-14 19:00" {
+		t.Fatalf("unexpected text: %+v", msg)
+	}
+	if msg.Data["kind"] != "event_created" || msg.Data["eventId"] != "9" || msg.Data["jobId"] != "3" || msg.Data["url"] == "" {
+		t.Fatalf("unexpected data: %v", msg.Data)
+	}
+	single := pushMessage(models.NotificationJob{Kind: "report_resolved"}, notificationMessage{Text: "Спасибо!"})
+	if single.Title != "Спасибо!" || single.Body != "" || single.Data["eventId"] != "" {
+		t.Fatalf("unexpected single line message: %+v", single)
+	}
+}
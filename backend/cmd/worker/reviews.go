@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gigme/backend/internal/repository"
+)
+
+const (
+	reviewRequestInterval        = 5 * time.Minute
+	maxReviewRequestEventsPerRun = 50
+)
+
+// reviewRequestCutoff returns the end time before which events are due for a review request.
+func reviewRequestCutoff(now time.Time, delayHours int) time.Time {
+	if delayHours < 0 {
+		delayHours = 0
+	}
+	return now.Add(-time.Duration(delayHours) * time.Hour)
+}
+
+// requestEventReviews asks participants of recently ended events to rate them.
+func requestEventReviews(ctx context.Context, repo *repository.Repository, delayHours int, now time.Time, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	events, jobs, err := repo.QueueReviewRequests(ctx, reviewRequestCutoff(now, delayHours), now, maxReviewRequestEventsPerRun)
+	if err != nil {
+		return 0, err
+	}
+	if events > 0 {
+		logger.Info("review_requests_enqueued", "events", events, "jobs", jobs)
+	}
+	return events, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestReviewRequestCutoff verifies review request cutoff behavior.
+func TestReviewRequestCutoff(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	if got := reviewRequestCutoff(now, 3); !got.Equal(now.Add(-3 * time.Hour)) {
+		t.Fatalf("unexpected cutoff: %s", got)
+	}
+	if got := reviewRequestCutoff(now, -1); !got.Equal(now) {
+		t.Fatalf("expected negative delay to be ignored, got %s", got)
+	}
+}
+
+// TestBuildNotificationReviewRequest verifies build notification review request behavior.
+func TestBuildNotificationReviewRequest(t *testing.T) {
+	eventID := int64(9)
+	msg := buildNotification(models.NotificationJob{
+		Kind:    "review_request",
+		EventID: &eventID,
+		Payload: map[string]interface{}{"title": "Jam"},
+	}, "https://spacefestival.fun", "")
+	if msg.Text == "" || msg.ButtonURL == "" || msg.ButtonText != "Оценить" {
+		t.Fatalf("unexpected message: %+v", msg)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gigme/backend/internal/recurrence"
+	"gigme/backend/internal/repository"
+)
+
+const (
+	seriesMaterializeInterval  = time.Hour
+	maxSeriesOccurrencesPerRun = 60
+)
+
+// materializeEventSeries extends recurring series with occurrences up to the horizon.
+func materializeEventSeries(ctx context.Context, repo *repository.Repository, weeks int, now time.Time, logger *slog.Logger) (int, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if weeks <= 0 {
+		weeks = 8
+	}
+	horizon := now.AddDate(0, 0, 7*weeks)
+	seriesList, err := repo.ListEventSeriesToMaterialize(ctx, horizon, 50)
+	if err != nil {
+		return 0, err
+	}
+	created := 0
+	for _, series := range seriesList {
+		rule, err := recurrence.Parse(series.RRule)
+		if err != nil {
+			logger.Warn("series_rule_invalid", "series_id", series.ID, "rrule", series.RRule, "error", err)
+			continue
+		}
+		loc := recurrence.LoadLocation(series.Timezone)
+		starts, exhausted := rule.Next(series.StartsAt.In(loc), series.MaterializedCount, horizon, maxSeriesOccurrencesPerRun)
+		materializedUntil := horizon
+		if len(starts) >= maxSeriesOccurrencesPerRun {
+			materializedUntil = starts[len(starts)-1]
+		}
+		ids, err := repo.AppendEventSeriesOccurrences(ctx, series, starts, materializedUntil, !exhausted)
+		if err != nil {
+			logger.Error("series_materialize_failed", "series_id", series.ID, "error", err)
+			continue
+		}
+		created += len(ids)
+		if len(ids) > 0 {
+			logger.Info("series_materialized", "series_id", series.ID, "count", len(ids), "materialized_until", materializedUntil)
+		}
+	}
+	return created, nil
+}
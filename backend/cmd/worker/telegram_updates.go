@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"gigme/backend/internal/repository"
+)
+
+const (
+	pruneTelegramUpdatesInterval = time.Hour
+	// telegramUpdateRetention outlasts the 24 hours Telegram keeps redelivering an update.
+	telegramUpdateRetention = 7 * 24 * time.Hour
+)
+
+// pruneTelegramUpdates deletes webhook update ids too old to be redelivered.
+func pruneTelegramUpdates(ctx context.Context, repo *repository.Repository, now time.Time, logger *slog.Logger) (int64, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	pruned, err := repo.PruneTelegramUpdates(ctx, now.Add(-telegramUpdateRetention))
+	if err != nil {
+		return 0, err
+	}
+	if pruned > 0 {
+		logger.Info("telegram_updates_pruned", "count", pruned)
+	}
+	return pruned, nil
+}
@@ -2,11 +2,16 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 )
 
+// webhookSecretRe matches the characters Telegram allows in a webhook secret token.
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
 // Config represents config.
 type Config struct {
 	Env           string
@@ -19,6 +24,7 @@ type Config struct {
 	TelegramUser  string
 	VKAppID       string
 	VKAppSecret   string
+	VKGroupToken  string
 	BaseURL       string
 	APIPublicURL  string
 	PhoneNumber   string
@@ -30,9 +36,85 @@ type Config struct {
 	AdminLogin    string
 	AdminPassword string
 	AdminPassHash string
+	SeriesWeeks   int
+	ReviewHours   int
+	ReportHide    int
+	MapMarkerZoom int
+	FeedRanking   FeedRankingConfig
+	ContentFilter ContentFilterConfig
+	Announce      AnnounceConfig
+	Digest        DigestConfig
+	Push          PushConfig
+	SMTP          SMTPConfig
 	Tochka        TochkaConfig
 	S3            S3Config
 	Logging       LoggingConfig
+	Webhook       TelegramWebhookConfig
+}
+
+// FeedRankingConfig represents feed ranking config.
+type FeedRankingConfig struct {
+	DefaultMode      string
+	DistanceWeight   float64
+	TimeWeight       float64
+	PopularityWeight float64
+	AffinityWeight   float64
+	DistanceScaleKm  float64
+	TimeScaleHours   float64
+}
+
+// ContentFilterConfig represents content filter config.
+type ContentFilterConfig struct {
+	BlockWords        []string
+	HoldWords         []string
+	CommentsPerMinute int
+}
+
+// AnnounceConfig represents targeting of new-event announcements.
+type AnnounceConfig struct {
+	RadiusKm         float64
+	SeenDays         int
+	DailyCap         int
+	FollowedDailyCap int
+}
+
+// DigestConfig represents selection of weekly digest events.
+type DigestConfig struct {
+	RadiusKm     float64
+	Size         int
+	SocialWeight float64
+}
+
+// PushConfig represents mobile and Web Push settings. FCM is enabled by a
+// service account key file, APNs by a .p8 signing key file and Web Push by a
+// VAPID key file.
+type PushConfig struct {
+	FCMCredentialsFile string
+	FCMBaseURL         string
+	APNsKeyFile        string
+	APNsKeyID          string
+	APNsTeamID         string
+	APNsBundleID       string
+	APNsBaseURL        string
+	WebPushKeyFile     string
+	WebPushSubject     string
+}
+
+// SMTPConfig represents outgoing email settings.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// TelegramWebhookConfig represents Telegram webhook settings. Secret is the
+// secret_token Telegram sends in the X-Telegram-Bot-Api-Secret-Token header;
+// URL is the public webhook address registered with setWebhook.
+type TelegramWebhookConfig struct {
+	Secret string
+	URL    string
 }
 
 // TochkaConfig represents tochka config.
@@ -83,6 +165,7 @@ func Load() (*Config, error) {
 		TelegramUser:  os.Getenv("TELEGRAM_BOT_USERNAME"),
 		VKAppID:       strings.TrimSpace(os.Getenv("VK_APP_ID")),
 		VKAppSecret:   strings.TrimSpace(os.Getenv("VK_APP_SECRET")),
+		VKGroupToken:  strings.TrimSpace(os.Getenv("VK_GROUP_TOKEN")),
 		BaseURL:       getenv("BASE_URL", ""),
 		APIPublicURL:  getenv("API_PUBLIC_URL", ""),
 		PhoneNumber:   getenvAny([]string{"PAYMENT_PHONE_NUMBER", "PHONE_NUMBER"}, ""),
@@ -93,6 +176,53 @@ func Load() (*Config, error) {
 		AdminLogin:    getenv("ADMIN_LOGIN", ""),
 		AdminPassword: os.Getenv("ADMIN_PASSWORD"),
 		AdminPassHash: os.Getenv("ADMIN_PASSWORD_HASH"),
+		SeriesWeeks:   getenvInt("EVENT_SERIES_WEEKS", 8),
+		ReviewHours:   getenvInt("REVIEW_REQUEST_DELAY_HOURS", 3),
+		ReportHide:    getenvInt("REPORT_AUTO_HIDE_THRESHOLD", 3),
+		MapMarkerZoom: getenvInt("MAP_MARKER_MIN_ZOOM", 14),
+		FeedRanking: FeedRankingConfig{
+			DefaultMode:      strings.ToLower(strings.TrimSpace(getenv("FEED_DEFAULT_MODE", "chronological"))),
+			DistanceWeight:   getenvFloat("FEED_RANK_WEIGHT_DISTANCE", 1),
+			TimeWeight:       getenvFloat("FEED_RANK_WEIGHT_TIME", 1),
+			PopularityWeight: getenvFloat("FEED_RANK_WEIGHT_POPULARITY", 0.7),
+			AffinityWeight:   getenvFloat("FEED_RANK_WEIGHT_AFFINITY", 1.2),
+			DistanceScaleKm:  getenvFloat("FEED_RANK_DISTANCE_SCALE_KM", 5),
+			TimeScaleHours:   getenvFloat("FEED_RANK_TIME_SCALE_HOURS", 48),
+		},
+		ContentFilter: ContentFilterConfig{
+			BlockWords:        parseList(os.Getenv("CONTENT_FILTER_BLOCK_WORDS")),
+			HoldWords:         parseList(os.Getenv("CONTENT_FILTER_HOLD_WORDS")),
+			CommentsPerMinute: getenvInt("CONTENT_FILTER_COMMENTS_PER_MINUTE", 5),
+		},
+		Announce: AnnounceConfig{
+			RadiusKm:         getenvFloat("ANNOUNCE_RADIUS_KM", 25),
+			SeenDays:         getenvInt("ANNOUNCE_SEEN_DAYS", 30),
+			DailyCap:         getenvInt("ANNOUNCE_DAILY_CAP", 3),
+			FollowedDailyCap: getenvInt("ANNOUNCE_FOLLOWED_DAILY_CAP", 10),
+		},
+		Digest: DigestConfig{
+			RadiusKm:     getenvFloat("DIGEST_RADIUS_KM", 25),
+			Size:         getenvInt("DIGEST_SIZE", 8),
+			SocialWeight: getenvFloat("DIGEST_SOCIAL_WEIGHT", 1),
+		},
+		Push: PushConfig{
+			FCMCredentialsFile: strings.TrimSpace(os.Getenv("FCM_CREDENTIALS_FILE")),
+			FCMBaseURL:         strings.TrimSpace(os.Getenv("FCM_BASE_URL")),
+			APNsKeyFile:        strings.TrimSpace(os.Getenv("APNS_KEY_FILE")),
+			APNsKeyID:          strings.TrimSpace(os.Getenv("APNS_KEY_ID")),
+			APNsTeamID:         strings.TrimSpace(os.Getenv("APNS_TEAM_ID")),
+			APNsBundleID:       strings.TrimSpace(os.Getenv("APNS_BUNDLE_ID")),
+			APNsBaseURL:        strings.TrimSpace(getenv("APNS_BASE_URL", "https://api.push.apple.com")),
+			WebPushKeyFile:     strings.TrimSpace(os.Getenv("WEBPUSH_VAPID_KEY_FILE")),
+			WebPushSubject:     strings.TrimSpace(os.Getenv("WEBPUSH_SUBJECT")),
+		},
+		SMTP: SMTPConfig{
+			Host:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+			Port:     getenvInt("SMTP_PORT", 587),
+			Username: strings.TrimSpace(os.Getenv("SMTP_USERNAME")),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     strings.TrimSpace(os.Getenv("SMTP_FROM")),
+		},
 		Tochka: TochkaConfig{
 			ClientID:     strings.TrimSpace(os.Getenv("TOCHKA_CLIENT_ID")),
 			ClientSecret: strings.TrimSpace(os.Getenv("TOCHKA_CLIENT_SECRET")),
@@ -119,6 +249,10 @@ func Load() (*Config, error) {
 			Format: getenv("LOG_FORMAT", "text"),
 			File:   os.Getenv("LOG_FILE"),
 		},
+		Webhook: TelegramWebhookConfig{
+			Secret: strings.TrimSpace(os.Getenv("TELEGRAM_WEBHOOK_SECRET")),
+			URL:    strings.TrimSpace(os.Getenv("TELEGRAM_WEBHOOK_URL")),
+		},
 	}
 
 	if cfg.DatabaseURL == "" {
@@ -133,6 +267,9 @@ func Load() (*Config, error) {
 	if cfg.TelegramToken == "" {
 		return nil, fmt.Errorf("TELEGRAM_BOT_TOKEN is required")
 	}
+	if cfg.Webhook.Secret != "" && !webhookSecretRe.MatchString(cfg.Webhook.Secret) {
+		return nil, fmt.Errorf("TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
 
 	return cfg, nil
 }
@@ -168,6 +305,32 @@ func getenvBool(key string, def bool) bool {
 	return parsed
 }
 
+// getenvInt handles getenv int.
+func getenvInt(key string, def int) int {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	parsed, err := strconv.Atoi(v)
+	if err != nil || parsed <= 0 {
+		return def
+	}
+	return parsed
+}
+
+// getenvFloat handles getenv float.
+func getenvFloat(key string, def float64) float64 {
+	v := strings.TrimSpace(os.Getenv(key))
+	if v == "" {
+		return def
+	}
+	parsed, err := strconv.ParseFloat(v, 64)
+	if err != nil || parsed < 0 || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+		return def
+	}
+	return parsed
+}
+
 // parseIDSet parses i d set.
 func parseIDSet(val string) map[int64]struct{} {
 	set := make(map[int64]struct{})
@@ -184,3 +347,16 @@ func parseIDSet(val string) map[int64]struct{} {
 	}
 	return set
 }
+
+// parseList parses a comma-separated list, skipping empty items.
+func parseList(val string) []string {
+	out := make([]string, 0)
+	for _, part := range strings.Split(val, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		out = append(out, part)
+	}
+	return out
+}
@@ -0,0 +1,27 @@
+package contentfilter
+
+import "time"
+
+// Config configures the default filter.
+type Config struct {
+	BlockWords        []string
+	HoldWords         []string
+	CommentsPerMinute int
+}
+
+// Default builds the filter used for comments and events: word lists, link and
+// phone heuristics, duplicate detection and comment flood control.
+func Default(history History, cfg Config) *Filter {
+	blockWords := append(append([]string{}, DefaultBlockWords...), cfg.BlockWords...)
+	return New(
+		WordRule("block_words", blockWords, Reject),
+		WordRule("hold_words", cfg.HoldWords, Hold),
+		FloodRule(history, KindComment, cfg.CommentsPerMinute, time.Minute),
+		DuplicateRule(history, KindComment, 24*time.Hour, Reject),
+		DuplicateRule(history, KindEvent, 24*time.Hour, Hold),
+		LinkRule(KindComment, 1),
+		PhoneRule(KindComment, 0),
+		LinkRule(KindEvent, 5),
+		PhoneRule(KindEvent, 2),
+	)
+}
@@ -0,0 +1,100 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+)
+
+// Decision is the outcome of a content check.
+type Decision string
+
+const (
+	Accept Decision = "accept"
+	Hold   Decision = "hold"
+	Reject Decision = "reject"
+)
+
+const (
+	KindComment = "comment"
+	KindEvent   = "event"
+)
+
+// Content is user text submitted for publication. Edited content skips the
+// duplicate and flood rules, which only apply to new submissions.
+type Content struct {
+	Kind    string
+	UserID  int64
+	EventID int64
+	Text    string
+	Edited  bool
+}
+
+// Verdict is a decision with the rule and fragment that caused it.
+type Verdict struct {
+	Decision Decision
+	Rule     string
+	Match    string
+}
+
+// Rule is a single check of the filter.
+type Rule interface {
+	Check(ctx context.Context, content Content) (Verdict, error)
+}
+
+// RuleFunc adapts a function to Rule.
+type RuleFunc func(ctx context.Context, content Content) (Verdict, error)
+
+// Check calls f.
+func (f RuleFunc) Check(ctx context.Context, content Content) (Verdict, error) {
+	return f(ctx, content)
+}
+
+// Filter runs rules in order and keeps the strictest verdict.
+type Filter struct {
+	rules []Rule
+}
+
+// New creates a filter from rules.
+func New(rules ...Rule) *Filter {
+	return &Filter{rules: rules}
+}
+
+// Check returns the strictest verdict of all rules; on ties the earlier rule wins.
+// A nil filter accepts everything.
+func (f *Filter) Check(ctx context.Context, content Content) (Verdict, error) {
+	out := Verdict{Decision: Accept}
+	if f == nil {
+		return out, nil
+	}
+	for _, rule := range f.rules {
+		verdict, err := rule.Check(ctx, content)
+		if err != nil {
+			return Verdict{}, err
+		}
+		if severity(verdict.Decision) > severity(out.Decision) {
+			out = verdict
+		}
+		if out.Decision == Reject {
+			break
+		}
+	}
+	return out, nil
+}
+
+// severity orders decisions from accept to reject.
+func severity(decision Decision) int {
+	switch decision {
+	case Hold:
+		return 1
+	case Reject:
+		return 2
+	default:
+		return 0
+	}
+}
+
+// NormalizeText lower-cases text and collapses whitespace; duplicate detection
+// compares texts in this form.
+func NormalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
@@ -0,0 +1,111 @@
+package contentfilter
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeHistory implements History with fixed counts.
+type fakeHistory struct {
+	recent     int
+	duplicates int
+}
+
+// CountUserContentSince returns the fixed recent count.
+func (f fakeHistory) CountUserContentSince(context.Context, string, int64, time.Time) (int, error) {
+	return f.recent, nil
+}
+
+// CountUserDuplicatesSince returns the fixed duplicate count.
+func (f fakeHistory) CountUserDuplicatesSince(context.Context, string, int64, string, time.Time) (int, error) {
+	return f.duplicates, nil
+}
+
+// TestWordRuleObfuscations verifies word rule obfuscations behavior.
+func TestWordRuleObfuscations(t *testing.T) {
+	rule := WordRule("block_words", DefaultBlockWords, Reject)
+	blocked := []string{
+		"what the FUUUCK",
+		"ну ты с у к а",
+		"х.у.й",
+		"cyka blyat",
+		"п1зд@",
+		"ёбаный xyй",
+		"страхуйся, а то нахуй",
+	}
+	for _, text := range blocked {
+		verdict, err := rule.Check(context.Background(), Content{Kind: KindComment, Text: text})
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", text, err)
+		}
+		if verdict.Decision != Reject {
+			t.Fatalf("%q: unexpected verdict %+v", text, verdict)
+		}
+	}
+	accepted := []string{
+		"Хлебать суп",
+		"Scunthorpe class",
+		"сукно и учеба",
+		"Классный концерт!",
+		"Он всегда страхуется",
+		"страхуя друг друга",
+		"застрахуй машину",
+		"не употреблять алкоголь",
+		"не оскорблять артистов",
+	}
+	for _, text := range accepted {
+		verdict, _ := rule.Check(context.Background(), Content{Kind: KindComment, Text: text})
+		if verdict.Decision != Accept {
+			t.Fatalf("%q: expected accept, got %+v", text, verdict)
+		}
+	}
+}
+
+// TestDefaultFilterSpamHeuristics verifies default filter spam heuristics behavior.
+func TestDefaultFilterSpamHeuristics(t *testing.T) {
+	filter := Default(fakeHistory{}, Config{HoldWords: []string{"казино"}})
+	cases := []struct {
+		content Content
+		want    Decision
+		rule    string
+	}{
+		{Content{Kind: KindComment, Text: "see https://a.example and t.me/spam"}, Hold, "links"},
+		{Content{Kind: KindComment, Text: "звоните +7 (999) 123-45-67"}, Hold, "phones"},
+		{Content{Kind: KindEvent, Text: "Jam\nTickets: +7 999 123 45 67, site.ru"}, Accept, ""},
+		{Content{Kind: KindComment, Text: "лучшее КАЗИНО тут"}, Hold, "hold_words"},
+		{Content{Kind: KindComment, Text: "see you at 19:00"}, Accept, ""},
+	}
+	for _, tc := range cases {
+		verdict, err := filter.Check(context.Background(), tc.content)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.content.Text, err)
+		}
+		if verdict.Decision != tc.want || verdict.Rule != tc.rule {
+			t.Fatalf("%q: unexpected verdict %+v", tc.content.Text, verdict)
+		}
+	}
+}
+
+// TestDefaultFilterHistoryRules verifies default filter history rules behavior.
+func TestDefaultFilterHistoryRules(t *testing.T) {
+	comment := Content{Kind: KindComment, Text: "hello"}
+
+	verdict, _ := Default(fakeHistory{recent: 5}, Config{CommentsPerMinute: 5}).Check(context.Background(), comment)
+	if verdict.Decision != Reject || verdict.Rule != "flood" {
+		t.Fatalf("expected flood reject, got %+v", verdict)
+	}
+	verdict, _ = Default(fakeHistory{duplicates: 1}, Config{}).Check(context.Background(), comment)
+	if verdict.Decision != Reject || verdict.Rule != "duplicate" {
+		t.Fatalf("expected duplicate reject, got %+v", verdict)
+	}
+	verdict, _ = Default(fakeHistory{duplicates: 1}, Config{}).Check(context.Background(), Content{Kind: KindEvent, Text: "hello"})
+	if verdict.Decision != Hold || verdict.Rule != "duplicate" {
+		t.Fatalf("expected duplicate hold for events, got %+v", verdict)
+	}
+	comment.Edited = true
+	verdict, _ = Default(fakeHistory{recent: 5, duplicates: 1}, Config{CommentsPerMinute: 5}).Check(context.Background(), comment)
+	if verdict.Decision != Accept {
+		t.Fatalf("expected edits to skip history rules, got %+v", verdict)
+	}
+}
@@ -0,0 +1,100 @@
+package contentfilter
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// linkPattern matches URLs, bare domains and Telegram links.
+var linkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+|\b(?:t\.me|telegram\.me)/\S+|\b[a-z0-9][a-z0-9-]*\.(?:ru|com|net|org|io|me|info|biz|xyz|online|site|shop|top|club|click|link)\b(?:/\S*)?`)
+
+// phonePattern matches phone numbers written with common separators.
+var phonePattern = regexp.MustCompile(`\+?\d[\d\s\-().]{8,}\d`)
+
+// History counts earlier submissions of a user; the repository implements it.
+type History interface {
+	CountUserContentSince(ctx context.Context, kind string, userID int64, since time.Time) (int, error)
+	CountUserDuplicatesSince(ctx context.Context, kind string, userID int64, text string, since time.Time) (int, error)
+}
+
+// LinkRule holds content of kind with more than maxLinks links.
+func LinkRule(kind string, maxLinks int) Rule {
+	return RuleFunc(func(_ context.Context, content Content) (Verdict, error) {
+		if content.Kind != kind {
+			return Verdict{Decision: Accept}, nil
+		}
+		links := linkPattern.FindAllString(content.Text, -1)
+		if len(links) > maxLinks {
+			return Verdict{Decision: Hold, Rule: "links", Match: links[maxLinks]}, nil
+		}
+		return Verdict{Decision: Accept}, nil
+	})
+}
+
+// PhoneRule holds content of kind with more than maxPhones phone numbers.
+func PhoneRule(kind string, maxPhones int) Rule {
+	return RuleFunc(func(_ context.Context, content Content) (Verdict, error) {
+		if content.Kind != kind {
+			return Verdict{Decision: Accept}, nil
+		}
+		phones := make([]string, 0)
+		for _, match := range phonePattern.FindAllString(content.Text, -1) {
+			if digits := countDigits(match); digits >= 10 && digits <= 15 {
+				phones = append(phones, match)
+			}
+		}
+		if len(phones) > maxPhones {
+			return Verdict{Decision: Hold, Rule: "phones", Match: phones[maxPhones]}, nil
+		}
+		return Verdict{Decision: Accept}, nil
+	})
+}
+
+// DuplicateRule returns decision when the user already submitted the same
+// text of kind within window.
+func DuplicateRule(history History, kind string, window time.Duration, decision Decision) Rule {
+	return RuleFunc(func(ctx context.Context, content Content) (Verdict, error) {
+		if content.Kind != kind || content.Edited {
+			return Verdict{Decision: Accept}, nil
+		}
+		count, err := history.CountUserDuplicatesSince(ctx, kind, content.UserID, NormalizeText(content.Text), time.Now().Add(-window))
+		if err != nil {
+			return Verdict{}, err
+		}
+		if count > 0 {
+			return Verdict{Decision: decision, Rule: "duplicate", Match: fmt.Sprintf("%d within %s", count, window)}, nil
+		}
+		return Verdict{Decision: Accept}, nil
+	})
+}
+
+// FloodRule rejects content of kind once the user submitted limit items within window.
+// A non-positive limit disables the rule.
+func FloodRule(history History, kind string, limit int, window time.Duration) Rule {
+	return RuleFunc(func(ctx context.Context, content Content) (Verdict, error) {
+		if content.Kind != kind || content.Edited || limit <= 0 {
+			return Verdict{Decision: Accept}, nil
+		}
+		count, err := history.CountUserContentSince(ctx, kind, content.UserID, time.Now().Add(-window))
+		if err != nil {
+			return Verdict{}, err
+		}
+		if count >= limit {
+			return Verdict{Decision: Reject, Rule: "flood", Match: fmt.Sprintf("%d within %s", count, window)}, nil
+		}
+		return Verdict{Decision: Accept}, nil
+	})
+}
+
+// countDigits returns the number of ASCII digits in value.
+func countDigits(value string) int {
+	count := 0
+	for _, ch := range value {
+		if ch >= '0' && ch <= '9' {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,177 @@
+package contentfilter
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// DefaultBlockWords are profanity stems rejected by default. Entries match the
+// start of a word; a leading "*" matches anywhere inside a word. A leading "!"
+// marks a fragment of ordinary words that is never matched, so "*хуй" skips
+// «застрахуй» and "*блят" skips «употреблять».
+var DefaultBlockWords = []string{
+	"*fuck", "shit", "bitch", "cunt", "*asshole",
+	"*хуй", "*хуе", "*хуя", "*пизд", "*бляд", "*блят",
+	"!страху", "!требл", "!скорбл",
+	"ебан", "ебат", "ебал", "заеб", "наеб", "выеб", "уеб", "доеб", "отъеб", "въеб",
+	"сука", "суки", "мудак", "мудил", "пидор", "пидар", "гандон",
+}
+
+// leetToLatin maps digits and symbols used in place of Latin letters.
+var leetToLatin = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's',
+}
+
+// leetToCyrillic maps digits and symbols used in place of Cyrillic letters.
+var leetToCyrillic = map[rune]rune{
+	'0': 'о', '1': 'и', '3': 'з', '4': 'ч', '6': 'б', '@': 'а',
+}
+
+// latinToCyrillic maps Latin letters that look like Cyrillic ones.
+var latinToCyrillic = map[rune]rune{
+	'a': 'а', 'b': 'в', 'c': 'с', 'e': 'е', 'h': 'н', 'k': 'к', 'm': 'м',
+	'o': 'о', 'p': 'р', 't': 'т', 'u': 'и', 'x': 'х', 'y': 'у',
+}
+
+// cyrillicToLatin maps Cyrillic letters that look like Latin ones.
+var cyrillicToLatin = map[rune]rune{
+	'а': 'a', 'в': 'b', 'с': 'c', 'е': 'e', 'н': 'h', 'к': 'k', 'м': 'm',
+	'о': 'o', 'р': 'p', 'т': 't', 'х': 'x', 'у': 'y',
+}
+
+// wordEntry is a normalized word list entry.
+type wordEntry struct {
+	stem     string
+	anywhere bool
+}
+
+// wordRule matches words from a list, including common obfuscations.
+type wordRule struct {
+	name     string
+	entries  []wordEntry
+	allowed  []string
+	decision Decision
+}
+
+// WordRule creates a rule that returns decision when text contains one of words.
+func WordRule(name string, words []string, decision Decision) Rule {
+	rule := &wordRule{name: name, decision: decision}
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if strings.HasPrefix(word, "!") {
+			if allowed := collapseRepeats(strings.ReplaceAll(strings.TrimPrefix(word, "!"), "ё", "е")); allowed != "" {
+				rule.allowed = append(rule.allowed, allowed)
+			}
+			continue
+		}
+		anywhere := strings.HasPrefix(word, "*")
+		stem := collapseRepeats(strings.ReplaceAll(strings.TrimPrefix(word, "*"), "ё", "е"))
+		if stem == "" {
+			continue
+		}
+		rule.entries = append(rule.entries, wordEntry{stem: stem, anywhere: anywhere})
+	}
+	return rule
+}
+
+// Check implements Rule.
+func (r *wordRule) Check(_ context.Context, content Content) (Verdict, error) {
+	if len(r.entries) == 0 {
+		return Verdict{Decision: Accept}, nil
+	}
+	for _, token := range wordTokens(content.Text) {
+		for _, variant := range tokenVariants(token) {
+			variant = maskAllowed(variant, r.allowed)
+			for _, entry := range r.entries {
+				if entry.anywhere && strings.Contains(variant, entry.stem) || strings.HasPrefix(variant, entry.stem) {
+					return Verdict{Decision: r.decision, Rule: r.name, Match: token}, nil
+				}
+			}
+		}
+	}
+	return Verdict{Decision: Accept}, nil
+}
+
+// wordTokens splits text into lower-case words with separators inside words
+// removed ("х.у.й" becomes "хуй"). Runs of single letters separated by spaces
+// are also joined into one token.
+func wordTokens(text string) []string {
+	text = strings.ReplaceAll(strings.ToLower(text), "ё", "е")
+	fields := strings.Fields(text)
+	out := make([]string, 0, len(fields))
+	var letters strings.Builder
+	flushLetters := func() {
+		if len([]rune(letters.String())) > 2 {
+			out = append(out, letters.String())
+		}
+		letters.Reset()
+	}
+	for _, field := range fields {
+		var b strings.Builder
+		for _, ch := range field {
+			if unicode.IsLetter(ch) || unicode.IsDigit(ch) || ch == '@' || ch == '$' {
+				b.WriteRune(ch)
+			}
+		}
+		token := b.String()
+		if token == "" {
+			continue
+		}
+		if len([]rune(token)) == 1 {
+			letters.WriteString(token)
+			continue
+		}
+		flushLetters()
+		out = append(out, token)
+	}
+	flushLetters()
+	return out
+}
+
+// tokenVariants returns the Latin and Cyrillic readings of a token with
+// look-alike characters replaced and repeated letters collapsed.
+func tokenVariants(token string) []string {
+	latin := []rune(token)
+	cyrillic := []rune(token)
+	for i, ch := range latin {
+		if mapped, ok := leetToLatin[ch]; ok {
+			latin[i] = mapped
+		} else if mapped, ok := cyrillicToLatin[ch]; ok {
+			latin[i] = mapped
+		}
+	}
+	for i, ch := range cyrillic {
+		if mapped, ok := leetToCyrillic[ch]; ok {
+			cyrillic[i] = mapped
+		} else if mapped, ok := latinToCyrillic[ch]; ok {
+			cyrillic[i] = mapped
+		}
+	}
+	return []string{collapseRepeats(string(latin)), collapseRepeats(string(cyrillic))}
+}
+
+// maskAllowed blanks out allowed fragments of a token so that stems inside
+// them don't match.
+func maskAllowed(token string, allowed []string) string {
+	for _, fragment := range allowed {
+		if strings.Contains(token, fragment) {
+			token = strings.ReplaceAll(token, fragment, strings.Repeat("_", len([]rune(fragment))))
+		}
+	}
+	return token
+}
+
+// collapseRepeats replaces runs of the same character with one character.
+func collapseRepeats(value string) string {
+	var b strings.Builder
+	var prev rune
+	for i, ch := range value {
+		if i > 0 && ch == prev {
+			continue
+		}
+		b.WriteRune(ch)
+		prev = ch
+	}
+	return b.String()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"log/slog"
@@ -10,8 +11,10 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"gigme/backend/internal/contentfilter"
 	"gigme/backend/internal/http/middleware"
 	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
@@ -39,6 +42,8 @@ type updateEventRequest struct {
 	ContactWechat      *string  `json:"contactWechat"`
 	ContactFbMessenger *string  `json:"contactFbMessenger"`
 	ContactSnapchat    *string  `json:"contactSnapchat"`
+	Scope              *string  `json:"scope"`
+	RRule              *string  `json:"rrule"`
 }
 
 // adminBlockRequest represents admin block request.
@@ -56,6 +61,7 @@ type adminUsersResponse struct {
 type adminUserDetailResponse struct {
 	User          models.AdminUser   `json:"user"`
 	CreatedEvents []models.UserEvent `json:"createdEvents"`
+	Bans          []models.UserBan   `json:"bans"`
 }
 
 // broadcastButton represents broadcast button.
@@ -107,6 +113,15 @@ func (h *Handler) requireAdmin(logger *slog.Logger, w http.ResponseWriter, r *ht
 	return telegramID, true
 }
 
+// isAdminContext reports whether the request context belongs to an admin.
+func (h *Handler) isAdminContext(ctx context.Context) bool {
+	if isAdmin, ok := middleware.IsAdminFromContext(ctx); ok && isAdmin {
+		return true
+	}
+	telegramID, ok := middleware.TelegramIDFromContext(ctx)
+	return ok && h.isAdminTelegramID(telegramID)
+}
+
 // ListAdminUsers lists admin users.
 func (h *Handler) ListAdminUsers(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
@@ -180,10 +195,16 @@ func (h *Handler) GetAdminUser(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
-	writeJSON(w, http.StatusOK, adminUserDetailResponse{User: user, CreatedEvents: events})
+	bans, err := h.repo.ListUserBans(ctx, id)
+	if err != nil {
+		logger.Error("action", "action", "admin_get_user", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, adminUserDetailResponse{User: user, CreatedEvents: events, Bans: bans})
 }
 
-// BlockUser handles block user.
+// BlockUser bans the user permanently from the whole app.
 func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
 	if _, ok := h.requireAdmin(logger, w, r, "admin_block_user"); !ok {
@@ -201,9 +222,15 @@ func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid json")
 		return
 	}
+	adminUserID, _ := middleware.UserIDFromContext(r.Context())
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	if err := h.repo.BlockUser(ctx, id, strings.TrimSpace(req.Reason)); err != nil {
+	if err := h.repo.BlockUser(ctx, id, strings.TrimSpace(req.Reason), adminUserID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "admin_block_user", "status", "not_found", "user_id", id)
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
 		logger.Error("action", "action", "admin_block_user", "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
@@ -211,7 +238,7 @@ func (h *Handler) BlockUser(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 }
 
-// UnblockUser handles unblock user.
+// UnblockUser lifts all full bans of the user.
 func (h *Handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
 	if _, ok := h.requireAdmin(logger, w, r, "admin_unblock_user"); !ok {
@@ -223,9 +250,10 @@ func (h *Handler) UnblockUser(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid user id")
 		return
 	}
+	adminUserID, _ := middleware.UserIDFromContext(r.Context())
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	if err := h.repo.UnblockUser(ctx, id); err != nil {
+	if err := h.repo.UnblockUser(ctx, id, adminUserID); err != nil {
 		logger.Error("action", "action", "admin_unblock_user", "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
@@ -451,12 +479,10 @@ func (h *Handler) HideEvent(w http.ResponseWriter, r *http.Request) {
 }
 
 // UpdateEventAdmin updates event admin.
+// Co-hosts of the event may use it as well, except for splitting a series
+// (scope "following"), which stays with the owner and admins.
 func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_update_event"); !ok {
-		return
-	}
-
 	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 	if err != nil {
 		logger.Warn("action", "action", "admin_update_event", "status", "invalid_event_id")
@@ -464,6 +490,13 @@ func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	actorID, ok := h.requireEventPermission(ctx, logger, w, "admin_update_event", id, eventPermEdit)
+	if !ok {
+		return
+	}
+
 	var req updateEventRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		logger.Warn("action", "action", "admin_update_event", "status", "invalid_json")
@@ -485,15 +518,29 @@ func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 		req.ContactWhatsapp != nil ||
 		req.ContactWechat != nil ||
 		req.ContactFbMessenger != nil ||
-		req.ContactSnapchat != nil
+		req.ContactSnapchat != nil ||
+		req.RRule != nil
 	if !hasUpdate {
 		logger.Warn("action", "action", "admin_update_event", "status", "no_updates")
 		writeError(w, http.StatusBadRequest, "no updates provided")
 		return
 	}
-
-	ctx, cancel := h.withTimeout(r.Context())
-	defer cancel()
+	scope, ok := normalizeEventEditScope(req.Scope)
+	if !ok {
+		logger.Warn("action", "action", "admin_update_event", "status", "invalid_scope")
+		writeError(w, http.StatusBadRequest, "invalid scope")
+		return
+	}
+	if req.RRule != nil && scope != eventEditScopeFollowing {
+		logger.Warn("action", "action", "admin_update_event", "status", "rrule_requires_following_scope")
+		writeError(w, http.StatusBadRequest, "rrule can only be changed for following occurrences")
+		return
+	}
+	if scope == eventEditScopeFollowing {
+		if _, ok := h.requireEventPermission(ctx, logger, w, "admin_update_event", id, eventPermSeries); !ok {
+			return
+		}
+	}
 
 	existing, err := h.repo.GetEventByID(ctx, id)
 	if err != nil {
@@ -506,6 +553,11 @@ func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
+	if scope == eventEditScopeFollowing && existing.SeriesID == nil {
+		logger.Warn("action", "action", "admin_update_event", "status", "not_recurring", "event_id", id)
+		writeError(w, http.StatusBadRequest, "event is not recurring")
+		return
+	}
 
 	updated := existing
 
@@ -661,7 +713,73 @@ func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 	}
 	updated.EndsAt = endsAt
 
+	var verdict contentfilter.Verdict
+	content := contentfilter.Content{Kind: contentfilter.KindEvent, UserID: actorID, EventID: id, Text: updated.Title + "\n" + updated.Description, Edited: true}
+	if req.Title != nil || req.Description != nil {
+		if verdict, ok = h.filterContent(ctx, logger, w, "admin_update_event", content); !ok {
+			return
+		}
+	}
+	held := verdict.Decision == contentfilter.Hold
+
 	replaceMedia := req.Media != nil
+	if scope == eventEditScopeFollowing {
+		previous, err := h.loadSeriesOccurrencesFrom(ctx, existing)
+		if err != nil {
+			logger.Error("action", "action", "admin_update_event", "status", "snapshot_failed", "event_id", id, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+			return
+		}
+		seriesID, canceledIDs, err := h.updateEventSeriesFollowing(ctx, actorID, existing, updated, req.Media, replaceMedia, req.RRule)
+		if err != nil {
+			switch {
+			case errors.Is(err, repository.ErrSeriesOccurrenceHasSales):
+				logger.Warn("action", "action", "admin_update_event", "status", "dropped_occurrence_has_sales", "event_id", id)
+				writeError(w, http.StatusConflict, "dropped occurrences have paid orders or tickets")
+			case errors.Is(err, errInvalidRecurrence):
+				logger.Warn("action", "action", "admin_update_event", "status", "invalid_recurrence", "event_id", id)
+				writeError(w, http.StatusBadRequest, "invalid recurrence")
+			case errors.Is(err, errNoOccurrences):
+				logger.Warn("action", "action", "admin_update_event", "status", "no_occurrences", "event_id", id)
+				writeError(w, http.StatusBadRequest, "recurrence has no occurrences")
+			case errors.Is(err, errNotRecurring), errors.Is(err, pgx.ErrNoRows):
+				logger.Warn("action", "action", "admin_update_event", "status", "series_not_found", "event_id", id)
+				writeError(w, http.StatusNotFound, "event series not found")
+			default:
+				logger.Error("action", "action", "admin_update_event", "status", "db_error", "event_id", id, "error", err)
+				writeError(w, http.StatusInternalServerError, "db error")
+			}
+			return
+		}
+		h.notifyDroppedOccurrences(ctx, logger, "admin_update_event", previous, canceledIDs, actorID)
+		if held {
+			dropped := make(map[int64]bool, len(canceledIDs))
+			for _, canceledID := range canceledIDs {
+				dropped[canceledID] = true
+			}
+			for _, before := range previous {
+				if !dropped[before.ID] {
+					h.holdEditedEvent(ctx, logger, content, verdict, before.ID)
+				}
+			}
+		}
+		for _, before := range previous {
+			after, err := h.repo.GetEventByID(ctx, before.ID)
+			if err != nil {
+				logger.Warn("action", "action", "admin_update_event", "status", "reload_failed", "event_id", before.ID, "error", err)
+				continue
+			}
+			h.applyEventChanges(ctx, logger, "admin_update_event", before, after, actorID)
+		}
+		logger.Info("action", "action", "admin_update_event", "status", "success", "event_id", id, "scope", scope, "series_id", seriesID, "replace_media", replaceMedia, "held", held)
+		resp := map[string]interface{}{"ok": true, "seriesId": seriesID}
+		if held {
+			resp["held"] = true
+		}
+		writeJSON(w, http.StatusOK, resp)
+		return
+	}
+
 	if err := h.repo.UpdateEventWithMedia(ctx, updated, req.Media, replaceMedia); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.Warn("action", "action", "admin_update_event", "status", "not_found", "event_id", id)
@@ -672,9 +790,32 @@ func (h *Handler) UpdateEventAdmin(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
+	if existing.SeriesID != nil {
+		if err := h.repo.SetEventSeriesException(ctx, id); err != nil {
+			logger.Warn("action", "action", "admin_update_event", "status", "series_exception_failed", "event_id", id, "error", err)
+		}
+	}
+	h.applyEventChanges(ctx, logger, "admin_update_event", existing, updated, actorID)
+	if held {
+		h.holdEditedEvent(ctx, logger, content, verdict, id)
+	}
 
-	logger.Info("action", "action", "admin_update_event", "status", "success", "event_id", id, "replace_media", replaceMedia)
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+	logger.Info("action", "action", "admin_update_event", "status", "success", "event_id", id, "scope", scope, "replace_media", replaceMedia, "held", held)
+	resp := map[string]interface{}{"ok": true}
+	if held {
+		resp["held"] = true
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// holdEditedEvent hides an event whose edit the content filter held for review.
+func (h *Handler) holdEditedEvent(ctx context.Context, logger *slog.Logger, content contentfilter.Content, verdict contentfilter.Verdict, eventID int64) {
+	if err := h.repo.SetEventHidden(ctx, eventID, true); err != nil {
+		logger.Error("action", "action", "admin_update_event", "status", "hold_failed", "event_id", eventID, "error", err)
+		return
+	}
+	content.EventID = eventID
+	h.logContentFilter(ctx, logger, "admin_update_event", content, verdict, &eventID)
 }
 
 // DeleteEventAdmin deletes event admin.
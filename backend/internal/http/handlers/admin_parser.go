@@ -522,6 +522,8 @@ func (h *Handler) ImportParsedEvent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
+	payload := h.eventCreatedPayload(r, eventID, event.Title, event.StartsAt, event.AddressLabel, media)
+	h.alertSavedSearches(ctx, logger, "admin_parser_import_event", eventID, payload)
 	writeJSON(w, http.StatusOK, map[string]interface{}{
 		"ok":       true,
 		"eventId":  eventID,
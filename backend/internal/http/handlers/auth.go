@@ -52,6 +52,12 @@ func (h *Handler) AuthTelegram(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if isNew {
+		if _, err := h.repo.MatchNewUserContacts(ctx, stored.ID, stored.TelegramID, h.cfg.HMACSecret); err != nil {
+			logger.Warn("action", "action", "auth_telegram", "status", "match_contacts_failed", "error", err)
+		}
+	}
+
 	token, err := auth.SignAccessToken(h.cfg.JWTSecret, stored.ID, stored.TelegramID, isNew, false)
 	if err != nil {
 		logger.Error("action", "action", "auth_telegram", "status", "token_error", "error", err)
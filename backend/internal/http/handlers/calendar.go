@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/ical"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/recurrence"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	calendarProdID          = "-//Gigme//Events//EN"
+	calendarFeedName        = "Gigme"
+	calendarFeedRefresh     = time.Hour
+	calendarFeedLookback    = 30 * 24 * time.Hour
+	calendarFeedLimit       = 500
+	calendarDefaultDuration = 2 * time.Hour
+	maxCalendarTokenLength  = 64
+)
+
+// EventCalendar returns a single event as an iCalendar file.
+func (h *Handler) EventCalendar(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "event_calendar", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	accessKey := accessKeyFromRequest(r)
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil || event.IsHidden || !h.allowPrivateEvent(ctx, event, userID, accessKey) {
+		logger.Warn("action", "action", "event_calendar", "status", "not_found", "event_id", eventID)
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+
+	body := ical.Calendar{
+		ProdID: calendarProdID,
+		Events: []ical.Event{h.calendarEvent(event)},
+	}.Encode()
+	writeCalendar(w, fmt.Sprintf("event-%d.ics", eventID), body)
+}
+
+// CalendarFeedInfo returns the user's calendar feed url, creating the token on first use.
+func (h *Handler) CalendarFeedInfo(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "calendar_feed_info", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	candidate, err := generateCalendarToken()
+	if err != nil {
+		logger.Error("action", "action", "calendar_feed_info", "status", "token_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "token error")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	token, err := h.repo.GetOrCreateCalendarToken(ctx, userID, candidate)
+	if err != nil {
+		logger.Error("action", "action", "calendar_feed_info", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, h.calendarFeedResponse(token))
+}
+
+// RotateCalendarFeed replaces the user's calendar feed token, revoking the old url.
+func (h *Handler) RotateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "calendar_feed_rotate", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	token, err := generateCalendarToken()
+	if err != nil {
+		logger.Error("action", "action", "calendar_feed_rotate", "status", "token_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "token error")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	if err := h.repo.RotateCalendarToken(ctx, userID, token); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("action", "action", "calendar_feed_rotate", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "calendar_feed_rotate", "status", "success", "user_id", userID)
+	writeJSON(w, http.StatusOK, h.calendarFeedResponse(token))
+}
+
+// CalendarFeed returns the events a token owner joined or bought tickets for.
+func (h *Handler) CalendarFeed(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	token := strings.TrimSpace(chi.URLParam(r, "token"))
+	if token == "" || len(token) > maxCalendarTokenLength {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	userID, err := h.repo.GetUserIDByCalendarToken(ctx, token)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("action", "action", "calendar_feed", "status", "db_error", "error", err)
+		}
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	events, err := h.repo.ListCalendarEvents(ctx, userID, time.Now().Add(-calendarFeedLookback), calendarFeedLimit)
+	if err != nil {
+		logger.Error("action", "action", "calendar_feed", "status", "db_error", "user_id", userID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+
+	items := make([]ical.Event, 0, len(events))
+	for _, event := range events {
+		items = append(items, h.calendarEvent(event))
+	}
+	body := ical.Calendar{
+		ProdID:          calendarProdID,
+		Name:            calendarFeedName,
+		RefreshInterval: calendarFeedRefresh,
+		Events:          items,
+	}.Encode()
+	writeCalendar(w, "gigme.ics", body)
+}
+
+// calendarEvent converts an event into a calendar entry.
+// Hidden events are exported as cancelled; the revision becomes SEQUENCE.
+func (h *Handler) calendarEvent(event models.Event) ical.Event {
+	link := buildEventURL(normalizeWebAppBaseURL(h.cfg.BaseURL), event.ID, calendarAccessKey(event))
+	description := strings.TrimSpace(event.Description)
+	if link != "" {
+		if description != "" {
+			description += "\n\n"
+		}
+		description += link
+	}
+	endsAt := event.StartsAt.Add(calendarDefaultDuration)
+	if event.EndsAt != nil {
+		endsAt = *event.EndsAt
+	}
+	return ical.Event{
+		UID:         fmt.Sprintf("event-%d@%s", event.ID, calendarUIDHost(h.cfg.BaseURL)),
+		Sequence:    event.Revision,
+		Start:       event.StartsAt,
+		End:         endsAt,
+		Location:    recurrence.LoadLocation(event.Timezone),
+		Summary:     event.Title,
+		Description: description,
+		Address:     strings.TrimSpace(event.AddressLabel),
+		Lat:         event.Lat,
+		Lng:         event.Lng,
+		HasGeo:      true,
+		URL:         link,
+		Cancelled:   event.IsHidden || event.IsCanceled(),
+		Created:     event.CreatedAt,
+		Updated:     event.UpdatedAt,
+	}
+}
+
+// calendarFeedResponse builds calendar feed response.
+func (h *Handler) calendarFeedResponse(token string) map[string]interface{} {
+	feedURL := strings.TrimRight(strings.TrimSpace(h.cfg.APIPublicURL), "/") + "/calendar/" + token + ".ics"
+	resp := map[string]interface{}{
+		"token": token,
+		"url":   feedURL,
+	}
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Host != "" {
+		parsed.Scheme = "webcal"
+		resp["webcalUrl"] = parsed.String()
+	}
+	return resp
+}
+
+// calendarAccessKey returns the access key to embed into a private event link.
+func calendarAccessKey(event models.Event) string {
+	if !event.IsPrivate {
+		return ""
+	}
+	return event.AccessKey
+}
+
+// calendarUIDHost returns the host part of calendar UIDs.
+func calendarUIDHost(baseURL string) string {
+	if parsed, err := url.Parse(strings.TrimSpace(baseURL)); err == nil && parsed.Hostname() != "" {
+		return parsed.Hostname()
+	}
+	return "gigme"
+}
+
+// generateCalendarToken generates a calendar feed token.
+func generateCalendarToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// writeCalendar writes an iCalendar response.
+func writeCalendar(w http.ResponseWriter, filename string, body []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
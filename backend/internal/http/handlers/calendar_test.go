@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestCalendarEventBuildsDeepLink verifies calendar event builds deep link behavior.
+func TestCalendarEventBuildsDeepLink(t *testing.T) {
+	h := New(nil, nil, nil, nil, &config.Config{BaseURL: "https://gigme.example/app"}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	startsAt := time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC)
+	item := h.calendarEvent(models.Event{
+		ID:          42,
+		Title:       "Private jam",
+		Description: "Bring a guitar",
+		StartsAt:    startsAt,
+		IsPrivate:   true,
+		AccessKey:   "secret",
+		IsHidden:    true,
+		Revision:    2,
+		Timezone:    "Europe/Moscow",
+	})
+
+	if item.UID != "event-42@gigme.example" {
+		t.Fatalf("unexpected uid: %q", item.UID)
+	}
+	if item.URL != "https://gigme.example/space_app?eventKey=secret#eventId=42" {
+		t.Fatalf("unexpected url: %q", item.URL)
+	}
+	if !strings.HasSuffix(item.Description, "\n\n"+item.URL) {
+		t.Fatalf("expected link in description: %q", item.Description)
+	}
+	if !item.End.Equal(startsAt.Add(calendarDefaultDuration)) {
+		t.Fatalf("expected default duration, got %s", item.End)
+	}
+	if !item.Cancelled || item.Sequence != 2 {
+		t.Fatalf("expected cancelled revision 2, got cancelled=%v sequence=%d", item.Cancelled, item.Sequence)
+	}
+}
+
+// TestCalendarFeedRouteToken verifies calendar feed route token behavior.
+func TestCalendarFeedRouteToken(t *testing.T) {
+	var got string
+	r := chi.NewRouter()
+	r.Get("/calendar/{token}.ics", func(w http.ResponseWriter, r *http.Request) {
+		got = chi.URLParam(r, "token")
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/calendar/abc-DEF_1.ics", nil))
+	if got != "abc-DEF_1" {
+		t.Fatalf("unexpected token: %q", got)
+	}
+}
@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gigme/backend/internal/contentfilter"
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// resolveContentRequest represents resolve content request.
+type resolveContentRequest struct {
+	Status string `json:"status"`
+}
+
+// adminContentFilterResponse represents admin content filter response.
+type adminContentFilterResponse struct {
+	Items []models.ContentFilterEntry `json:"items"`
+	Total int                         `json:"total"`
+}
+
+// contentRejection returns the HTTP status and message for rejected content.
+func contentRejection(verdict contentfilter.Verdict) (int, string) {
+	switch verdict.Rule {
+	case "flood":
+		return http.StatusTooManyRequests, "too many messages, try again later"
+	case "duplicate":
+		return http.StatusConflict, "duplicate message"
+	default:
+		return http.StatusBadRequest, "content rejected by moderation rules"
+	}
+}
+
+// filterContent runs the content filter. Rejected content is logged for audit
+// and answered with an error, in which case ok is false. Filter errors are
+// logged and the content is accepted.
+func (h *Handler) filterContent(ctx context.Context, logger *slog.Logger, w http.ResponseWriter, action string, content contentfilter.Content) (contentfilter.Verdict, bool) {
+	verdict, err := h.contentFilter.Check(ctx, content)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "content_filter_failed", "error", err)
+		return contentfilter.Verdict{Decision: contentfilter.Accept}, true
+	}
+	if verdict.Decision != contentfilter.Reject {
+		return verdict, true
+	}
+	h.logContentFilter(ctx, logger, action, content, verdict, nil)
+	status, message := contentRejection(verdict)
+	logger.Warn("action", "action", action, "status", "content_rejected", "rule", verdict.Rule)
+	writeError(w, status, message)
+	return verdict, false
+}
+
+// logContentFilter records a held or rejected item with the matched rule.
+func (h *Handler) logContentFilter(ctx context.Context, logger *slog.Logger, action string, content contentfilter.Content, verdict contentfilter.Verdict, targetID *int64) {
+	entry := models.ContentFilterEntry{
+		Kind:     content.Kind,
+		TargetID: targetID,
+		UserID:   content.UserID,
+		Content:  content.Text,
+		Decision: string(verdict.Decision),
+		Rule:     verdict.Rule,
+		Match:    verdict.Match,
+	}
+	if content.EventID != 0 {
+		eventID := content.EventID
+		entry.EventID = &eventID
+	}
+	if _, err := h.repo.LogContentFilter(ctx, entry); err != nil {
+		logger.Warn("action", "action", action, "status", "content_log_failed", "error", err)
+	}
+}
+
+// ListAdminContentFilter lists content held or rejected by the content filter.
+func (h *Handler) ListAdminContentFilter(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_list_content_filter"); !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	var decision *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("decision")); raw != "" {
+		if raw != string(contentfilter.Hold) && raw != string(contentfilter.Reject) {
+			writeError(w, http.StatusBadRequest, "invalid decision")
+			return
+		}
+		decision = &raw
+	}
+	var status *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+		switch raw {
+		case models.ContentStatusPending, models.ContentStatusApproved, models.ContentStatusRejected:
+			status = &raw
+		default:
+			writeError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+	var kind *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("kind")); raw != "" {
+		if raw != contentfilter.KindComment && raw != contentfilter.KindEvent {
+			writeError(w, http.StatusBadRequest, "invalid kind")
+			return
+		}
+		kind = &raw
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListContentFilterEntries(ctx, decision, status, kind, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "admin_list_content_filter", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, adminContentFilterResponse{Items: items, Total: total})
+}
+
+// ResolveAdminContentFilter approves or rejects held content. Approved comments
+// and events are published with the notifications they skipped while held.
+func (h *Handler) ResolveAdminContentFilter(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_resolve_content"); !ok {
+		return
+	}
+	entryID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_resolve_content", "status", "invalid_id")
+		writeError(w, http.StatusBadRequest, "invalid id")
+		return
+	}
+	var req resolveContentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "admin_resolve_content", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.Status != models.ContentStatusApproved && req.Status != models.ContentStatusRejected {
+		logger.Warn("action", "action", "admin_resolve_content", "status", "invalid_status")
+		writeError(w, http.StatusBadRequest, "status must be approved or rejected")
+		return
+	}
+	reviewerID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	entry, err := h.repo.ResolveHeldContent(ctx, entryID, req.Status, reviewerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			logger.Warn("action", "action", "admin_resolve_content", "status", "not_found", "entry_id", entryID)
+			writeError(w, http.StatusNotFound, "entry not found")
+		case errors.Is(err, repository.ErrContentReviewed):
+			logger.Warn("action", "action", "admin_resolve_content", "status", "already_reviewed", "entry_id", entryID)
+			writeError(w, http.StatusConflict, "entry already reviewed")
+		default:
+			logger.Error("action", "action", "admin_resolve_content", "status", "db_error", "entry_id", entryID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+		}
+		return
+	}
+	if entry.Status == models.ContentStatusApproved && entry.TargetID != nil {
+		h.publishApprovedContent(ctx, r, logger, entry)
+	}
+	logger.Info("action", "action", "admin_resolve_content", "status", "success", "entry_id", entryID, "resolution", req.Status)
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// publishApprovedContent sends the notifications an approved item skipped while held.
+func (h *Handler) publishApprovedContent(ctx context.Context, r *http.Request, logger *slog.Logger, entry models.ContentFilterEntry) {
+	switch entry.Kind {
+	case contentfilter.KindComment:
+		comment, err := h.repo.GetEventComment(ctx, *entry.TargetID)
+		if err != nil {
+			logger.Warn("action", "action", "admin_resolve_content", "status", "comment_load_failed", "entry_id", entry.ID, "error", err)
+			return
+		}
+		if comment.EditedAt != nil {
+			// Notifications for edited comments went out with the original text.
+			return
+		}
+		event, err := h.repo.GetEventByID(ctx, comment.EventID)
+		if err != nil {
+			logger.Warn("action", "action", "admin_resolve_content", "status", "event_load_failed", "entry_id", entry.ID, "error", err)
+			return
+		}
+		var parentAuthorID int64
+		if comment.ParentID != nil {
+			if parent, err := h.repo.GetEventComment(ctx, *comment.ParentID); err == nil {
+				parentAuthorID = parent.UserID
+			}
+		}
+		h.notifyComment(ctx, r, logger, "admin_resolve_content", event, comment, parentAuthorID, event.CreatorUserID, extractMentions(comment.Body))
+	case contentfilter.KindEvent:
+		event, err := h.repo.GetEventByID(ctx, *entry.TargetID)
+		if err != nil {
+			logger.Warn("action", "action", "admin_resolve_content", "status", "event_load_failed", "entry_id", entry.ID, "error", err)
+			return
+		}
+		if event.Status != models.EventStatusPublished || event.IsPrivate || event.IsHidden {
+			return
+		}
+		media, err := h.repo.ListEventMedia(ctx, event.ID)
+		if err != nil {
+			logger.Warn("action", "action", "admin_resolve_content", "status", "media_error", "event_id", event.ID, "error", err)
+		}
+		payload := h.eventCreatedPayload(r, event.ID, event.Title, event.StartsAt, event.AddressLabel, media)
+		h.announceEvent(ctx, logger, "admin_resolve_content", event.ID, payload)
+	}
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+// coordinateEpsilon ignores float noise when comparing event coordinates (~10 cm).
+const coordinateEpsilon = 1e-6
+
+// eventChange describes a user-visible difference between two versions of an event.
+type eventChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// diffEventChanges returns changes of time, location, address and capacity.
+func diffEventChanges(before, after models.Event) []eventChange {
+	changes := make([]eventChange, 0, 5)
+	if !before.StartsAt.Equal(after.StartsAt) {
+		changes = append(changes, eventChange{Field: "startsAt", Before: formatChangeTime(&before.StartsAt), After: formatChangeTime(&after.StartsAt)})
+	}
+	if !sameTimePtr(before.EndsAt, after.EndsAt) {
+		changes = append(changes, eventChange{Field: "endsAt", Before: formatChangeTime(before.EndsAt), After: formatChangeTime(after.EndsAt)})
+	}
+	if math.Abs(before.Lat-after.Lat) > coordinateEpsilon || math.Abs(before.Lng-after.Lng) > coordinateEpsilon {
+		changes = append(changes, eventChange{
+			Field:  "location",
+			Before: fmt.Sprintf("%.5f, %.5f", before.Lat, before.Lng),
+			After:  fmt.Sprintf("%.5f, %.5f", after.Lat, after.Lng),
+		})
+	}
+	if before.AddressLabel != after.AddressLabel {
+		changes = append(changes, eventChange{Field: "address", Before: before.AddressLabel, After: after.AddressLabel})
+	}
+	if !sameIntPtr(before.Capacity, after.Capacity) {
+		changes = append(changes, eventChange{Field: "capacity", Before: formatChangeInt(before.Capacity), After: formatChangeInt(after.Capacity)})
+	}
+	return changes
+}
+
+// eventUpdatedPayload builds the payload of event_updated notifications.
+func eventUpdatedPayload(event models.Event, changes []eventChange) map[string]interface{} {
+	items := make([]map[string]interface{}, 0, len(changes))
+	for _, change := range changes {
+		items = append(items, map[string]interface{}{
+			"field":  change.Field,
+			"before": change.Before,
+			"after":  change.After,
+		})
+	}
+	return map[string]interface{}{
+		"eventId":  event.ID,
+		"title":    event.Title,
+		"startsAt": event.StartsAt.UTC().Format(time.RFC3339),
+		"changes":  items,
+	}
+}
+
+// applyEventChanges keeps pending notification jobs in line with an edited event
+// and tells participants and ticket holders what changed.
+func (h *Handler) applyEventChanges(ctx context.Context, logger *slog.Logger, action string, before, after models.Event, actorID int64) {
+	changes := diffEventChanges(before, after)
+	titleChanged := before.Title != after.Title
+	if len(changes) == 0 && !titleChanged {
+		return
+	}
+	if after.IsCanceled() {
+		return
+	}
+
+	if !before.StartsAt.Equal(after.StartsAt) {
+		if count, err := h.repo.RecomputeEventReminders(ctx, after.ID, time.Now()); err != nil {
+			logger.Warn("action", "action", action, "status", "reminders_failed", "event_id", after.ID, "error", err)
+		} else {
+			logger.Info("action", "action", action, "status", "reminders_rescheduled", "event_id", after.ID, "count", count)
+		}
+	}
+	if _, err := h.repo.RefreshPendingEventCards(ctx, after); err != nil {
+		logger.Warn("action", "action", action, "status", "refresh_jobs_failed", "event_id", after.ID, "error", err)
+	}
+
+	if len(changes) == 0 || after.IsDraft() {
+		return
+	}
+	count, err := h.repo.CreateEventAudienceNotificationJobs(ctx, after.ID, "event_updated", time.Now(), eventUpdatedPayload(after, changes), actorID)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "notify_failed", "event_id", after.ID, "error", err)
+		return
+	}
+	logger.Info("action", "action", action, "status", "notify_enqueued", "event_id", after.ID, "count", count, "changes", len(changes))
+}
+
+// loadSeriesOccurrencesFrom snapshots the occurrences a "following" edit will touch.
+func (h *Handler) loadSeriesOccurrencesFrom(ctx context.Context, event models.Event) ([]models.Event, error) {
+	if event.SeriesID == nil || event.SeriesIndex == nil {
+		return []models.Event{event}, nil
+	}
+	ids, err := h.repo.ListEventSeriesIDsFrom(ctx, *event.SeriesID, *event.SeriesIndex)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]models.Event, 0, len(ids))
+	for _, id := range ids {
+		occurrence, err := h.repo.GetEventByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, occurrence)
+	}
+	return out, nil
+}
+
+// notifyDroppedOccurrences sends event_canceled to the audience of series
+// occurrences that a split canceled. previous holds the occurrences as they
+// were before the split.
+func (h *Handler) notifyDroppedOccurrences(ctx context.Context, logger *slog.Logger, action string, previous []models.Event, canceledIDs []int64, actorID int64) {
+	canceled := make(map[int64]bool, len(canceledIDs))
+	for _, id := range canceledIDs {
+		canceled[id] = true
+	}
+	for _, event := range previous {
+		if !canceled[event.ID] || event.IsCanceled() || event.IsDraft() {
+			continue
+		}
+		payload := map[string]interface{}{
+			"eventId":  event.ID,
+			"title":    event.Title,
+			"startsAt": event.StartsAt.Format(time.RFC3339),
+			"refund":   false,
+			"reason":   repository.SeriesDroppedReason,
+		}
+		count, err := h.repo.CreateEventAudienceNotificationJobs(ctx, event.ID, "event_canceled", time.Now(), payload, actorID)
+		if err != nil {
+			logger.Warn("action", "action", action, "status", "notify_failed", "event_id", event.ID, "error", err)
+			continue
+		}
+		logger.Info("action", "action", action, "status", "cancel_notify_enqueued", "event_id", event.ID, "count", count)
+	}
+}
+
+// sameTimePtr reports whether two optional times are equal.
+func sameTimePtr(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// sameIntPtr reports whether two optional ints are equal.
+func sameIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// formatChangeTime formats an optional time for change payloads.
+func formatChangeTime(value *time.Time) string {
+	if value == nil {
+		return ""
+	}
+	return value.UTC().Format(time.RFC3339)
+}
+
+// formatChangeInt formats an optional int for change payloads.
+func formatChangeInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
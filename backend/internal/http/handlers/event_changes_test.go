@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestDiffEventChanges verifies diff event changes behavior.
+func TestDiffEventChanges(t *testing.T) {
+	startsAt := time.Date(2026, 4, 1, 18, 0, 0, 0, time.UTC)
+	capacity := 20
+	before := models.Event{StartsAt: startsAt, Lat: 55.75, Lng: 37.61, AddressLabel: "Club", Capacity: &capacity}
+
+	after := before
+	after.Title = "Renamed"
+	after.Lat += 1e-9
+	if changes := diffEventChanges(before, after); len(changes) != 0 {
+		t.Fatalf("expected no tracked changes, got %+v", changes)
+	}
+
+	moved := startsAt.Add(2 * time.Hour)
+	after.StartsAt = moved
+	after.AddressLabel = "Park"
+	after.Capacity = nil
+	changes := diffEventChanges(before, after)
+	if len(changes) != 3 {
+		t.Fatalf("expected 3 changes, got %+v", changes)
+	}
+	if changes[0].Field != "startsAt" || changes[0].Before != "2026-04-01T18:00:00Z" || changes[0].After != "2026-04-01T20:00:00Z" {
+		t.Fatalf("unexpected start change: %+v", changes[0])
+	}
+	if changes[1].Field != "address" || changes[1].After != "Park" {
+		t.Fatalf("unexpected address change: %+v", changes[1])
+	}
+	if changes[2].Field != "capacity" || changes[2].Before != "20" || changes[2].After != "" {
+		t.Fatalf("unexpected capacity change: %+v", changes[2])
+	}
+}
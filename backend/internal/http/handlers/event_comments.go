@@ -0,0 +1,357 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gigme/backend/internal/contentfilter"
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	commentEditWindow  = 15 * time.Minute
+	maxCommentMentions = 10
+	commentKindAdded   = "comment_added"
+	commentKindReply   = "comment_reply"
+	commentKindMention = "comment_mention"
+)
+
+// allowedCommentReactions lists emoji accepted as comment reactions.
+var allowedCommentReactions = map[string]struct{}{
+	"👍": {}, "👎": {}, "❤️": {}, "🔥": {}, "😂": {}, "😮": {}, "😢": {}, "🎉": {},
+}
+
+// mentionPattern matches @username mentions using Telegram username rules.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@])@([A-Za-z][A-Za-z0-9_]{4,31})\b`)
+
+// commentReactionRequest represents comment reaction request.
+type commentReactionRequest struct {
+	Emoji string `json:"emoji"`
+}
+
+// extractMentions returns distinct lower-case usernames mentioned in body.
+func extractMentions(body string) []string {
+	matches := mentionPattern.FindAllStringSubmatch(body, -1)
+	seen := make(map[string]struct{}, len(matches))
+	out := make([]string, 0, len(matches))
+	for _, match := range matches {
+		username := strings.ToLower(match[1])
+		if _, ok := seen[username]; ok {
+			continue
+		}
+		seen[username] = struct{}{}
+		out = append(out, username)
+		if len(out) == maxCommentMentions {
+			break
+		}
+	}
+	return out
+}
+
+// newMentions returns mentions of body that were not present in previous.
+func newMentions(previous, body string) []string {
+	old := make(map[string]struct{})
+	for _, username := range extractMentions(previous) {
+		old[username] = struct{}{}
+	}
+	out := make([]string, 0)
+	for _, username := range extractMentions(body) {
+		if _, ok := old[username]; !ok {
+			out = append(out, username)
+		}
+	}
+	return out
+}
+
+// commentRecipient is a user to notify about a comment.
+type commentRecipient struct {
+	userID int64
+	kind   string
+}
+
+// commentRecipients decides who is notified about a comment. Each user gets at
+// most one job: mentions win over replies, replies over the creator notice. The
+// author is never notified.
+func commentRecipients(authorID, creatorID int64, parentAuthorID int64, mentionedIDs []int64) []commentRecipient {
+	seen := map[int64]struct{}{authorID: {}}
+	out := make([]commentRecipient, 0, len(mentionedIDs)+2)
+	add := func(userID int64, kind string) {
+		if userID == 0 {
+			return
+		}
+		if _, ok := seen[userID]; ok {
+			return
+		}
+		seen[userID] = struct{}{}
+		out = append(out, commentRecipient{userID: userID, kind: kind})
+	}
+	for _, userID := range mentionedIDs {
+		add(userID, commentKindMention)
+	}
+	add(parentAuthorID, commentKindReply)
+	add(creatorID, commentKindAdded)
+	return out
+}
+
+// commentPayload builds the payload of comment notifications.
+func (h *Handler) commentPayload(r *http.Request, event models.Event, comment models.EventComment) map[string]interface{} {
+	payload := map[string]interface{}{
+		"eventId":       event.ID,
+		"title":         event.Title,
+		"commentId":     comment.ID,
+		"comment":       comment.Body,
+		"commenterName": comment.UserName,
+	}
+	if comment.ParentID != nil {
+		payload["parentId"] = *comment.ParentID
+	}
+	if apiBaseURL := strings.TrimSpace(h.cfg.APIPublicURL); apiBaseURL != "" {
+		payload["apiBaseUrl"] = apiBaseURL
+	} else if apiBaseURL := publicBaseURL(r); apiBaseURL != "" {
+		payload["apiBaseUrl"] = apiBaseURL
+	}
+	return payload
+}
+
+// resolveMentionedUsers maps mentioned usernames to users who can see the event.
+func (h *Handler) resolveMentionedUsers(ctx context.Context, event models.Event, usernames []string) ([]int64, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	found, err := h.repo.GetUserIDsByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]int64, 0, len(found))
+	for _, username := range usernames {
+		userID, ok := found[username]
+		if !ok {
+			continue
+		}
+		if event.IsPrivate && !h.allowPrivateEvent(ctx, event, userID, "") {
+			continue
+		}
+		out = append(out, userID)
+	}
+	return out, nil
+}
+
+// notifyComment enqueues comment_added, comment_reply and comment_mention jobs.
+func (h *Handler) notifyComment(ctx context.Context, r *http.Request, logger *slog.Logger, action string, event models.Event, comment models.EventComment, parentAuthorID, creatorID int64, mentions []string) {
+	mentionedIDs, err := h.resolveMentionedUsers(ctx, event, mentions)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "mentions_failed", "event_id", event.ID, "error", err)
+	}
+	payload := h.commentPayload(r, event, comment)
+	eventID := event.ID
+	for _, recipient := range commentRecipients(comment.UserID, creatorID, parentAuthorID, mentionedIDs) {
+		if _, err := h.repo.CreateNotificationJob(ctx, models.NotificationJob{
+			UserID:  recipient.userID,
+			EventID: &eventID,
+			Kind:    recipient.kind,
+			RunAt:   time.Now(),
+			Payload: payload,
+			Status:  "pending",
+		}); err != nil {
+			logger.Warn("action", "action", action, "status", "notify_failed", "event_id", event.ID, "user_id", recipient.userID, "error", err)
+		}
+	}
+}
+
+// loadVisibleComment returns a comment and its event if the current user can see the event.
+func (h *Handler) loadVisibleComment(ctx context.Context, r *http.Request, logger *slog.Logger, w http.ResponseWriter, action string, commentID, userID int64) (models.EventComment, models.Event, bool) {
+	comment, err := h.repo.GetEventComment(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", action, "status", "not_found", "comment_id", commentID)
+			writeError(w, http.StatusNotFound, "comment not found")
+			return models.EventComment{}, models.Event{}, false
+		}
+		logger.Error("action", "action", action, "status", "db_error", "comment_id", commentID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return models.EventComment{}, models.Event{}, false
+	}
+	event, err := h.repo.GetEventByID(ctx, comment.EventID)
+	if err != nil || event.IsHidden || !h.allowPrivateEvent(ctx, event, userID, accessKeyFromRequest(r)) {
+		logger.Warn("action", "action", action, "status", "not_found", "comment_id", commentID)
+		writeError(w, http.StatusNotFound, "comment not found")
+		return models.EventComment{}, models.Event{}, false
+	}
+	return comment, event, true
+}
+
+// EditEventComment lets the author change a comment within the edit window.
+func (h *Handler) EditEventComment(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "edit_comment", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "edit_comment", "status", "invalid_comment_id")
+		writeError(w, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+	var req commentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "edit_comment", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	body := strings.TrimSpace(req.Body)
+	if body == "" || utf8.RuneCountInString(body) > maxCommentLength {
+		logger.Warn("action", "action", "edit_comment", "status", "invalid_body")
+		writeError(w, http.StatusBadRequest, "comment length invalid")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	existing, event, ok := h.loadVisibleComment(ctx, r, logger, w, "edit_comment", commentID, userID)
+	if !ok {
+		return
+	}
+	if existing.UserID != userID {
+		logger.Warn("action", "action", "edit_comment", "status", "forbidden", "comment_id", commentID)
+		writeError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+	now := time.Now()
+	if now.Sub(existing.CreatedAt) > commentEditWindow {
+		logger.Warn("action", "action", "edit_comment", "status", "edit_window_expired", "comment_id", commentID)
+		writeError(w, http.StatusConflict, "edit window expired")
+		return
+	}
+
+	content := contentfilter.Content{Kind: contentfilter.KindComment, UserID: userID, EventID: event.ID, Text: body, Edited: true}
+	verdict, ok := h.filterContent(ctx, logger, w, "edit_comment", content)
+	if !ok {
+		return
+	}
+
+	comment, err := h.repo.UpdateEventComment(ctx, commentID, userID, body, now.Add(-commentEditWindow))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "edit_comment", "status", "edit_window_expired", "comment_id", commentID)
+			writeError(w, http.StatusConflict, "edit window expired")
+			return
+		}
+		logger.Error("action", "action", "edit_comment", "status", "db_error", "comment_id", commentID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if verdict.Decision == contentfilter.Hold {
+		if err := h.repo.SetEventCommentHidden(ctx, commentID, true); err != nil {
+			logger.Error("action", "action", "edit_comment", "status", "db_error", "comment_id", commentID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+			return
+		}
+		h.logContentFilter(ctx, logger, "edit_comment", content, verdict, &commentID)
+		logger.Info("action", "action", "edit_comment", "status", "held", "comment_id", commentID, "rule", verdict.Rule)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"comment": comment, "held": true})
+		return
+	}
+
+	if mentions := newMentions(existing.Body, body); len(mentions) > 0 {
+		// Only newly mentioned users are notified, so no reply or creator notice is repeated.
+		mentionedIDs, err := h.resolveMentionedUsers(ctx, event, mentions)
+		if err != nil {
+			logger.Warn("action", "action", "edit_comment", "status", "mentions_failed", "comment_id", commentID, "error", err)
+		}
+		payload := h.commentPayload(r, event, comment)
+		for _, recipient := range commentRecipients(userID, 0, 0, mentionedIDs) {
+			_, _ = h.repo.CreateNotificationJob(ctx, models.NotificationJob{
+				UserID:  recipient.userID,
+				EventID: &event.ID,
+				Kind:    commentKindMention,
+				RunAt:   now,
+				Payload: payload,
+				Status:  "pending",
+			})
+		}
+	}
+
+	logger.Info("action", "action", "edit_comment", "status", "success", "comment_id", commentID)
+	writeJSON(w, http.StatusOK, comment)
+}
+
+// AddCommentReaction adds an emoji reaction to a comment.
+func (h *Handler) AddCommentReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeCommentReaction(w, r, "add_comment_reaction", true)
+}
+
+// RemoveCommentReaction removes an emoji reaction from a comment.
+func (h *Handler) RemoveCommentReaction(w http.ResponseWriter, r *http.Request) {
+	h.changeCommentReaction(w, r, "remove_comment_reaction", false)
+}
+
+// changeCommentReaction adds or removes the current user's reaction and returns the new counts.
+func (h *Handler) changeCommentReaction(w http.ResponseWriter, r *http.Request, action string, add bool) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", action, "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "invalid_comment_id")
+		writeError(w, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+	emoji := strings.TrimSpace(r.URL.Query().Get("emoji"))
+	if add {
+		var req commentReactionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			logger.Warn("action", "action", action, "status", "invalid_json")
+			writeError(w, http.StatusBadRequest, "invalid json")
+			return
+		}
+		emoji = strings.TrimSpace(req.Emoji)
+	}
+	if _, ok := allowedCommentReactions[emoji]; !ok {
+		logger.Warn("action", "action", action, "status", "invalid_emoji")
+		writeError(w, http.StatusBadRequest, "unsupported reaction")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if _, _, ok := h.loadVisibleComment(ctx, r, logger, w, action, commentID, userID); !ok {
+		return
+	}
+	if add {
+		err = h.repo.AddCommentReaction(ctx, commentID, userID, emoji)
+	} else {
+		err = h.repo.RemoveCommentReaction(ctx, commentID, userID, emoji)
+	}
+	if err != nil {
+		logger.Error("action", "action", action, "status", "db_error", "comment_id", commentID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	reactions, err := h.repo.ListCommentReactions(ctx, commentID, userID)
+	if err != nil {
+		logger.Error("action", "action", action, "status", "db_error", "comment_id", commentID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", action, "status", "success", "comment_id", commentID, "emoji", emoji)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"commentId": commentID, "reactions": reactions})
+}
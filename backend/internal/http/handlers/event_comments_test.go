@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestExtractMentions verifies extract mentions behavior.
+func TestExtractMentions(t *testing.T) {
+	got := extractMentions("hi @Anna_K and @anna_k, mail me at bob@example.com; @abc is too short, (@Second_User)")
+	want := []string{"anna_k", "second_user"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected mentions: %v", got)
+	}
+	if got := newMentions("cc @first_user", "cc @first_user @second_user"); !reflect.DeepEqual(got, []string{"second_user"}) {
+		t.Fatalf("unexpected new mentions: %v", got)
+	}
+}
+
+// TestCommentRecipients verifies comment recipients behavior.
+func TestCommentRecipients(t *testing.T) {
+	got := commentRecipients(1, 2, 3, []int64{3, 1, 4})
+	want := []commentRecipient{
+		{userID: 3, kind: commentKindMention},
+		{userID: 4, kind: commentKindMention},
+		{userID: 2, kind: commentKindAdded},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected recipients: %+v", got)
+	}
+	if got := commentRecipients(2, 2, 0, nil); len(got) != 0 {
+		t.Fatalf("expected creator commenting on own event to get no jobs, got %+v", got)
+	}
+}
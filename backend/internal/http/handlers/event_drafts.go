@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// publishEventRequest represents publish event request.
+type publishEventRequest struct {
+	PublishAt *string `json:"publishAt"`
+}
+
+// parsePublishAt parses an optional RFC3339 publish time.
+// Empty values and times not after now mean "publish immediately" and return nil.
+func parsePublishAt(raw *string, now time.Time) (*time.Time, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, strings.TrimSpace(*raw))
+	if err != nil {
+		return nil, err
+	}
+	if !parsed.After(now) {
+		return nil, nil
+	}
+	parsed = parsed.UTC()
+	return &parsed, nil
+}
+
+// eventCreatedPayload builds the payload of event_created notifications.
+func (h *Handler) eventCreatedPayload(r *http.Request, eventID int64, title string, startsAt time.Time, addressLabel string, media []string) map[string]interface{} {
+	payload := map[string]interface{}{
+		"eventId":  eventID,
+		"title":    title,
+		"startsAt": startsAt.Format(time.RFC3339),
+	}
+	if apiBaseURL := strings.TrimSpace(h.cfg.APIPublicURL); apiBaseURL != "" {
+		payload["apiBaseUrl"] = apiBaseURL
+	} else if apiBaseURL := publicBaseURL(r); apiBaseURL != "" {
+		payload["apiBaseUrl"] = apiBaseURL
+	}
+	if addressLabel != "" {
+		payload["addressLabel"] = addressLabel
+	}
+	if len(media) > 0 {
+		payload["photoUrl"] = media[0]
+	}
+	return payload
+}
+
+// PublishEvent publishes a draft event now or schedules it for publishAt.
+func (h *Handler) PublishEvent(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "publish_event", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "publish_event", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	var req publishEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("action", "action", "publish_event", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	publishAt, err := parsePublishAt(req.PublishAt, time.Now())
+	if err != nil {
+		logger.Warn("action", "action", "publish_event", "status", "invalid_publish_at")
+		writeError(w, http.StatusBadRequest, "invalid publishAt")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "publish_event", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		logger.Error("action", "action", "publish_event", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if event.CreatorUserID != userID && !h.isAdminContext(ctx) {
+		logger.Warn("action", "action", "publish_event", "status", "forbidden", "event_id", eventID)
+		writeError(w, http.StatusForbidden, "forbidden")
+		return
+	}
+	if !event.IsDraft() {
+		logger.Warn("action", "action", "publish_event", "status", "already_published", "event_id", eventID)
+		writeError(w, http.StatusConflict, "event already published")
+		return
+	}
+
+	if publishAt != nil {
+		if err := h.repo.ScheduleEventPublish(ctx, eventID, publishAt); err != nil {
+			logger.Error("action", "action", "publish_event", "status", "db_error", "event_id", eventID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+			return
+		}
+		logger.Info("action", "action", "publish_event", "status", "scheduled", "event_id", eventID, "publish_at", publishAt)
+		writeJSON(w, http.StatusOK, map[string]interface{}{"eventId": eventID, "status": models.EventStatusDraft, "publishAt": publishAt})
+		return
+	}
+
+	if err := h.repo.PublishEvent(ctx, eventID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "publish_event", "status", "already_published", "event_id", eventID)
+			writeError(w, http.StatusConflict, "event already published")
+			return
+		}
+		logger.Error("action", "action", "publish_event", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+
+	if !event.IsPrivate {
+		media, err := h.repo.ListEventMedia(ctx, eventID)
+		if err != nil {
+			logger.Warn("action", "action", "publish_event", "status", "media_error", "event_id", eventID, "error", err)
+		}
+		payload := h.eventCreatedPayload(r, eventID, event.Title, event.StartsAt, event.AddressLabel, media)
+		h.announceEvent(ctx, logger, "publish_event", eventID, payload)
+	}
+
+	logger.Info("action", "action", "publish_event", "status", "success", "event_id", eventID)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"eventId": eventID, "status": models.EventStatusPublished})
+}
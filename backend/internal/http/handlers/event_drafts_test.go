@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/models"
+)
+
+// TestParsePublishAt verifies parse publish at behavior.
+func TestParsePublishAt(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if got, err := parsePublishAt(nil, now); err != nil || got != nil {
+		t.Fatalf("expected nil for missing value, got %v, %v", got, err)
+	}
+	past := "2026-03-01T11:00:00Z"
+	if got, err := parsePublishAt(&past, now); err != nil || got != nil {
+		t.Fatalf("expected past time to publish immediately, got %v, %v", got, err)
+	}
+	future := "2026-03-01T15:30:00+03:00"
+	got, err := parsePublishAt(&future, now)
+	if err != nil || got == nil {
+		t.Fatalf("expected future time, got %v, %v", got, err)
+	}
+	if !got.Equal(now.Add(30*time.Minute)) || got.Location() != time.UTC {
+		t.Fatalf("unexpected publish time %v", got)
+	}
+	invalid := "tomorrow"
+	if _, err := parsePublishAt(&invalid, now); err == nil {
+		t.Fatalf("expected error for invalid value")
+	}
+}
+
+// TestAllowPrivateEventDraft verifies allow private event draft behavior.
+func TestAllowPrivateEventDraft(t *testing.T) {
+	h := &Handler{cfg: &config.Config{}}
+	event := models.Event{ID: 1, CreatorUserID: 10, Status: models.EventStatusDraft}
+	ctx := context.Background()
+	if !h.allowPrivateEvent(ctx, event, 10, "") {
+		t.Fatalf("expected creator to see draft")
+	}
+	if h.allowPrivateEvent(ctx, event, 11, "") {
+		t.Fatalf("expected draft to be hidden from other users")
+	}
+	if h.allowPrivateEvent(ctx, event, 0, "") {
+		t.Fatalf("expected draft to be hidden from anonymous users")
+	}
+}
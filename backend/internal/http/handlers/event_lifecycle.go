@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxCancelReasonRunes = 500
+
+// cancelEventRequest represents cancel event request.
+type cancelEventRequest struct {
+	Reason string `json:"reason"`
+}
+
+// rescheduleEventRequest represents reschedule event request.
+type rescheduleEventRequest struct {
+	StartsAt string  `json:"startsAt"`
+	EndsAt   *string `json:"endsAt"`
+}
+
+// resolveRefundRequest represents resolve refund request.
+type resolveRefundRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// listRefundsResponse represents list refunds response.
+type listRefundsResponse struct {
+	Items []models.OrderRefund `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// parseRescheduleTimes validates new event times. Without endsAt the previous
+// duration is kept.
+func parseRescheduleTimes(req rescheduleEventRequest, event models.Event) (time.Time, *time.Time, error) {
+	startsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(req.StartsAt))
+	if err != nil {
+		return time.Time{}, nil, errors.New("invalid startsAt")
+	}
+	startsAt = startsAt.UTC()
+	if req.EndsAt == nil || strings.TrimSpace(*req.EndsAt) == "" {
+		return startsAt, occurrenceEndsAt(startsAt, event.StartsAt, event.EndsAt), nil
+	}
+	endsAt, err := time.Parse(time.RFC3339, strings.TrimSpace(*req.EndsAt))
+	if err != nil {
+		return time.Time{}, nil, errors.New("invalid endsAt")
+	}
+	endsAt = endsAt.UTC()
+	if endsAt.Before(startsAt) {
+		return time.Time{}, nil, errors.New("endsAt before startsAt")
+	}
+	return startsAt, &endsAt, nil
+}
+
+// CancelEvent cancels a published event, notifies its participants and ticket
+// holders and queues paid orders for refund.
+func (h *Handler) CancelEvent(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "cancel_event", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	var req cancelEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		logger.Warn("action", "action", "cancel_event", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len([]rune(reason)) > maxCancelReasonRunes {
+		logger.Warn("action", "action", "cancel_event", "status", "reason_too_long")
+		writeError(w, http.StatusBadRequest, "reason too long")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	userID, ok := h.requireEventPermission(ctx, logger, w, "cancel_event", eventID, eventPermEdit)
+	if !ok {
+		return
+	}
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "cancel_event", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		logger.Error("action", "action", "cancel_event", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+
+	result, err := h.repo.CancelEvent(ctx, eventID, userID, reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			logger.Warn("action", "action", "cancel_event", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, repository.ErrEventCanceled):
+			logger.Warn("action", "action", "cancel_event", "status", "already_canceled", "event_id", eventID)
+			writeError(w, http.StatusConflict, "event already canceled")
+		case errors.Is(err, repository.ErrEventNotPublished):
+			logger.Warn("action", "action", "cancel_event", "status", "not_published", "event_id", eventID)
+			writeError(w, http.StatusConflict, "event is not published")
+		default:
+			logger.Error("action", "action", "cancel_event", "status", "db_error", "event_id", eventID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+		}
+		return
+	}
+
+	payload := map[string]interface{}{
+		"eventId":  eventID,
+		"title":    event.Title,
+		"startsAt": event.StartsAt.Format(time.RFC3339),
+		"refund":   result.QueuedRefunds > 0,
+	}
+	if reason != "" {
+		payload["reason"] = reason
+	}
+	notified, err := h.repo.CreateEventAudienceNotificationJobs(ctx, eventID, "event_canceled", time.Now(), payload, userID)
+	if err != nil {
+		logger.Warn("action", "action", "cancel_event", "status", "notify_failed", "event_id", eventID, "error", err)
+	}
+
+	logger.Info("action", "action", "cancel_event", "status", "success", "event_id", eventID, "canceled_orders", result.CanceledOrders, "queued_refunds", result.QueuedRefunds, "notified", notified)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"eventId":        eventID,
+		"status":         models.EventStatusCanceled,
+		"canceledOrders": result.CanceledOrders,
+		"queuedRefunds":  result.QueuedRefunds,
+		"notified":       notified,
+	})
+}
+
+// RescheduleEvent moves an event to new times, recomputes reminders and
+// notifies participants and ticket holders about the change.
+func (h *Handler) RescheduleEvent(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "reschedule_event", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	var req rescheduleEventRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "reschedule_event", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	userID, ok := h.requireEventPermission(ctx, logger, w, "reschedule_event", eventID, eventPermEdit)
+	if !ok {
+		return
+	}
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "reschedule_event", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		logger.Error("action", "action", "reschedule_event", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if event.IsCanceled() {
+		logger.Warn("action", "action", "reschedule_event", "status", "canceled", "event_id", eventID)
+		writeError(w, http.StatusConflict, "event is canceled")
+		return
+	}
+	startsAt, endsAt, err := parseRescheduleTimes(req, event)
+	if err != nil {
+		logger.Warn("action", "action", "reschedule_event", "status", "invalid_times", "event_id", eventID, "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if startsAt.Equal(event.StartsAt) {
+		logger.Warn("action", "action", "reschedule_event", "status", "unchanged", "event_id", eventID)
+		writeError(w, http.StatusBadRequest, "startsAt unchanged")
+		return
+	}
+
+	reminders, err := h.repo.RescheduleEvent(ctx, eventID, startsAt, endsAt, time.Now())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "reschedule_event", "status", "conflict", "event_id", eventID)
+			writeError(w, http.StatusConflict, "event is canceled")
+			return
+		}
+		logger.Error("action", "action", "reschedule_event", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+
+	rescheduled := event
+	rescheduled.StartsAt = startsAt
+	rescheduled.EndsAt = endsAt
+	if _, err := h.repo.RefreshPendingEventCards(ctx, rescheduled); err != nil {
+		logger.Warn("action", "action", "reschedule_event", "status", "refresh_jobs_failed", "event_id", eventID, "error", err)
+	}
+
+	var notified int64
+	if !event.IsDraft() {
+		payload := map[string]interface{}{
+			"eventId":     eventID,
+			"title":       event.Title,
+			"oldStartsAt": event.StartsAt.UTC().Format(time.RFC3339),
+			"startsAt":    startsAt.Format(time.RFC3339),
+		}
+		notified, err = h.repo.CreateEventAudienceNotificationJobs(ctx, eventID, "event_rescheduled", time.Now(), payload, userID)
+		if err != nil {
+			logger.Warn("action", "action", "reschedule_event", "status", "notify_failed", "event_id", eventID, "error", err)
+		}
+	}
+
+	logger.Info("action", "action", "reschedule_event", "status", "success", "event_id", eventID, "old_starts_at", event.StartsAt, "starts_at", startsAt, "reminders", reminders, "notified", notified)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"eventId":     eventID,
+		"oldStartsAt": event.StartsAt,
+		"startsAt":    startsAt,
+		"endsAt":      endsAt,
+		"reminders":   reminders,
+		"notified":    notified,
+	})
+}
+
+// ListAdminRefunds lists refunds queued by event cancellations.
+func (h *Handler) ListAdminRefunds(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_list_refunds"); !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	offset := parseIntQuery(r, "offset", 0)
+	status := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status")))
+
+	var eventID *int64
+	if raw := strings.TrimSpace(r.URL.Query().Get("event_id")); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid event_id")
+			return
+		}
+		eventID = &parsed
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListOrderRefunds(ctx, status, eventID, limit, offset)
+	if err != nil {
+		logger.Error("admin_list_refunds", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, listRefundsResponse{Items: items, Total: total})
+}
+
+// ResolveAdminRefund marks a queued refund as refunded (canceling the order) or rejected.
+func (h *Handler) ResolveAdminRefund(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_resolve_refund"); !ok {
+		return
+	}
+	orderID := strings.TrimSpace(chi.URLParam(r, "orderId"))
+	if !uuidRe.MatchString(orderID) {
+		writeError(w, http.StatusBadRequest, "invalid order id")
+		return
+	}
+	var req resolveRefundRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status != models.RefundStatusRefunded && status != models.RefundStatusRejected {
+		writeError(w, http.StatusBadRequest, "status must be refunded or rejected")
+		return
+	}
+	adminID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.ResolveOrderRefund(ctx, orderID, status, adminID, req.Note); err != nil {
+		h.handleTicketingError(logger, w, "admin_resolve_refund", err)
+		return
+	}
+	logger.Info("admin_resolve_refund", "status", "success", "order_id", orderID, "refund_status", status)
+	writeJSON(w, http.StatusOK, map[string]interface{}{"orderId": orderID, "status": status})
+}
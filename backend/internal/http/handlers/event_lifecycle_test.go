@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestParseRescheduleTimes verifies parse reschedule times behavior.
+func TestParseRescheduleTimes(t *testing.T) {
+	oldStart := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	oldEnd := oldStart.Add(3 * time.Hour)
+	event := models.Event{StartsAt: oldStart, EndsAt: &oldEnd}
+
+	startsAt, endsAt, err := parseRescheduleTimes(rescheduleEventRequest{StartsAt: "2026-05-02T20:00:00+02:00"}, event)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !startsAt.Equal(time.Date(2026, 5, 2, 18, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected start: %s", startsAt)
+	}
+	if endsAt == nil || endsAt.Sub(startsAt) != 3*time.Hour {
+		t.Fatalf("expected duration to be kept, got %v", endsAt)
+	}
+
+	early := "2026-05-02T17:00:00Z"
+	if _, _, err := parseRescheduleTimes(rescheduleEventRequest{StartsAt: "2026-05-02T18:00:00Z", EndsAt: &early}, event); err == nil {
+		t.Fatalf("expected error for end before start")
+	}
+	if _, _, err := parseRescheduleTimes(rescheduleEventRequest{StartsAt: "tomorrow"}, event); err == nil {
+		t.Fatalf("expected error for invalid start")
+	}
+
+	openEnded := models.Event{StartsAt: oldStart}
+	if _, endsAt, err := parseRescheduleTimes(rescheduleEventRequest{StartsAt: "2026-05-02T18:00:00Z"}, openEnded); err != nil || endsAt != nil {
+		t.Fatalf("expected no end for open-ended event, got %v, %v", endsAt, err)
+	}
+}
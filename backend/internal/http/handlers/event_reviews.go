@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxReviewLength = 1000
+
+// eventReviewRequest represents event review request.
+type eventReviewRequest struct {
+	Rating int    `json:"rating"`
+	Body   string `json:"body"`
+}
+
+// moderateReviewRequest represents moderate review request.
+type moderateReviewRequest struct {
+	Hidden bool   `json:"hidden"`
+	Reason string `json:"reason"`
+}
+
+// eventReviewsResponse represents event reviews response.
+type eventReviewsResponse struct {
+	Items   []models.EventReview      `json:"items"`
+	Summary models.EventReviewSummary `json:"summary"`
+}
+
+// adminReviewsResponse represents admin reviews response.
+type adminReviewsResponse struct {
+	Items []models.EventReview `json:"items"`
+	Total int                  `json:"total"`
+}
+
+// validateEventReview validates rating and review text.
+func validateEventReview(req eventReviewRequest) (string, error) {
+	if req.Rating < 1 || req.Rating > 5 {
+		return "", errors.New("rating must be between 1 and 5")
+	}
+	body := strings.TrimSpace(req.Body)
+	if utf8.RuneCountInString(body) > maxReviewLength {
+		return "", errors.New("review too long")
+	}
+	return body, nil
+}
+
+// ReviewEvent creates or updates the current user's review of an ended event.
+func (h *Handler) ReviewEvent(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "review_event", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "review_event", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	var req eventReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "review_event", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	body, err := validateEventReview(req)
+	if err != nil {
+		logger.Warn("action", "action", "review_event", "status", "invalid_review")
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	review, err := h.repo.UpsertEventReview(ctx, eventID, userID, req.Rating, body)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			logger.Warn("action", "action", "review_event", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+		case errors.Is(err, repository.ErrEventNotEnded):
+			logger.Warn("action", "action", "review_event", "status", "not_ended", "event_id", eventID)
+			writeError(w, http.StatusConflict, "event has not ended")
+		case errors.Is(err, repository.ErrReviewNotAllowed):
+			logger.Warn("action", "action", "review_event", "status", "forbidden", "event_id", eventID)
+			writeError(w, http.StatusForbidden, "only participants can review this event")
+		default:
+			logger.Error("action", "action", "review_event", "status", "db_error", "event_id", eventID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+		}
+		return
+	}
+	logger.Info("action", "action", "review_event", "status", "success", "event_id", eventID, "rating", req.Rating)
+	writeJSON(w, http.StatusOK, review)
+}
+
+// ListEventReviews lists visible reviews of an event with its average rating.
+func (h *Handler) ListEventReviews(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, _ := middleware.UserIDFromContext(r.Context())
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "list_reviews", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil || event.IsHidden || !h.allowPrivateEvent(ctx, event, userID, accessKeyFromRequest(r)) {
+		logger.Warn("action", "action", "list_reviews", "status", "not_found", "event_id", eventID)
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	items, summary, err := h.repo.ListEventReviews(ctx, eventID, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "list_reviews", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, eventReviewsResponse{Items: items, Summary: summary})
+}
+
+// ListAdminReviews lists reviews for moderation.
+func (h *Handler) ListAdminReviews(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_list_reviews"); !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	var hidden *bool
+	if raw := strings.TrimSpace(r.URL.Query().Get("hidden")); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid hidden")
+			return
+		}
+		hidden = &parsed
+	}
+	var eventID *int64
+	if raw := strings.TrimSpace(r.URL.Query().Get("event_id")); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid event_id")
+			return
+		}
+		eventID = &parsed
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListEventReviewsForModeration(ctx, hidden, eventID, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "admin_list_reviews", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, adminReviewsResponse{Items: items, Total: total})
+}
+
+// ModerateReviewAdmin hides or restores a review.
+func (h *Handler) ModerateReviewAdmin(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_moderate_review"); !ok {
+		return
+	}
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_moderate_review", "status", "invalid_review_id")
+		writeError(w, http.StatusBadRequest, "invalid review id")
+		return
+	}
+	var req moderateReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "admin_moderate_review", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	moderatorID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.SetEventReviewHidden(ctx, reviewID, req.Hidden, req.Reason, moderatorID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "admin_moderate_review", "status", "not_found", "review_id", reviewID)
+			writeError(w, http.StatusNotFound, "review not found")
+			return
+		}
+		logger.Error("action", "action", "admin_moderate_review", "status", "db_error", "review_id", reviewID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "admin_moderate_review", "status", "success", "review_id", reviewID, "hidden", req.Hidden)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// DeleteReviewAdmin deletes a review.
+func (h *Handler) DeleteReviewAdmin(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_delete_review"); !ok {
+		return
+	}
+	reviewID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_delete_review", "status", "invalid_review_id")
+		writeError(w, http.StatusBadRequest, "invalid review id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.DeleteEventReview(ctx, reviewID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "admin_delete_review", "status", "not_found", "review_id", reviewID)
+			writeError(w, http.StatusNotFound, "review not found")
+			return
+		}
+		logger.Error("action", "action", "admin_delete_review", "status", "db_error", "review_id", reviewID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "admin_delete_review", "status", "success", "review_id", reviewID)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
@@ -0,0 +1,22 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateEventReview verifies validate event review behavior.
+func TestValidateEventReview(t *testing.T) {
+	if body, err := validateEventReview(eventReviewRequest{Rating: 5, Body: "  great  "}); err != nil || body != "great" {
+		t.Fatalf("expected trimmed body, got %q, %v", body, err)
+	}
+	if _, err := validateEventReview(eventReviewRequest{Rating: 0}); err == nil {
+		t.Fatalf("expected error for rating 0")
+	}
+	if _, err := validateEventReview(eventReviewRequest{Rating: 6}); err == nil {
+		t.Fatalf("expected error for rating 6")
+	}
+	if _, err := validateEventReview(eventReviewRequest{Rating: 3, Body: strings.Repeat("я", maxReviewLength+1)}); err == nil {
+		t.Fatalf("expected error for long review")
+	}
+}
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/recurrence"
+	"gigme/backend/internal/repository"
+)
+
+// eventRecurrenceRequest represents event recurrence request.
+type eventRecurrenceRequest struct {
+	RRule    string `json:"rrule"`
+	Timezone string `json:"timezone"`
+}
+
+const (
+	defaultSeriesWeeks   = 8
+	maxSeriesOccurrences = 60
+
+	eventEditScopeThis      = "this"
+	eventEditScopeFollowing = "following"
+)
+
+var (
+	errInvalidRecurrence = errors.New("invalid recurrence")
+	errNotRecurring      = errors.New("event is not recurring")
+	errNoOccurrences     = errors.New("recurrence has no occurrences")
+)
+
+// parseEventRecurrence parses recurrence rule and its timezone.
+func parseEventRecurrence(req *eventRecurrenceRequest) (recurrence.Rule, *time.Location, error) {
+	if req == nil || strings.TrimSpace(req.RRule) == "" {
+		return recurrence.Rule{}, nil, errInvalidRecurrence
+	}
+	rule, err := recurrence.Parse(req.RRule)
+	if err != nil {
+		return recurrence.Rule{}, nil, errInvalidRecurrence
+	}
+	loc := time.UTC
+	if tz := strings.TrimSpace(req.Timezone); tz != "" {
+		loaded, err := time.LoadLocation(tz)
+		if err != nil {
+			return recurrence.Rule{}, nil, errInvalidRecurrence
+		}
+		loc = loaded
+	}
+	return rule, loc, nil
+}
+
+// normalizeEventEditScope normalizes event edit scope.
+func normalizeEventEditScope(raw *string) (string, bool) {
+	if raw == nil {
+		return eventEditScopeThis, true
+	}
+	switch strings.ToLower(strings.TrimSpace(*raw)) {
+	case "", eventEditScopeThis:
+		return eventEditScopeThis, true
+	case eventEditScopeFollowing:
+		return eventEditScopeFollowing, true
+	default:
+		return "", false
+	}
+}
+
+// seriesHorizon returns how far ahead occurrences are materialized.
+func (h *Handler) seriesHorizon(now time.Time) time.Time {
+	weeks := defaultSeriesWeeks
+	if h.cfg != nil && h.cfg.SeriesWeeks > 0 {
+		weeks = h.cfg.SeriesWeeks
+	}
+	return now.AddDate(0, 0, 7*weeks)
+}
+
+// seriesDurationSeconds returns occurrence duration for series storage.
+func seriesDurationSeconds(startsAt time.Time, endsAt *time.Time) *int {
+	if endsAt == nil {
+		return nil
+	}
+	seconds := int(endsAt.Sub(startsAt) / time.Second)
+	return &seconds
+}
+
+// occurrenceEndsAt shifts the requested end to an occurrence start.
+func occurrenceEndsAt(occurrenceStart, requestedStart time.Time, requestedEnd *time.Time) *time.Time {
+	if requestedEnd == nil {
+		return nil
+	}
+	endsAt := occurrenceStart.Add(requestedEnd.Sub(requestedStart))
+	return &endsAt
+}
+
+// createEventSeries creates recurring event and returns series id and occurrence ids.
+func (h *Handler) createEventSeries(ctx context.Context, event models.Event, media []string, rule recurrence.Rule, loc *time.Location) (int64, []int64, []time.Time, error) {
+	horizon := h.seriesHorizon(time.Now())
+	starts, exhausted := rule.Next(event.StartsAt.In(loc), 0, horizon, maxSeriesOccurrences)
+	if len(starts) == 0 {
+		return 0, nil, nil, errNoOccurrences
+	}
+	materializedUntil := horizon
+	if len(starts) >= maxSeriesOccurrences {
+		materializedUntil = starts[len(starts)-1]
+	}
+	seriesID, eventIDs, err := h.repo.CreateEventSeries(ctx, models.EventSeries{
+		CreatorUserID:     event.CreatorUserID,
+		RRule:             rule.String(),
+		Timezone:          loc.String(),
+		StartsAt:          event.StartsAt,
+		DurationSeconds:   seriesDurationSeconds(event.StartsAt, event.EndsAt),
+		MaterializedUntil: materializedUntil,
+		IsActive:          !exhausted,
+	}, event, media, starts)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	return seriesID, eventIDs, starts, nil
+}
+
+// updateEventSeriesFollowing applies an edit to an occurrence and every following one.
+// The series is split: the old one ends before the occurrence, a new one starts at it.
+func (h *Handler) updateEventSeriesFollowing(ctx context.Context, actorID int64, existing, updated models.Event, media []string, replaceMedia bool, rruleRaw *string) (int64, []int64, error) {
+	if existing.SeriesID == nil || existing.SeriesIndex == nil {
+		return 0, nil, errNotRecurring
+	}
+	series, err := h.repo.GetEventSeries(ctx, *existing.SeriesID)
+	if err != nil {
+		return 0, nil, err
+	}
+	oldRule, err := recurrence.Parse(series.RRule)
+	if err != nil {
+		return 0, nil, errInvalidRecurrence
+	}
+	loc := recurrence.LoadLocation(series.Timezone)
+	index := *existing.SeriesIndex
+
+	newRule := oldRule.Remaining(index)
+	if rruleRaw != nil {
+		newRule, err = recurrence.Parse(*rruleRaw)
+		if err != nil {
+			return 0, nil, errInvalidRecurrence
+		}
+	}
+
+	eventIDs, err := h.repo.ListEventSeriesIDsFrom(ctx, series.ID, index)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(eventIDs) == 0 {
+		return 0, nil, errNotRecurring
+	}
+	starts := newRule.Expand(updated.StartsAt.In(loc), time.Time{}, len(eventIDs))
+	if len(starts) == 0 {
+		return 0, nil, errNoOccurrences
+	}
+
+	splitAt := existing.StartsAt
+	if original := oldRule.Expand(series.StartsAt.In(loc), time.Time{}, index+1); len(original) == index+1 {
+		splitAt = original[index]
+	}
+	lastStart := starts[len(starts)-1]
+	exhausted := len(starts) < len(eventIDs) || newRule.Exhausted(len(starts), lastStart)
+
+	return h.repo.SplitEventSeries(ctx, repository.EventSeriesSplit{
+		SeriesID:     series.ID,
+		FromIndex:    index,
+		PreviousRule: oldRule.Truncate(index, splitAt).String(),
+		Series: models.EventSeries{
+			CreatorUserID:     series.CreatorUserID,
+			RRule:             newRule.String(),
+			Timezone:          series.Timezone,
+			StartsAt:          updated.StartsAt,
+			DurationSeconds:   seriesDurationSeconds(updated.StartsAt, updated.EndsAt),
+			MaterializedUntil: lastStart,
+			IsActive:          !exhausted,
+		},
+		Event:        updated,
+		EventIDs:     eventIDs,
+		Starts:       starts,
+		Media:        media,
+		ReplaceMedia: replaceMedia,
+		ActorID:      actorID,
+	})
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseEventRecurrenceDefaultsToUTC verifies parse event recurrence defaults to u t c behavior.
+func TestParseEventRecurrenceDefaultsToUTC(t *testing.T) {
+	rule, loc, err := parseEventRecurrence(&eventRecurrenceRequest{RRule: "FREQ=WEEKLY;BYDAY=FR"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if loc != time.UTC {
+		t.Fatalf("expected UTC location, got %s", loc)
+	}
+	if rule.String() != "FREQ=WEEKLY;BYDAY=FR" {
+		t.Fatalf("unexpected rule: %s", rule.String())
+	}
+
+	if _, _, err := parseEventRecurrence(&eventRecurrenceRequest{RRule: "FREQ=WEEKLY", Timezone: "Mars/Base"}); err == nil {
+		t.Fatalf("expected unknown timezone to be rejected")
+	}
+	if _, _, err := parseEventRecurrence(&eventRecurrenceRequest{RRule: "FREQ=YEARLY"}); err == nil {
+		t.Fatalf("expected unsupported frequency to be rejected")
+	}
+}
+
+// TestNormalizeEventEditScope verifies normalize event edit scope behavior.
+func TestNormalizeEventEditScope(t *testing.T) {
+	following := " Following "
+	if scope, ok := normalizeEventEditScope(&following); !ok || scope != eventEditScopeFollowing {
+		t.Fatalf("expected following scope, got %q ok=%v", scope, ok)
+	}
+	if scope, ok := normalizeEventEditScope(nil); !ok || scope != eventEditScopeThis {
+		t.Fatalf("expected default this scope, got %q ok=%v", scope, ok)
+	}
+	all := "all"
+	if _, ok := normalizeEventEditScope(&all); ok {
+		t.Fatalf("expected unknown scope to be rejected")
+	}
+}
+
+// TestOccurrenceEndsAtKeepsDuration verifies occurrence ends at keeps duration behavior.
+func TestOccurrenceEndsAtKeepsDuration(t *testing.T) {
+	requestedStart := time.Date(2026, 3, 5, 20, 0, 0, 0, time.UTC)
+	requestedEnd := requestedStart.Add(3 * time.Hour)
+	occurrence := time.Date(2026, 3, 6, 20, 0, 0, 0, time.UTC)
+
+	got := occurrenceEndsAt(occurrence, requestedStart, &requestedEnd)
+	if got == nil || !got.Equal(occurrence.Add(3*time.Hour)) {
+		t.Fatalf("unexpected occurrence end: %v", got)
+	}
+	if occurrenceEndsAt(occurrence, requestedStart, nil) != nil {
+		t.Fatalf("expected nil end without requested end")
+	}
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// eventPermission is an action that can be granted per event.
+type eventPermission string
+
+const (
+	eventPermEdit   eventPermission = "edit"
+	eventPermOrders eventPermission = "orders"
+	eventPermRedeem eventPermission = "redeem"
+	eventPermStaff  eventPermission = "staff"
+	eventPermSeries eventPermission = "series"
+)
+
+// uuidRe matches the canonical textual form of order and ticket ids.
+var uuidRe = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// eventRolePermissions lists permissions of the scoped event roles.
+// The owner (event creator) and global admins have every permission, including
+// eventPermSeries (splitting a recurring series), which no staff role has.
+var eventRolePermissions = map[string][]eventPermission{
+	models.EventRoleCoHost:  {eventPermEdit, eventPermOrders, eventPermRedeem},
+	models.EventRoleCashier: {eventPermOrders},
+	models.EventRoleScanner: {eventPermRedeem},
+}
+
+// addEventStaffRequest represents add event staff request.
+type addEventStaffRequest struct {
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// eventRoleAllows reports whether role grants permission.
+func eventRoleAllows(role string, permission eventPermission) bool {
+	if role == models.EventRoleOwner {
+		return true
+	}
+	for _, granted := range eventRolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeEventStaffRole validates an assignable staff role.
+func normalizeEventStaffRole(raw string) (string, bool) {
+	role := strings.ToLower(strings.TrimSpace(raw))
+	role = strings.NewReplacer("-", "", "_", "").Replace(role)
+	if _, ok := eventRolePermissions[role]; !ok {
+		return "", false
+	}
+	return role, true
+}
+
+// requireEventPermission checks that the current user is an admin or holds permission on the event.
+func (h *Handler) requireEventPermission(ctx context.Context, logger *slog.Logger, w http.ResponseWriter, action string, eventID int64, permission eventPermission) (int64, bool) {
+	userID, ok := middleware.UserIDFromContext(ctx)
+	if !ok {
+		logger.Warn("action", "action", action, "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, false
+	}
+	if h.isAdminContext(ctx) {
+		return userID, true
+	}
+	role, err := h.repo.GetEventAccessRole(ctx, eventID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", action, "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+			return 0, false
+		}
+		logger.Error("action", "action", action, "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return 0, false
+	}
+	if !eventRoleAllows(role, permission) {
+		logger.Warn("action", "action", action, "status", "forbidden", "event_id", eventID, "user_id", userID, "role", role)
+		writeError(w, http.StatusForbidden, "forbidden")
+		return 0, false
+	}
+	return userID, true
+}
+
+// ListEventStaff lists staff members of an event.
+func (h *Handler) ListEventStaff(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "list_event_staff", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if _, ok := h.requireEventPermission(ctx, logger, w, "list_event_staff", eventID, eventPermEdit); !ok {
+		return
+	}
+	items, err := h.repo.ListEventStaff(ctx, eventID)
+	if err != nil {
+		logger.Error("action", "action", "list_event_staff", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "list_event_staff", "status", "success", "event_id", eventID, "count", len(items))
+	writeJSON(w, http.StatusOK, map[string]interface{}{"items": items})
+}
+
+// AddEventStaff invites a user by Telegram username or changes their role.
+func (h *Handler) AddEventStaff(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "add_event_staff", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	var req addEventStaffRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "add_event_staff", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	role, ok := normalizeEventStaffRole(req.Role)
+	if !ok {
+		logger.Warn("action", "action", "add_event_staff", "status", "invalid_role")
+		writeError(w, http.StatusBadRequest, "invalid role")
+		return
+	}
+	username := strings.TrimPrefix(strings.TrimSpace(req.Username), "@")
+	if username == "" {
+		logger.Warn("action", "action", "add_event_staff", "status", "missing_username")
+		writeError(w, http.StatusBadRequest, "username is required")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	inviterID, ok := h.requireEventPermission(ctx, logger, w, "add_event_staff", eventID, eventPermStaff)
+	if !ok {
+		return
+	}
+	memberID, err := h.repo.GetUserIDByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "add_event_staff", "status", "user_not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("action", "action", "add_event_staff", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	currentRole, err := h.repo.GetEventAccessRole(ctx, eventID, memberID)
+	if err != nil {
+		logger.Error("action", "action", "add_event_staff", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if currentRole == models.EventRoleOwner {
+		logger.Warn("action", "action", "add_event_staff", "status", "owner", "event_id", eventID)
+		writeError(w, http.StatusBadRequest, "user is the event owner")
+		return
+	}
+	member, err := h.repo.UpsertEventStaff(ctx, eventID, memberID, role, inviterID)
+	if err != nil {
+		logger.Error("action", "action", "add_event_staff", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "add_event_staff", "status", "success", "event_id", eventID, "member_id", memberID, "role", role)
+	writeJSON(w, http.StatusOK, member)
+}
+
+// RemoveEventStaff removes a staff member; members may also remove themselves.
+func (h *Handler) RemoveEventStaff(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "remove_event_staff", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	memberID, err := strconv.ParseInt(chi.URLParam(r, "userId"), 10, 64)
+	if err != nil || memberID <= 0 {
+		logger.Warn("action", "action", "remove_event_staff", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	userID, _ := middleware.UserIDFromContext(ctx)
+	if userID == 0 || userID != memberID {
+		if _, ok := h.requireEventPermission(ctx, logger, w, "remove_event_staff", eventID, eventPermStaff); !ok {
+			return
+		}
+	}
+	if err := h.repo.RemoveEventStaff(ctx, eventID, memberID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "remove_event_staff", "status", "not_found", "event_id", eventID, "member_id", memberID)
+			writeError(w, http.StatusNotFound, "staff member not found")
+			return
+		}
+		logger.Error("action", "action", "remove_event_staff", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "remove_event_staff", "status", "success", "event_id", eventID, "member_id", memberID)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// CheckInParticipant marks a participant as present, which lets them review the
+// event. Scanners, co-hosts, the creator and admins may check participants in.
+func (h *Handler) CheckInParticipant(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "check_in_participant", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	participantID, err := strconv.ParseInt(chi.URLParam(r, "userId"), 10, 64)
+	if err != nil || participantID <= 0 {
+		logger.Warn("action", "action", "check_in_participant", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	staffID, ok := h.requireEventPermission(ctx, logger, w, "check_in_participant", eventID, eventPermRedeem)
+	if !ok {
+		return
+	}
+	if err := h.repo.CheckInParticipant(ctx, eventID, participantID, staffID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "check_in_participant", "status", "not_found", "event_id", eventID, "participant_id", participantID)
+			writeError(w, http.StatusNotFound, "participant not found")
+			return
+		}
+		logger.Error("action", "action", "check_in_participant", "status", "db_error", "event_id", eventID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "check_in_participant", "status", "success", "event_id", eventID, "participant_id", participantID)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// requireOrderPermission checks permission on the event an order belongs to.
+func (h *Handler) requireOrderPermission(ctx context.Context, logger *slog.Logger, w http.ResponseWriter, action string, orderID string, permission eventPermission) (int64, bool) {
+	if !uuidRe.MatchString(orderID) {
+		logger.Warn("action", "action", action, "status", "invalid_order_id")
+		writeError(w, http.StatusBadRequest, "invalid order id")
+		return 0, false
+	}
+	if userID, ok := middleware.UserIDFromContext(ctx); ok && h.isAdminContext(ctx) {
+		return userID, true
+	}
+	eventID, err := h.repo.GetOrderEventID(ctx, orderID)
+	if err != nil {
+		h.handleTicketingError(logger, w, action, err)
+		return 0, false
+	}
+	return h.requireEventPermission(ctx, logger, w, action, eventID, permission)
+}
+
+// requireTicketPermission checks permission on the event a ticket belongs to.
+func (h *Handler) requireTicketPermission(ctx context.Context, logger *slog.Logger, w http.ResponseWriter, action string, ticketID string, permission eventPermission) (int64, bool) {
+	if !uuidRe.MatchString(ticketID) {
+		logger.Warn("action", "action", action, "status", "invalid_ticket_id")
+		writeError(w, http.StatusBadRequest, "invalid ticket id")
+		return 0, false
+	}
+	if userID, ok := middleware.UserIDFromContext(ctx); ok && h.isAdminContext(ctx) {
+		return userID, true
+	}
+	eventID, err := h.repo.GetTicketEventID(ctx, ticketID)
+	if err != nil {
+		h.handleTicketingError(logger, w, action, err)
+		return 0, false
+	}
+	return h.requireEventPermission(ctx, logger, w, action, eventID, permission)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"testing"
+
+	"gigme/backend/internal/models"
+)
+
+// TestEventRoleAllows verifies event role allows behavior.
+func TestEventRoleAllows(t *testing.T) {
+	cases := []struct {
+		role       string
+		permission eventPermission
+		want       bool
+	}{
+		{models.EventRoleOwner, eventPermStaff, true},
+		{models.EventRoleCoHost, eventPermEdit, true},
+		{models.EventRoleCoHost, eventPermStaff, false},
+		{models.EventRoleOwner, eventPermSeries, true},
+		{models.EventRoleCoHost, eventPermSeries, false},
+		{models.EventRoleCashier, eventPermOrders, true},
+		{models.EventRoleCashier, eventPermRedeem, false},
+		{models.EventRoleScanner, eventPermRedeem, true},
+		{models.EventRoleScanner, eventPermOrders, false},
+		{"", eventPermRedeem, false},
+	}
+	for _, tc := range cases {
+		if got := eventRoleAllows(tc.role, tc.permission); got != tc.want {
+			t.Fatalf("eventRoleAllows(%q, %q) = %v, want %v", tc.role, tc.permission, got, tc.want)
+		}
+	}
+}
+
+// TestNormalizeEventStaffRole verifies normalize event staff role behavior.
+func TestNormalizeEventStaffRole(t *testing.T) {
+	if role, ok := normalizeEventStaffRole(" Co-Host "); !ok || role != models.EventRoleCoHost {
+		t.Fatalf("expected cohost, got %q", role)
+	}
+	if role, ok := normalizeEventStaffRole("SCANNER"); !ok || role != models.EventRoleScanner {
+		t.Fatalf("expected scanner, got %q", role)
+	}
+	if _, ok := normalizeEventStaffRole("owner"); ok {
+		t.Fatalf("expected owner to be rejected")
+	}
+}
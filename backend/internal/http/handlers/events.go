@@ -12,8 +12,10 @@ import (
 	"time"
 	"unicode/utf8"
 
+	"gigme/backend/internal/contentfilter"
 	"gigme/backend/internal/http/middleware"
 	"gigme/backend/internal/models"
+	"gigme/backend/internal/recurrence"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/jackc/pgx/v5"
@@ -21,22 +23,26 @@ import (
 
 // createEventRequest represents create event request.
 type createEventRequest struct {
-	Title              string   `json:"title"`
-	Description        string   `json:"description"`
-	StartsAt           string   `json:"startsAt"`
-	EndsAt             *string  `json:"endsAt"`
-	Lat                float64  `json:"lat"`
-	Lng                float64  `json:"lng"`
-	Capacity           *int     `json:"capacity"`
-	Media              []string `json:"media"`
-	Address            string   `json:"addressLabel"`
-	Filters            []string `json:"filters"`
-	IsPrivate          bool     `json:"isPrivate"`
-	ContactTelegram    string   `json:"contactTelegram"`
-	ContactWhatsapp    string   `json:"contactWhatsapp"`
-	ContactWechat      string   `json:"contactWechat"`
-	ContactFbMessenger string   `json:"contactFbMessenger"`
-	ContactSnapchat    string   `json:"contactSnapchat"`
+	Title              string                  `json:"title"`
+	Description        string                  `json:"description"`
+	StartsAt           string                  `json:"startsAt"`
+	EndsAt             *string                 `json:"endsAt"`
+	Lat                float64                 `json:"lat"`
+	Lng                float64                 `json:"lng"`
+	Capacity           *int                    `json:"capacity"`
+	Media              []string                `json:"media"`
+	Address            string                  `json:"addressLabel"`
+	Filters            []string                `json:"filters"`
+	IsPrivate          bool                    `json:"isPrivate"`
+	ContactTelegram    string                  `json:"contactTelegram"`
+	ContactWhatsapp    string                  `json:"contactWhatsapp"`
+	ContactWechat      string                  `json:"contactWechat"`
+	ContactFbMessenger string                  `json:"contactFbMessenger"`
+	ContactSnapchat    string                  `json:"contactSnapchat"`
+	Timezone           string                  `json:"timezone"`
+	Recurrence         *eventRecurrenceRequest `json:"recurrence"`
+	Draft              bool                    `json:"draft"`
+	PublishAt          *string                 `json:"publishAt"`
 }
 
 // promoteEventRequest represents promote event request.
@@ -48,7 +54,8 @@ type promoteEventRequest struct {
 
 // commentRequest represents comment request.
 type commentRequest struct {
-	Body string `json:"body"`
+	Body     string `json:"body"`
+	ParentID *int64 `json:"parentId"`
 }
 
 const maxEventFilters = 3
@@ -206,7 +213,11 @@ func generateAccessKey() (string, error) {
 }
 
 // allowPrivateEvent handles allow private event.
+// Drafts are visible only to their creator and admins.
 func (h *Handler) allowPrivateEvent(ctx context.Context, event models.Event, userID int64, accessKey string) bool {
+	if event.IsDraft() {
+		return (userID != 0 && userID == event.CreatorUserID) || h.isAdminContext(ctx)
+	}
 	if !event.IsPrivate {
 		return true
 	}
@@ -337,6 +348,38 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		}
 		endsAt = &parsed
 	}
+	timezone := strings.TrimSpace(req.Timezone)
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			logger.Warn("action", "action", "create_event", "status", "invalid_timezone")
+			writeError(w, http.StatusBadRequest, "invalid timezone")
+			return
+		}
+	}
+	publishAt, err := parsePublishAt(req.PublishAt, time.Now())
+	if err != nil {
+		logger.Warn("action", "action", "create_event", "status", "invalid_publish_at")
+		writeError(w, http.StatusBadRequest, "invalid publishAt")
+		return
+	}
+	status := models.EventStatusPublished
+	if req.Draft || publishAt != nil {
+		status = models.EventStatusDraft
+	}
+	recurring := req.Recurrence != nil && strings.TrimSpace(req.Recurrence.RRule) != ""
+	var rule recurrence.Rule
+	var ruleLoc *time.Location
+	if recurring {
+		if strings.TrimSpace(req.Recurrence.Timezone) == "" {
+			req.Recurrence.Timezone = timezone
+		}
+		rule, ruleLoc, err = parseEventRecurrence(req.Recurrence)
+		if err != nil {
+			logger.Warn("action", "action", "create_event", "status", "invalid_recurrence")
+			writeError(w, http.StatusBadRequest, "invalid recurrence")
+			return
+		}
+	}
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
@@ -353,6 +396,13 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	content := contentfilter.Content{Kind: contentfilter.KindEvent, UserID: userID, Text: title + "\n" + description}
+	verdict, ok := h.filterContent(ctx, logger, w, "create_event", content)
+	if !ok {
+		return
+	}
+	held := verdict.Decision == contentfilter.Hold
+
 	addressLabel := strings.TrimSpace(req.Address)
 	accessKey := ""
 	if req.IsPrivate {
@@ -364,7 +414,7 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	eventID, err := h.repo.CreateEventWithMedia(ctx, models.Event{
+	event := models.Event{
 		CreatorUserID:      userID,
 		Title:              title,
 		Description:        description,
@@ -382,7 +432,30 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		Filters:            filters,
 		IsPrivate:          req.IsPrivate,
 		AccessKey:          accessKey,
-	}, req.Media)
+		Timezone:           timezone,
+		Status:             status,
+		PublishAt:          publishAt,
+		IsHidden:           held,
+	}
+	var eventID int64
+	var seriesID int64
+	var occurrenceIDs []int64
+	if recurring {
+		var starts []time.Time
+		seriesID, occurrenceIDs, starts, err = h.createEventSeries(ctx, event, req.Media, rule, ruleLoc)
+		if errors.Is(err, errNoOccurrences) {
+			logger.Warn("action", "action", "create_event", "status", "no_occurrences")
+			writeError(w, http.StatusBadRequest, "recurrence has no occurrences")
+			return
+		}
+		if err == nil {
+			eventID = occurrenceIDs[0]
+			startsAt = starts[0]
+			endsAt = occurrenceEndsAt(starts[0], event.StartsAt, endsAt)
+		}
+	} else {
+		eventID, err = h.repo.CreateEventWithMedia(ctx, event, req.Media)
+	}
 	if err != nil {
 		logger.Error("action", "action", "create_event", "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "failed to create event")
@@ -391,40 +464,17 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 
 	_ = h.repo.JoinEvent(ctx, eventID, userID)
 
-	payload := map[string]interface{}{
-		"eventId":  eventID,
-		"title":    title,
-		"startsAt": req.StartsAt,
-	}
-	if apiBaseURL := strings.TrimSpace(h.cfg.APIPublicURL); apiBaseURL != "" {
-		payload["apiBaseUrl"] = apiBaseURL
-	} else if apiBaseURL := publicBaseURL(r); apiBaseURL != "" {
-		payload["apiBaseUrl"] = apiBaseURL
+	if held {
+		content.EventID = eventID
+		h.logContentFilter(ctx, logger, "create_event", content, verdict, &eventID)
 	}
-	if addressLabel != "" {
-		payload["addressLabel"] = addressLabel
-	}
-	if len(req.Media) > 0 {
-		payload["photoUrl"] = req.Media[0]
-	}
-	if !req.IsPrivate {
-		if count, err := h.repo.CreateNotificationJobsForAllUsers(ctx, eventID, "event_created", time.Now(), payload); err != nil {
-			logger.Warn("action", "action", "create_event", "status", "notify_all_failed", "error", err)
-		} else {
-			logger.Info("action", "action", "create_event", "status", "notify_all_enqueued", "count", count)
-		}
+	if status == models.EventStatusPublished && !req.IsPrivate && !held {
+		payload := h.eventCreatedPayload(r, eventID, title, startsAt, addressLabel, req.Media)
+		h.announceEvent(ctx, logger, "create_event", eventID, payload)
 	}
 
-	reminderAt := startsAt.Add(-60 * time.Minute)
-	if reminderAt.After(time.Now()) {
-		_, _ = h.repo.CreateNotificationJob(ctx, models.NotificationJob{
-			UserID:  userID,
-			EventID: &eventID,
-			Kind:    "reminder_60m",
-			RunAt:   reminderAt,
-			Payload: map[string]interface{}{"eventId": eventID, "title": title},
-			Status:  "pending",
-		})
+	if _, err := h.repo.ScheduleEventReminders(ctx, eventID, userID, time.Now()); err != nil {
+		logger.Warn("action", "action", "create_event", "status", "reminders_failed", "event_id", eventID, "error", err)
 	}
 
 	logger.Info(
@@ -440,11 +490,25 @@ func (h *Handler) CreateEvent(w http.ResponseWriter, r *http.Request) {
 		"capacity", req.Capacity,
 		"media_count", len(req.Media),
 		"filters", filters,
+		"series_id", seriesID,
+		"occurrences", len(occurrenceIDs),
+		"event_status", status,
+		"held", held,
 	)
-	resp := map[string]interface{}{"eventId": eventID}
+	resp := map[string]interface{}{"eventId": eventID, "status": status}
+	if held {
+		resp["held"] = true
+	}
+	if publishAt != nil {
+		resp["publishAt"] = publishAt
+	}
 	if accessKey != "" {
 		resp["accessKey"] = accessKey
 	}
+	if seriesID != 0 {
+		resp["seriesId"] = seriesID
+		resp["occurrenceIds"] = occurrenceIDs
+	}
 	writeJSON(w, http.StatusOK, resp)
 }
 
@@ -511,16 +575,28 @@ func (h *Handler) Feed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	accessKeys := parseAccessKeysQuery(r)
+	mode, ok := h.feedMode(r.URL.Query().Get("mode"))
+	if !ok {
+		logger.Warn("action", "action", "feed", "status", "invalid_mode")
+		writeError(w, http.StatusBadRequest, "invalid mode")
+		return
+	}
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	items, err := h.repo.GetFeed(ctx, userID, limit, offset, lat, lng, radius, filters, accessKeys)
+	var items []models.Event
+	if mode == feedModeRanked {
+		items, err = h.repo.GetRankedFeed(ctx, userID, limit, offset, lat, lng, radius, filters, accessKeys, h.feedRanking())
+	} else {
+		items, err = h.repo.GetFeed(ctx, userID, limit, offset, lat, lng, radius, filters, accessKeys)
+	}
 	if err != nil {
 		logger.Error("action", "action", "feed", "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
-	logger.Info("action", "action", "feed", "status", "success", "scope", "global", "limit", limit, "offset", offset, "count", len(items))
+	h.attachFriendsGoing(ctx, logger, "feed", userID, items)
+	logger.Info("action", "action", "feed", "status", "success", "scope", "global", "mode", mode, "limit", limit, "offset", offset, "count", len(items))
 	writeJSON(w, http.StatusOK, items)
 }
 
@@ -550,6 +626,11 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "event not found")
 		return
 	}
+	if event.IsDraft() && !h.allowPrivateEvent(ctx, event, userID, accessKey) {
+		logger.Warn("action", "action", "get_event", "status", "draft", "event_id", eventID)
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
 	isJoined := false
 	if userID != 0 {
 		joined, err := h.repo.IsUserJoined(ctx, eventID, userID)
@@ -587,13 +668,24 @@ func (h *Handler) GetEvent(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	event.IsLiked = isLiked
+	if userID != 0 {
+		events := []models.Event{event}
+		h.attachFriendsGoing(ctx, logger, "get_event", userID, events)
+		event = events[0]
+	}
 
-	writeJSON(w, http.StatusOK, map[string]interface{}{
+	resp := map[string]interface{}{
 		"event":        event,
 		"participants": participants,
 		"media":        media,
 		"isJoined":     isJoined,
-	})
+	}
+	if userID != 0 {
+		if role, err := h.repo.GetEventAccessRole(ctx, eventID, userID); err == nil && role != "" {
+			resp["role"] = role
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
 	logger.Info("action", "action", "get_event", "status", "success", "event_id", eventID, "user_id", userID, "participants_count", len(participants), "media_count", len(media))
 }
 
@@ -633,6 +725,11 @@ func (h *Handler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "event not found")
 		return
 	}
+	if event.IsCanceled() {
+		logger.Warn("action", "action", "join_event", "status", "canceled", "event_id", id)
+		writeError(w, http.StatusConflict, "event is canceled")
+		return
+	}
 	cap := event.Capacity
 	if cap != nil {
 		count, err := h.repo.CountParticipants(ctx, id)
@@ -663,19 +760,9 @@ func (h *Handler) JoinEvent(w http.ResponseWriter, r *http.Request) {
 			Payload: map[string]interface{}{"eventId": id, "title": title},
 			Status:  "pending",
 		})
-		if startsAt, err := h.repo.GetEventStart(ctx, id); err == nil {
-			reminderAt := startsAt.Add(-60 * time.Minute)
-			if reminderAt.After(time.Now()) {
-				_, _ = h.repo.CreateNotificationJob(ctx, models.NotificationJob{
-					UserID:  userID,
-					EventID: &id,
-					Kind:    "reminder_60m",
-					RunAt:   reminderAt,
-					Payload: map[string]interface{}{"eventId": id, "title": title},
-					Status:  "pending",
-				})
-			}
-		}
+	}
+	if _, err := h.repo.ScheduleEventReminders(ctx, id, userID, time.Now()); err != nil {
+		logger.Warn("action", "action", "join_event", "status", "reminders_failed", "event_id", id, "error", err)
 	}
 
 	logger.Info("action", "action", "join_event", "status", "success", "event_id", id)
@@ -833,7 +920,7 @@ func (h *Handler) ListEventComments(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusNotFound, "event not found")
 		return
 	}
-	comments, err := h.repo.ListEventComments(ctx, eventID, limit, offset)
+	comments, err := h.repo.ListEventComments(ctx, eventID, userID, limit, offset)
 	if err != nil {
 		logger.Error("action", "action", "list_comments", "status", "db_error", "event_id", eventID, "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
@@ -884,12 +971,36 @@ func (h *Handler) AddEventComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	comment, err := h.repo.AddEventComment(ctx, eventID, userID, body)
+	var parentAuthorID int64
+	if req.ParentID != nil {
+		parent, err := h.repo.GetEventComment(ctx, *req.ParentID)
+		if err != nil || parent.EventID != eventID {
+			logger.Warn("action", "action", "add_comment", "status", "invalid_parent", "event_id", eventID)
+			writeError(w, http.StatusBadRequest, "invalid parent comment")
+			return
+		}
+		parentAuthorID = parent.UserID
+	}
+
+	content := contentfilter.Content{Kind: contentfilter.KindComment, UserID: userID, EventID: eventID, Text: body}
+	verdict, ok := h.filterContent(ctx, logger, w, "add_comment", content)
+	if !ok {
+		return
+	}
+	held := verdict.Decision == contentfilter.Hold
+
+	comment, err := h.repo.AddEventComment(ctx, eventID, userID, req.ParentID, body, held)
 	if err != nil {
 		logger.Error("action", "action", "add_comment", "status", "db_error", "event_id", eventID, "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
+	if held {
+		h.logContentFilter(ctx, logger, "add_comment", content, verdict, &comment.ID)
+		logger.Info("action", "action", "add_comment", "status", "held", "event_id", eventID, "rule", verdict.Rule)
+		writeJSON(w, http.StatusAccepted, map[string]interface{}{"comment": comment, "held": true})
+		return
+	}
 	commentsCount, err := h.repo.CountEventComments(ctx, eventID)
 	if err != nil {
 		logger.Error("action", "action", "add_comment", "status", "count_error", "event_id", eventID, "error", err)
@@ -897,25 +1008,7 @@ func (h *Handler) AddEventComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	payload := map[string]interface{}{
-		"eventId":       eventID,
-		"title":         event.Title,
-		"comment":       comment.Body,
-		"commenterName": comment.UserName,
-	}
-	if apiBaseURL := strings.TrimSpace(h.cfg.APIPublicURL); apiBaseURL != "" {
-		payload["apiBaseUrl"] = apiBaseURL
-	} else if apiBaseURL := publicBaseURL(r); apiBaseURL != "" {
-		payload["apiBaseUrl"] = apiBaseURL
-	}
-	_, _ = h.repo.CreateNotificationJob(ctx, models.NotificationJob{
-		UserID:  event.CreatorUserID,
-		EventID: &eventID,
-		Kind:    "comment_added",
-		RunAt:   time.Now(),
-		Payload: payload,
-		Status:  "pending",
-	})
+	h.notifyComment(ctx, r, logger, "add_comment", event, comment, parentAuthorID, event.CreatorUserID, extractMentions(body))
 
 	writeJSON(w, http.StatusOK, map[string]interface{}{"comment": comment, "commentsCount": commentsCount})
 	logger.Info("action", "action", "add_comment", "status", "success", "event_id", eventID, "user_id", userID)
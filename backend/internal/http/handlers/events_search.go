@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gigme/backend/internal/http/middleware"
+)
+
+const (
+	minSearchQueryLength = 2
+	maxSearchQueryLength = 200
+	maxSearchLimit       = 50
+)
+
+// SearchEvents handles full-text event search.
+func (h *Handler) SearchEvents(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, _ := middleware.UserIDFromContext(r.Context())
+
+	text, ok := normalizeSearchQuery(r.URL.Query().Get("q"))
+	if !ok {
+		logger.Warn("action", "action", "search_events", "status", "invalid_query")
+		writeError(w, http.StatusBadRequest, "invalid query")
+		return
+	}
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+	lat, lng := parseLatLng(r)
+	radius := parseRadiusM(r)
+	filters, err := parseEventFiltersQuery(r)
+	if err != nil {
+		logger.Warn("action", "action", "search_events", "status", "invalid_filters")
+		writeError(w, http.StatusBadRequest, "invalid filters")
+		return
+	}
+	accessKeys := parseAccessKeysQuery(r)
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, err := h.repo.SearchEvents(ctx, userID, text, limit, offset, lat, lng, radius, filters, accessKeys)
+	if err != nil {
+		logger.Error("action", "action", "search_events", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "search_events", "status", "success", "limit", limit, "offset", offset, "count", len(items))
+	writeJSON(w, http.StatusOK, items)
+}
+
+// normalizeSearchQuery trims and collapses whitespace, validating query length.
+func normalizeSearchQuery(raw string) (string, bool) {
+	text := strings.Join(strings.Fields(raw), " ")
+	length := utf8.RuneCountInString(text)
+	if length < minSearchQueryLength || length > maxSearchQueryLength {
+		return "", false
+	}
+	return text, true
+}
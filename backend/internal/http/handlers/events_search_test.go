@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestNormalizeSearchQuery verifies normalize search query behavior.
+func TestNormalizeSearchQuery(t *testing.T) {
+	got, ok := normalizeSearchQuery("  джаз \t  concert\n")
+	if !ok || got != "джаз concert" {
+		t.Fatalf("unexpected query: %q ok=%v", got, ok)
+	}
+	if _, ok := normalizeSearchQuery(" я "); ok {
+		t.Fatalf("expected too short query to be rejected")
+	}
+	if _, ok := normalizeSearchQuery(strings.Repeat("a", maxSearchQueryLength+1)); ok {
+		t.Fatalf("expected too long query to be rejected")
+	}
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strings"
+
+	"gigme/backend/internal/repository"
+)
+
+const (
+	feedModeChronological = "chronological"
+	feedModeRanked        = "ranked"
+)
+
+// feedMode resolves the requested feed mode, falling back to the configured default.
+func (h *Handler) feedMode(raw string) (string, bool) {
+	mode := strings.ToLower(strings.TrimSpace(raw))
+	if mode == "" && h.cfg != nil && h.cfg.FeedRanking.DefaultMode == feedModeRanked {
+		mode = feedModeRanked
+	}
+	switch mode {
+	case "", feedModeChronological:
+		return feedModeChronological, true
+	case feedModeRanked:
+		return feedModeRanked, true
+	default:
+		return "", false
+	}
+}
+
+// feedRanking returns ranking weights from config.
+func (h *Handler) feedRanking() repository.FeedRanking {
+	if h.cfg == nil {
+		return repository.FeedRanking{}
+	}
+	cfg := h.cfg.FeedRanking
+	return repository.FeedRanking{
+		DistanceWeight:   cfg.DistanceWeight,
+		TimeWeight:       cfg.TimeWeight,
+		PopularityWeight: cfg.PopularityWeight,
+		AffinityWeight:   cfg.AffinityWeight,
+		DistanceScaleKm:  cfg.DistanceScaleKm,
+		TimeScaleHours:   cfg.TimeScaleHours,
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"testing"
+
+	"gigme/backend/internal/config"
+)
+
+// TestFeedMode verifies feed mode behavior.
+func TestFeedMode(t *testing.T) {
+	h := &Handler{cfg: &config.Config{FeedRanking: config.FeedRankingConfig{DefaultMode: "ranked"}}}
+	if mode, ok := h.feedMode(""); !ok || mode != feedModeRanked {
+		t.Fatalf("expected configured default, got %q", mode)
+	}
+	if mode, ok := h.feedMode("Chronological"); !ok || mode != feedModeChronological {
+		t.Fatalf("expected explicit chronological mode, got %q", mode)
+	}
+	if _, ok := h.feedMode("random"); ok {
+		t.Fatalf("expected unknown mode to be rejected")
+	}
+	plain := &Handler{cfg: &config.Config{FeedRanking: config.FeedRankingConfig{DefaultMode: "bogus"}}}
+	if mode, ok := plain.feedMode(""); !ok || mode != feedModeChronological {
+		t.Fatalf("expected chronological fallback, got %q", mode)
+	}
+}
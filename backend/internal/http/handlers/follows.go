@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+// followingResponse represents following response.
+type followingResponse struct {
+	Items []models.FollowedUser `json:"items"`
+	Total int                   `json:"total"`
+}
+
+// announceEvent enqueues notifications about a newly published public event
+// for followers of its creator, users who opted into all new events, nearby
+// users selected by the announcement policy and matching saved searches.
+func (h *Handler) announceEvent(ctx context.Context, logger *slog.Logger, action string, eventID int64, payload map[string]interface{}) {
+	counts, err := h.repo.EnqueueEventAnnouncements(ctx, eventID, time.Now(), payload, h.announcementPolicy())
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "announce_failed", "event_id", eventID, "error", err)
+	} else {
+		logger.Info("action", "action", action, "status", "announce_enqueued", "event_id", eventID, "followers", counts.Followers, "all_events", counts.AllEvents, "nearby", counts.Nearby)
+	}
+	h.alertSavedSearches(ctx, logger, action, eventID, payload)
+}
+
+// announcementPolicy returns announcement targeting from config.
+func (h *Handler) announcementPolicy() repository.AnnouncementPolicy {
+	if h.cfg == nil {
+		return repository.AnnouncementPolicy{}
+	}
+	cfg := h.cfg.Announce
+	return repository.NewAnnouncementPolicy(cfg.RadiusKm, cfg.SeenDays, cfg.DailyCap, cfg.FollowedDailyCap)
+}
+
+// GetUserProfile returns the public profile of a user.
+func (h *Handler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	viewerID, _ := middleware.UserIDFromContext(r.Context())
+	userID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "get_user_profile", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	profile, err := h.repo.GetPublicProfile(ctx, userID, viewerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "get_user_profile", "status", "not_found", "user_id", userID)
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("action", "action", "get_user_profile", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, profile)
+}
+
+// FollowUser follows an organizer to get notified of their new events.
+func (h *Handler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "follow_user", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	targetID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "follow_user", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	if targetID == userID {
+		logger.Warn("action", "action", "follow_user", "status", "self_follow")
+		writeError(w, http.StatusBadRequest, "cannot follow yourself")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	created, err := h.repo.FollowUser(ctx, userID, targetID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "follow_user", "status", "not_found", "user_id", targetID)
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("action", "action", "follow_user", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "follow_user", "status", "success", "user_id", targetID, "created", created)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true, "following": true})
+}
+
+// UnfollowUser stops following an organizer.
+func (h *Handler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "unfollow_user", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	targetID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "unfollow_user", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	removed, err := h.repo.UnfollowUser(ctx, userID, targetID)
+	if err != nil {
+		logger.Error("action", "action", "unfollow_user", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "unfollow_user", "status", "success", "user_id", targetID, "removed", removed)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true, "following": false})
+}
+
+// ListFollowing lists organizers the current user follows.
+func (h *Handler) ListFollowing(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "list_following", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListFollowing(ctx, userID, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "list_following", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, followingResponse{Items: items, Total: total})
+}
+
+// PreviewEventAnnouncement counts who would be notified if the event were
+// announced now under the current policy.
+func (h *Handler) PreviewEventAnnouncement(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "preview_event_announcement"); !ok {
+		return
+	}
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "preview_event_announcement", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	counts, err := h.repo.PreviewEventAnnouncements(ctx, eventID, time.Now(), h.announcementPolicy())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "preview_event_announcement", "status", "not_found", "event_id", eventID)
+			writeError(w, http.StatusNotFound, "event not found")
+			return
+		}
+		logger.Error("action", "action", "preview_event_announcement", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, counts)
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/integrations"
+	"gigme/backend/internal/models"
+)
+
+const (
+	maxContactsPerUpload = 2000
+	friendsGoingPreview  = 3
+)
+
+// uploadContactsRequest represents upload contacts request. Phone hashes are
+// hex SHA-256 digests of the number's digits in international format without "+".
+type uploadContactsRequest struct {
+	PhoneHashes []string `json:"phoneHashes"`
+	TelegramIDs []int64  `json:"telegramIds"`
+}
+
+// updatePrivacyRequest represents update privacy request.
+type updatePrivacyRequest struct {
+	HideAttendance *bool `json:"hideAttendance"`
+}
+
+// friendsResponse represents friends response.
+type friendsResponse struct {
+	Items []models.Friend `json:"items"`
+	Total int             `json:"total"`
+}
+
+// normalizePhoneHash lower-cases a phone hash and reports whether it is a hex SHA-256 digest.
+func normalizePhoneHash(value string) (string, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if len(value) != 64 {
+		return "", false
+	}
+	for _, ch := range value {
+		if (ch < '0' || ch > '9') && (ch < 'a' || ch > 'f') {
+			return "", false
+		}
+	}
+	return value, true
+}
+
+// phoneNumberHash hashes a phone number the way clients hash their contacts:
+// hex SHA-256 of its digits.
+func phoneNumberHash(phone string) string {
+	var digits strings.Builder
+	for _, ch := range phone {
+		if unicode.IsDigit(ch) {
+			digits.WriteRune(ch)
+		}
+	}
+	sum := sha256.Sum256([]byte(digits.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// validateContacts normalizes uploaded contacts, dropping duplicates.
+func validateContacts(req uploadContactsRequest) ([]string, []int64, error) {
+	if len(req.PhoneHashes)+len(req.TelegramIDs) > maxContactsPerUpload {
+		return nil, nil, errors.New("too many contacts")
+	}
+	phones := make([]string, 0, len(req.PhoneHashes))
+	seenPhones := make(map[string]struct{}, len(req.PhoneHashes))
+	for _, raw := range req.PhoneHashes {
+		hash, ok := normalizePhoneHash(raw)
+		if !ok {
+			return nil, nil, errors.New("invalid phone hash")
+		}
+		if _, dup := seenPhones[hash]; dup {
+			continue
+		}
+		seenPhones[hash] = struct{}{}
+		phones = append(phones, hash)
+	}
+	telegramIDs := make([]int64, 0, len(req.TelegramIDs))
+	seenIDs := make(map[int64]struct{}, len(req.TelegramIDs))
+	for _, id := range req.TelegramIDs {
+		if id <= 0 {
+			return nil, nil, errors.New("invalid telegram id")
+		}
+		if _, dup := seenIDs[id]; dup {
+			continue
+		}
+		seenIDs[id] = struct{}{}
+		telegramIDs = append(telegramIDs, id)
+	}
+	return phones, telegramIDs, nil
+}
+
+// UploadContacts matches the user's address book against registered users.
+// Only the number of mutual friends is returned, so uploads can't be used to
+// probe which numbers are registered.
+func (h *Handler) UploadContacts(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "upload_contacts", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	if !h.contactsLimiter.Allow(strconv.FormatInt(userID, 10)) {
+		logger.Warn("action", "action", "upload_contacts", "status", "rate_limited")
+		writeError(w, http.StatusTooManyRequests, "contact upload limit reached")
+		return
+	}
+	var req uploadContactsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "upload_contacts", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	phones, telegramIDs, err := validateContacts(req)
+	if err != nil {
+		logger.Warn("action", "action", "upload_contacts", "status", "invalid_contacts", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	friends, err := h.repo.SaveContacts(ctx, userID, phones, telegramIDs, h.cfg.HMACSecret)
+	if err != nil {
+		logger.Error("action", "action", "upload_contacts", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "upload_contacts", "status", "success", "uploaded", len(phones)+len(telegramIDs), "friends", friends)
+	writeJSON(w, http.StatusOK, map[string]int{"friends": friends})
+}
+
+// ClearContacts removes the user's uploaded contacts.
+func (h *Handler) ClearContacts(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "clear_contacts", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.ClearContacts(ctx, userID); err != nil {
+		logger.Error("action", "action", "clear_contacts", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "clear_contacts", "status", "success")
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// RequestPhoneVerification asks the bot to send the user a button that shares
+// their Telegram contact. The number shared this way is the only one stored, so
+// users can't claim someone else's phone.
+func (h *Handler) RequestPhoneVerification(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := middleware.UserIDFromContext(r.Context()); !ok {
+		logger.Warn("action", "action", "request_phone_verification", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	telegramID, ok := middleware.TelegramIDFromContext(r.Context())
+	if !ok || telegramID <= 0 {
+		logger.Warn("action", "action", "request_phone_verification", "status", "no_telegram_account")
+		writeError(w, http.StatusBadRequest, "phone verification requires a telegram account")
+		return
+	}
+	if h.telegram == nil {
+		writeError(w, http.StatusServiceUnavailable, "telegram unavailable")
+		return
+	}
+	markup := &integrations.ReplyMarkup{
+		Keyboard:        [][]integrations.KeyboardButton{{{Text: "Поделиться номером", RequestContact: true}}},
+		ResizeKeyboard:  true,
+		OneTimeKeyboard: true,
+	}
+	if err := h.telegram.SendMessageWithMarkup(telegramID, "Подтвердите номер телефона, чтобы друзья из контактов нашли вас в SPACE.", markup); err != nil {
+		logger.Warn("action", "action", "request_phone_verification", "status", "send_failed", "error", err)
+		writeError(w, http.StatusBadGateway, "failed to send verification request")
+		return
+	}
+	logger.Info("action", "action", "request_phone_verification", "status", "success")
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// ListFriends lists mutual contacts of the user.
+func (h *Handler) ListFriends(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "list_friends", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListFriends(ctx, userID, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "list_friends", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, friendsResponse{Items: items, Total: total})
+}
+
+// GetPrivacySettings returns privacy settings of the user.
+func (h *Handler) GetPrivacySettings(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "get_privacy", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	settings, err := h.repo.GetPrivacySettings(ctx, userID)
+	if err != nil {
+		logger.Error("action", "action", "get_privacy", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdatePrivacySettings updates privacy settings of the user.
+func (h *Handler) UpdatePrivacySettings(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "update_privacy", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req updatePrivacyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "update_privacy", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if req.HideAttendance != nil {
+		if err := h.repo.SetHideAttendance(ctx, userID, *req.HideAttendance); err != nil {
+			logger.Error("action", "action", "update_privacy", "status", "db_error", "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+			return
+		}
+	}
+	settings, err := h.repo.GetPrivacySettings(ctx, userID)
+	if err != nil {
+		logger.Error("action", "action", "update_privacy", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "update_privacy", "status", "success", "hide_attendance", settings.HideAttendance)
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// FriendsEvents lists upcoming events the user's friends are going to.
+func (h *Handler) FriendsEvents(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "friends_events", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	limit := parseIntQuery(r, "limit", 20)
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, err := h.repo.ListFriendsUpcomingEvents(ctx, userID, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "friends_events", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	h.attachFriendsGoing(ctx, logger, "friends_events", userID, items)
+	logger.Info("action", "action", "friends_events", "status", "success", "count", len(items))
+	writeJSON(w, http.StatusOK, items)
+}
+
+// attachFriendsGoing fills the friends going preview of events. Failures are
+// logged and leave the preview empty.
+func (h *Handler) attachFriendsGoing(ctx context.Context, logger *slog.Logger, action string, userID int64, events []models.Event) {
+	if userID == 0 || len(events) == 0 {
+		return
+	}
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	friends, counts, err := h.repo.ListFriendsGoing(ctx, userID, ids, friendsGoingPreview)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "friends_going_error", "error", err)
+		return
+	}
+	for i := range events {
+		events[i].FriendsGoing = friends[events[i].ID]
+		events[i].FriendsGoingCount = counts[events[i].ID]
+	}
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateContacts verifies validate contacts behavior.
+func TestValidateContacts(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	phones, ids, err := validateContacts(uploadContactsRequest{
+		PhoneHashes: []string{" " + strings.ToUpper(hash) + " ", hash},
+		TelegramIDs: []int64{42, 42, 7},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(phones) != 1 || phones[0] != hash {
+		t.Fatalf("unexpected phones: %v", phones)
+	}
+	if len(ids) != 2 || ids[0] != 42 || ids[1] != 7 {
+		t.Fatalf("unexpected telegram ids: %v", ids)
+	}
+
+	invalid := []uploadContactsRequest{
+		{PhoneHashes: []string{"+79991234567"}},
+		{PhoneHashes: []string{strings.Repeat("zz", 32)}},
+		{TelegramIDs: []int64{0}},
+		{TelegramIDs: make([]int64, maxContactsPerUpload+1)},
+	}
+	for _, req := range invalid {
+		if _, _, err := validateContacts(req); err == nil {
+			t.Fatalf("expected error for %+v", req)
+		}
+	}
+}
+
+// TestPhoneNumberHash verifies phone number hash behavior.
+func TestPhoneNumberHash(t *testing.T) {
+	// sha256("79991234567"), the hash a client uploads for this number.
+	want := "3ea3b0328a0f8f8056414a434a80f7625bfe7f5317c055fac455076b255e6716"
+	for _, phone := range []string{"+7 (999) 123-45-67", "79991234567"} {
+		if got := phoneNumberHash(phone); got != want {
+			t.Fatalf("phoneNumberHash(%q) = %s, want %s", phone, got, want)
+		}
+	}
+}
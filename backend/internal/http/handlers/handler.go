@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"gigme/backend/internal/config"
+	"gigme/backend/internal/contentfilter"
 	"gigme/backend/internal/eventparser"
 	parsercore "gigme/backend/internal/eventparser/core"
 	"gigme/backend/internal/geocode"
@@ -33,6 +34,11 @@ type Handler struct {
 	logger           *slog.Logger
 	validator        *validator.Validate
 	joinLeaveLimiter *rate.WindowLimiter
+	contactsLimiter  *rate.WindowLimiter
+	emailLimiter     *rate.WindowLimiter
+	mailer           *integrations.Mailer
+	webPushKey       string
+	contentFilter    *contentfilter.Filter
 	replyTargetsMu   sync.RWMutex
 	adminReplyTarget map[int64]int64
 }
@@ -53,6 +59,15 @@ func New(repo *repository.Repository, s3 *integrations.S3Client, telegram *integ
 		logger:           logger,
 		validator:        validator.New(),
 		joinLeaveLimiter: rate.NewWindowLimiter(10, time.Minute),
+		contactsLimiter:  rate.NewWindowLimiter(5, time.Hour),
+		emailLimiter:     rate.NewWindowLimiter(5, time.Hour),
+		mailer:           newEmailMailer(cfg),
+		webPushKey:       loadWebPushPublicKey(cfg.Push, logger),
+		contentFilter: contentfilter.Default(repo, contentfilter.Config{
+			BlockWords:        cfg.ContentFilter.BlockWords,
+			HoldWords:         cfg.ContentFilter.HoldWords,
+			CommentsPerMinute: cfg.ContentFilter.CommentsPerMinute,
+		}),
 		adminReplyTarget: make(map[int64]int64),
 	}
 }
@@ -267,6 +267,10 @@ func (h *Handler) SetEventLandingPublished(w http.ResponseWriter, r *http.Reques
 			writeError(w, http.StatusBadRequest, "hidden event can't be published on landing")
 			return
 		}
+		if event.IsDraft() {
+			writeError(w, http.StatusBadRequest, "draft event can't be published on landing")
+			return
+		}
 		if event.IsPrivate {
 			writeError(w, http.StatusBadRequest, "private event can't be published on landing")
 			return
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gigme/backend/internal/models"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultMapMarkerZoom = 14
+	mapMaxZoom           = 22
+	mapMaxLat            = 85.05112878
+	mapCellsPerTileSide  = 4
+	mapTopFilters        = 3
+	mapMarkersLimit      = 500
+	mapCacheControl      = "public, max-age=60"
+	tileEpsilon          = 1e-9
+	// webMercatorExtent is the width of the EPSG:3857 world in meters.
+	webMercatorExtent = 40075016.68557849
+)
+
+// mapViewportResponse represents map viewport response.
+type mapViewportResponse struct {
+	Zoom     int                  `json:"zoom"`
+	BBox     models.BoundingBox   `json:"bbox"`
+	Mode     string               `json:"mode"`
+	Clusters []models.MapCluster  `json:"clusters,omitempty"`
+	Markers  []models.EventMarker `json:"markers,omitempty"`
+}
+
+// MapViewport returns clustered public events for a bbox and zoom level.
+// The bbox is expanded to whole tiles so equal viewports share cache entries.
+func (h *Handler) MapViewport(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	zoom, err := strconv.Atoi(r.URL.Query().Get("zoom"))
+	if err != nil || zoom < 0 || zoom > mapMaxZoom {
+		logger.Warn("action", "action", "map_viewport", "status", "invalid_zoom")
+		writeError(w, http.StatusBadRequest, "invalid zoom")
+		return
+	}
+	bbox, err := parseBBox(r.URL.Query().Get("bbox"))
+	if err != nil {
+		logger.Warn("action", "action", "map_viewport", "status", "invalid_bbox")
+		writeError(w, http.StatusBadRequest, "invalid bbox")
+		return
+	}
+	h.serveMapArea(w, r, logger, "map_viewport", snapBBoxToTiles(bbox, zoom), zoom)
+}
+
+// MapTile returns clustered public events for a single XYZ tile.
+func (h *Handler) MapTile(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	zoom, errZ := strconv.Atoi(chi.URLParam(r, "z"))
+	x, errX := strconv.Atoi(chi.URLParam(r, "x"))
+	y, errY := strconv.Atoi(chi.URLParam(r, "y"))
+	if errZ != nil || errX != nil || errY != nil || zoom < 0 || zoom > mapMaxZoom {
+		logger.Warn("action", "action", "map_tile", "status", "invalid_tile")
+		writeError(w, http.StatusBadRequest, "invalid tile")
+		return
+	}
+	n := 1 << zoom
+	if x < 0 || x >= n || y < 0 || y >= n {
+		logger.Warn("action", "action", "map_tile", "status", "invalid_tile")
+		writeError(w, http.StatusBadRequest, "invalid tile")
+		return
+	}
+	h.serveMapArea(w, r, logger, "map_tile", tileBBox(zoom, x, y), zoom)
+}
+
+// serveMapArea writes clusters or markers for bbox as a cacheable response.
+func (h *Handler) serveMapArea(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, bbox models.BoundingBox, zoom int) {
+	filters, err := parseEventFiltersQuery(r)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "invalid_filters")
+		writeError(w, http.StatusBadRequest, "invalid filters")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+
+	resp := mapViewportResponse{Zoom: zoom, BBox: bbox}
+	if zoom >= h.mapMarkerZoom() {
+		resp.Mode = "markers"
+		resp.Markers, err = h.repo.GetMapMarkers(ctx, bbox, filters, mapMarkersLimit)
+	} else {
+		resp.Mode = "clusters"
+		resp.Clusters, err = h.repo.GetMapClusters(ctx, bbox, mapCellSize(zoom), filters, mapTopFilters)
+	}
+	if err != nil {
+		logger.Error("action", "action", action, "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", action, "status", "success", "zoom", zoom, "mode", resp.Mode, "count", len(resp.Clusters)+len(resp.Markers))
+	writeCacheableJSON(w, r, resp)
+}
+
+// mapMarkerZoom returns the zoom level from which individual markers are returned.
+func (h *Handler) mapMarkerZoom() int {
+	if h.cfg != nil && h.cfg.MapMarkerZoom > 0 {
+		return h.cfg.MapMarkerZoom
+	}
+	return defaultMapMarkerZoom
+}
+
+// parseBBox parses "minLng,minLat,maxLng,maxLat".
+func parseBBox(raw string) (models.BoundingBox, error) {
+	parts := strings.Split(raw, ",")
+	if len(parts) != 4 {
+		return models.BoundingBox{}, fmt.Errorf("bbox must have 4 values")
+	}
+	values := make([]float64, 4)
+	for i, part := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+			return models.BoundingBox{}, fmt.Errorf("invalid bbox value %q", part)
+		}
+		values[i] = value
+	}
+	bbox := models.BoundingBox{MinLng: values[0], MinLat: values[1], MaxLng: values[2], MaxLat: values[3]}
+	if bbox.MinLng < -180 || bbox.MaxLng > 180 || bbox.MinLat < -90 || bbox.MaxLat > 90 {
+		return models.BoundingBox{}, fmt.Errorf("bbox out of range")
+	}
+	if bbox.MinLng >= bbox.MaxLng || bbox.MinLat >= bbox.MaxLat {
+		return models.BoundingBox{}, fmt.Errorf("bbox is empty")
+	}
+	return bbox, nil
+}
+
+// snapBBoxToTiles expands bbox to the XYZ tiles covering it at zoom.
+// Edges lying on a tile boundary do not pull in the neighbouring tile.
+func snapBBoxToTiles(bbox models.BoundingBox, zoom int) models.BoundingBox {
+	n := int(1) << zoom
+	minX := clampTile(int(math.Floor(tileXPosition(bbox.MinLng, zoom)+tileEpsilon)), n)
+	maxX := clampTile(int(math.Ceil(tileXPosition(bbox.MaxLng, zoom)-tileEpsilon))-1, n)
+	minY := clampTile(int(math.Floor(tileYPosition(bbox.MaxLat, zoom)+tileEpsilon)), n)
+	maxY := clampTile(int(math.Ceil(tileYPosition(bbox.MinLat, zoom)-tileEpsilon))-1, n)
+	if maxX < minX {
+		maxX = minX
+	}
+	if maxY < minY {
+		maxY = minY
+	}
+	top := tileBBox(zoom, minX, minY)
+	bottom := tileBBox(zoom, maxX, maxY)
+	return models.BoundingBox{
+		MinLng: top.MinLng,
+		MinLat: bottom.MinLat,
+		MaxLng: bottom.MaxLng,
+		MaxLat: top.MaxLat,
+	}
+}
+
+// tileBBox returns the lng/lat bounds of an XYZ tile.
+func tileBBox(zoom, x, y int) models.BoundingBox {
+	n := float64(int(1) << zoom)
+	return models.BoundingBox{
+		MinLng: float64(x)/n*360 - 180,
+		MinLat: tileLat(float64(y+1), n),
+		MaxLng: float64(x+1)/n*360 - 180,
+		MaxLat: tileLat(float64(y), n),
+	}
+}
+
+// tileLat returns the latitude of a tile row edge.
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// tileXPosition returns the fractional tile column of lng.
+func tileXPosition(lng float64, zoom int) float64 {
+	return (lng + 180) / 360 * float64(int(1)<<zoom)
+}
+
+// tileYPosition returns the fractional tile row of lat.
+func tileYPosition(lat float64, zoom int) float64 {
+	lat = math.Max(-mapMaxLat, math.Min(mapMaxLat, lat))
+	rad := lat * math.Pi / 180
+	return (1 - math.Log(math.Tan(rad)+1/math.Cos(rad))/math.Pi) / 2 * float64(int(1)<<zoom)
+}
+
+// clampTile clamps a tile index to [0, n).
+func clampTile(value, n int) int {
+	if value < 0 {
+		return 0
+	}
+	if value >= n {
+		return n - 1
+	}
+	return value
+}
+
+// mapCellSize returns the clustering cell size in Web Mercator meters.
+// Cells split every tile into a fixed grid, so tile edges are cell edges.
+func mapCellSize(zoom int) float64 {
+	return webMercatorExtent / float64(int(1)<<zoom) / mapCellsPerTileSide
+}
+
+// writeCacheableJSON writes a publicly cacheable JSON response with an ETag.
+func writeCacheableJSON(w http.ResponseWriter, r *http.Request, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "encode error")
+		return
+	}
+	sum := sha1.Sum(body)
+	etag := `"` + hex.EncodeToString(sum[:]) + `"`
+	w.Header().Set("Cache-Control", mapCacheControl)
+	w.Header().Set("ETag", etag)
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// newMapTestRouter mounts the map endpoints the way the API does.
+func newMapTestRouter(h *Handler) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/events/viewport", h.MapViewport)
+	r.Get("/events/tiles/{z}/{x}/{y}", h.MapTile)
+	return r
+}
+
+// TestMapViewportRejectsInvalidInput verifies map viewport rejects invalid input behavior.
+func TestMapViewportRejectsInvalidInput(t *testing.T) {
+	h := New(nil, nil, nil, nil, &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := newMapTestRouter(h)
+	for _, target := range []string{
+		"/events/viewport?zoom=10",
+		"/events/viewport?zoom=23&bbox=37.62,55.74,37.63,55.76",
+		"/events/viewport?zoom=10&bbox=1,2,3",
+		"/events/viewport?zoom=10&bbox=10,10,5,20",
+		"/events/viewport?zoom=10&bbox=0,-91,1,1",
+		"/events/viewport?zoom=10&bbox=a,b,c,d",
+		"/events/tiles/10/1024/0",
+		"/events/tiles/10/0/-1",
+		"/events/tiles/x/0/0",
+	} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected 400, got %d", target, rec.Code)
+		}
+	}
+}
+
+// TestMapViewportSnapsToTiles verifies map viewport snaps to tiles behavior.
+func TestMapViewportSnapsToTiles(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection failed: %v", err)
+	}
+	defer pool.Close()
+
+	h := New(repository.New(pool), nil, nil, nil, &config.Config{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	router := newMapTestRouter(h)
+	get := func(target string) (mapViewportResponse, string) {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", target, rec.Code, rec.Body.String())
+		}
+		var resp mapViewportResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: decode response: %v", target, err)
+		}
+		return resp, rec.Header().Get("ETag")
+	}
+
+	// Moscow center at zoom 10 lies in tile 619/320.
+	viewport, viewportTag := get("/events/viewport?zoom=10&bbox=37.62,55.74,37.63,55.76")
+	tile, tileTag := get("/events/tiles/10/619/320")
+	if viewport.BBox != tile.BBox {
+		t.Fatalf("expected viewport to snap to tile bbox %+v, got %+v", tile.BBox, viewport.BBox)
+	}
+	if viewport.BBox.MinLng > 37.6173 || viewport.BBox.MaxLng < 37.6173 || viewport.BBox.MinLat > 55.7558 || viewport.BBox.MaxLat < 55.7558 {
+		t.Fatalf("tile bbox does not contain Moscow center: %+v", viewport.BBox)
+	}
+	if viewportTag == "" || viewportTag != tileTag {
+		t.Fatalf("expected equal etags, got %q and %q", viewportTag, tileTag)
+	}
+
+	// A viewport lying exactly on tile edges must not pull in neighbouring tiles.
+	b := tile.BBox
+	again, _ := get(fmt.Sprintf("/events/viewport?zoom=10&bbox=%g,%g,%g,%g", b.MinLng, b.MinLat, b.MaxLng, b.MaxLat))
+	if again.BBox != tile.BBox {
+		t.Fatalf("snapping must be idempotent, got %+v", again.BBox)
+	}
+
+	world, _ := get("/events/tiles/0/0/0")
+	if world.BBox.MinLng != -180 || world.BBox.MaxLng != 180 || math.Abs(world.BBox.MaxLat-mapMaxLat) > 1e-6 {
+		t.Fatalf("unexpected world bbox: %+v", world.BBox)
+	}
+}
+
+// TestMapCellSizeAlignsWithTiles verifies map cell size aligns with tiles behavior.
+func TestMapCellSizeAlignsWithTiles(t *testing.T) {
+	for zoom := 0; zoom <= mapMaxZoom; zoom++ {
+		tileSize := webMercatorExtent / float64(int(1)<<zoom)
+		cells := (webMercatorExtent / 2) / mapCellSize(zoom)
+		if math.Abs(cells-math.Round(cells)) > 1e-6 {
+			t.Fatalf("zoom %d: world edge is not a cell edge", zoom)
+		}
+		if math.Abs(tileSize/mapCellSize(zoom)-mapCellsPerTileSide) > 1e-9 {
+			t.Fatalf("zoom %d: unexpected cells per tile", zoom)
+		}
+	}
+}
@@ -111,7 +111,7 @@ func (h *Handler) UpsertPushToken(w http.ResponseWriter, r *http.Request) {
 		writeError(w, http.StatusBadRequest, "invalid token")
 		return
 	}
-	if req.Platform != "android" && req.Platform != "ios" && req.Platform != "web" {
+	if req.Platform != "android" && req.Platform != "ios" && req.Platform != "ios_apns" && req.Platform != "web" {
 		writeError(w, http.StatusBadRequest, "invalid platform")
 		return
 	}
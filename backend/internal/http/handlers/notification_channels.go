@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/integrations/push"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+)
+
+// webPushHosts are the push services browsers subscribe with. Only their
+// endpoints are accepted so the worker never posts to arbitrary hosts.
+var webPushHosts = []string{"fcm.googleapis.com"}
+
+// webPushHostSuffixes are domains whose subdomains are push services.
+var webPushHostSuffixes = []string{".push.services.mozilla.com", ".push.apple.com", ".notify.windows.com"}
+
+// webPushSubscriptionRequest represents a browser PushSubscription as
+// serialized by PushSubscription.toJSON().
+type webPushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint"`
+	Keys     struct {
+		P256dh string `json:"p256dh"`
+		Auth   string `json:"auth"`
+	} `json:"keys"`
+}
+
+// adminNotificationsResponse represents admin notifications response.
+type adminNotificationsResponse struct {
+	Items []models.AdminNotificationJob `json:"items"`
+	Total int                           `json:"total"`
+}
+
+// validateWebPushSubscription checks the endpoint and keys of a subscription.
+func validateWebPushSubscription(req webPushSubscriptionRequest) (models.WebPushSubscription, string) {
+	endpoint := strings.TrimSpace(req.Endpoint)
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" || len(endpoint) > 2048 {
+		return models.WebPushSubscription{}, "invalid endpoint"
+	}
+	if parsed.User != nil || (parsed.Port() != "" && parsed.Port() != "443") || !isWebPushHost(parsed.Hostname()) {
+		return models.WebPushSubscription{}, "unsupported push service"
+	}
+	p256dh := strings.TrimRight(strings.TrimSpace(req.Keys.P256dh), "=")
+	if key, err := base64.RawURLEncoding.DecodeString(p256dh); err != nil || len(key) != 65 {
+		return models.WebPushSubscription{}, "invalid p256dh key"
+	}
+	auth := strings.TrimRight(strings.TrimSpace(req.Keys.Auth), "=")
+	if secret, err := base64.RawURLEncoding.DecodeString(auth); err != nil || len(secret) != 16 {
+		return models.WebPushSubscription{}, "invalid auth secret"
+	}
+	return models.WebPushSubscription{Endpoint: endpoint, P256dh: p256dh, Auth: auth}, ""
+}
+
+// isWebPushHost reports whether host belongs to a known push service.
+func isWebPushHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, known := range webPushHosts {
+		if host == known {
+			return true
+		}
+	}
+	for _, suffix := range webPushHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadWebPushPublicKey returns the VAPID public key browsers subscribe with,
+// or "" when Web Push is not configured.
+func loadWebPushPublicKey(cfg config.PushConfig, logger *slog.Logger) string {
+	if cfg.WebPushKeyFile == "" {
+		return ""
+	}
+	key, err := os.ReadFile(cfg.WebPushKeyFile)
+	if err == nil {
+		var sender *push.WebPushSender
+		if sender, err = push.NewWebPushSender(push.WebPushConfig{PrivateKey: string(key), Subject: cfg.WebPushSubject}, nil); err == nil {
+			return sender.PublicKey()
+		}
+	}
+	logger.Warn("web_push_key_unavailable", "error", err)
+	return ""
+}
+
+// WebPushPublicKey returns the VAPID public key for PushManager.subscribe.
+func (h *Handler) WebPushPublicKey(w http.ResponseWriter, r *http.Request) {
+	if h.webPushKey == "" {
+		writeError(w, http.StatusNotFound, "web push is not configured")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"publicKey": h.webPushKey})
+}
+
+// UpsertWebPushSubscription stores a browser push subscription of the current user.
+func (h *Handler) UpsertWebPushSubscription(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "upsert_web_push", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req webPushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "upsert_web_push", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	sub, msg := validateWebPushSubscription(req)
+	if msg != "" {
+		logger.Warn("action", "action", "upsert_web_push", "status", "invalid_subscription", "reason", msg)
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+	sub.UserID = userID
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.UpsertWebPushSubscription(ctx, sub); err != nil {
+		if errors.Is(err, repository.ErrWebPushSubscriptionTaken) {
+			logger.Warn("action", "action", "upsert_web_push", "status", "endpoint_taken")
+			writeError(w, http.StatusConflict, "subscription belongs to another user")
+			return
+		}
+		logger.Error("action", "action", "upsert_web_push", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+
+	logger.Info("action", "action", "upsert_web_push", "status", "success")
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// DeleteWebPushSubscription removes a browser push subscription of the current user.
+func (h *Handler) DeleteWebPushSubscription(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "delete_web_push", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	var req webPushSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "delete_web_push", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	endpoint := strings.TrimSpace(req.Endpoint)
+	if endpoint == "" {
+		writeError(w, http.StatusBadRequest, "endpoint is required")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	deleted, err := h.repo.DeleteWebPushSubscription(ctx, userID, endpoint)
+	if err != nil {
+		logger.Error("action", "action", "delete_web_push", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if !deleted {
+		writeError(w, http.StatusNotFound, "subscription not found")
+		return
+	}
+
+	logger.Info("action", "action", "delete_web_push", "status", "success")
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// ListAdminNotifications lists notification jobs with per-channel delivery
+// results, so admins can see why a notification wasn't delivered.
+func (h *Handler) ListAdminNotifications(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_list_notifications"); !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	var userID *int64
+	if raw := strings.TrimSpace(r.URL.Query().Get("userId")); raw != "" {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || id <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid userId")
+			return
+		}
+		userID = &id
+	}
+	var status *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+		switch raw {
+		case "pending", "processing", "sent", "failed", "skipped":
+			status = &raw
+		default:
+			writeError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+	var kind *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("kind")); raw != "" {
+		kind = &raw
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListAdminNotificationJobs(ctx, userID, status, kind, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "admin_list_notifications", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, adminNotificationsResponse{Items: items, Total: total})
+}
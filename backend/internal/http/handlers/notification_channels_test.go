@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// TestValidateWebPushSubscription verifies validate web push subscription behavior.
+func TestValidateWebPushSubscription(t *testing.T) {
+	p256dh := base64.RawURLEncoding.EncodeToString(append([]byte{4}, make([]byte, 64)...))
+	auth := base64.URLEncoding.EncodeToString(make([]byte, 16))
+
+	var req webPushSubscriptionRequest
+	req.Endpoint = " https://fcm.googleapis.com/fcm/send/abc "
+	req.Keys.P256dh = p256dh
+	req.Keys.Auth = auth
+	sub, msg := validateWebPushSubscription(req)
+	if msg != "" {
+		t.Fatalf("unexpected error: %s", msg)
+	}
+	if sub.Endpoint != "https://fcm.googleapis.com/fcm/send/abc" || sub.P256dh != p256dh || strings.HasSuffix(sub.Auth, "=") {
+		t.Fatalf("unexpected subscription: %+v", sub)
+	}
+
+	cases := map[string]func(*webPushSubscriptionRequest){
+		"invalid endpoint":    func(r *webPushSubscriptionRequest) { r.Endpoint = "http://fcm.googleapis.com/fcm/send/abc" },
+		"invalid p256dh key":  func(r *webPushSubscriptionRequest) { r.Keys.P256dh = auth },
+		"invalid auth secret": func(r *webPushSubscriptionRequest) { r.Keys.Auth = "not base64!" },
+	}
+	for _, endpoint := range []string{
+		"https://push.example.com/send/abc",
+		"https://169.254.169.254/latest/meta-data",
+		"https://fcm.googleapis.com:8443/fcm/send/abc",
+		"https://fcm.googleapis.com.evil.example/fcm/send/abc",
+	} {
+		bad := req
+		bad.Endpoint = endpoint
+		if _, msg := validateWebPushSubscription(bad); msg != "unsupported push service" {
+			t.Fatalf("expected %s to be rejected, got %q", endpoint, msg)
+		}
+	}
+	for _, endpoint := range []string{
+		"https://updates.push.services.mozilla.com/wpush/v2/abc",
+		"https://web.push.apple.com/QGuQyavXutnMH",
+		"https://wns2-par02p.notify.windows.com/w/?token=abc",
+	} {
+		ok := req
+		ok.Endpoint = endpoint
+		if _, msg := validateWebPushSubscription(ok); msg != "" {
+			t.Fatalf("expected %s to be accepted, got %q", endpoint, msg)
+		}
+	}
+	for want, mutate := range cases {
+		bad := req
+		mutate(&bad)
+		if _, msg := validateWebPushSubscription(bad); msg != want {
+			t.Fatalf("expected %q, got %q", want, msg)
+		}
+	}
+}
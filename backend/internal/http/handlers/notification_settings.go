@@ -0,0 +1,306 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/config"
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/integrations"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxReminderOffsets       = 5
+	maxReminderOffsetMinutes = 7 * 24 * 60
+	maxNotificationEmailLen  = 254
+	emailTokenTTL            = 24 * time.Hour
+	maxEmailTokenLength      = 128
+)
+
+// updateNotificationSettingsRequest represents update notification settings
+// request. Quiet hours are set together; two empty strings disable them. A
+// zero digest weekday turns the weekly digest off. Channels is the preferred
+// delivery order and an empty email removes the address.
+type updateNotificationSettingsRequest struct {
+	AllNewEvents    *bool    `json:"allNewEvents"`
+	Muted           []string `json:"muted"`
+	ReminderOffsets []int    `json:"reminderOffsets"`
+	QuietHoursStart *string  `json:"quietHoursStart"`
+	QuietHoursEnd   *string  `json:"quietHoursEnd"`
+	Timezone        *string  `json:"timezone"`
+	DigestWeekday   *int     `json:"digestWeekday"`
+	DigestTime      *string  `json:"digestTime"`
+	Channels        []string `json:"channels"`
+	Email           *string  `json:"email"`
+}
+
+// validateNotificationSettings converts a settings request into a repository
+// update, dropping duplicates and sorting reminder offsets from the earliest.
+func validateNotificationSettings(req updateNotificationSettingsRequest) (repository.NotificationSettingsUpdate, error) {
+	update := repository.NotificationSettingsUpdate{AllNewEvents: req.AllNewEvents}
+	if req.Muted != nil {
+		update.Muted = make([]string, 0, len(req.Muted))
+		seen := make(map[string]struct{}, len(req.Muted))
+		for _, raw := range req.Muted {
+			category := strings.ToLower(strings.TrimSpace(raw))
+			if !slices.Contains(models.NotificationCategories, category) {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid notification category")
+			}
+			if _, dup := seen[category]; dup {
+				continue
+			}
+			seen[category] = struct{}{}
+			update.Muted = append(update.Muted, category)
+		}
+	}
+	if req.ReminderOffsets != nil {
+		update.ReminderOffsets = make([]int, 0, len(req.ReminderOffsets))
+		seen := make(map[int]struct{}, len(req.ReminderOffsets))
+		for _, offset := range req.ReminderOffsets {
+			if offset <= 0 || offset > maxReminderOffsetMinutes {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid reminder offset")
+			}
+			if _, dup := seen[offset]; dup {
+				continue
+			}
+			seen[offset] = struct{}{}
+			update.ReminderOffsets = append(update.ReminderOffsets, offset)
+		}
+		if len(update.ReminderOffsets) > maxReminderOffsets {
+			return repository.NotificationSettingsUpdate{}, errors.New("too many reminder offsets")
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(update.ReminderOffsets)))
+	}
+	if req.QuietHoursStart != nil || req.QuietHoursEnd != nil {
+		if req.QuietHoursStart == nil || req.QuietHoursEnd == nil {
+			return repository.NotificationSettingsUpdate{}, errors.New("quiet hours need start and end")
+		}
+		update.SetQuietHours = true
+		start := strings.TrimSpace(*req.QuietHoursStart)
+		end := strings.TrimSpace(*req.QuietHoursEnd)
+		if start != "" || end != "" {
+			startMinutes, okStart := repository.ParseClockMinutes(start)
+			endMinutes, okEnd := repository.ParseClockMinutes(end)
+			if !okStart || !okEnd || startMinutes == endMinutes {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid quiet hours")
+			}
+			update.QuietHoursStart = &startMinutes
+			update.QuietHoursEnd = &endMinutes
+		}
+	}
+	if req.Timezone != nil {
+		timezone := strings.TrimSpace(*req.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid timezone")
+			}
+		}
+		update.Timezone = &timezone
+	}
+	if req.DigestWeekday != nil {
+		if *req.DigestWeekday < 0 || *req.DigestWeekday > 7 {
+			return repository.NotificationSettingsUpdate{}, errors.New("invalid digest weekday")
+		}
+		update.DigestWeekday = req.DigestWeekday
+	}
+	if req.DigestTime != nil {
+		minutes, ok := repository.ParseClockMinutes(*req.DigestTime)
+		if !ok {
+			return repository.NotificationSettingsUpdate{}, errors.New("invalid digest time")
+		}
+		update.DigestTime = &minutes
+	}
+	if req.Channels != nil {
+		update.Channels = make([]string, 0, len(req.Channels))
+		for _, raw := range req.Channels {
+			channel := strings.ToLower(strings.TrimSpace(raw))
+			if !slices.Contains(models.NotificationChannels, channel) {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid notification channel")
+			}
+			if !slices.Contains(update.Channels, channel) {
+				update.Channels = append(update.Channels, channel)
+			}
+		}
+	}
+	if req.Email != nil {
+		email := strings.TrimSpace(*req.Email)
+		if email != "" {
+			parsed, err := mail.ParseAddress(email)
+			if err != nil || parsed.Address != email || len(email) > maxNotificationEmailLen {
+				return repository.NotificationSettingsUpdate{}, errors.New("invalid email")
+			}
+		}
+		update.Email = &email
+	}
+	return update, nil
+}
+
+// GetNotificationSettings returns notification settings of the current user.
+func (h *Handler) GetNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "get_notification_settings", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	settings, err := h.repo.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		logger.Error("action", "action", "get_notification_settings", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// UpdateNotificationSettings updates notification settings of the current user.
+func (h *Handler) UpdateNotificationSettings(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "update_notification_settings", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req updateNotificationSettingsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "update_notification_settings", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	update, err := validateNotificationSettings(req)
+	if err != nil {
+		logger.Warn("action", "action", "update_notification_settings", "status", "invalid_settings", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var emailToken string
+	if update.Email != nil && *update.Email != "" {
+		if h.mailer == nil {
+			writeError(w, http.StatusBadRequest, "email notifications are not available")
+			return
+		}
+		if !h.emailLimiter.Allow(strconv.FormatInt(userID, 10)) {
+			logger.Warn("action", "action", "update_notification_settings", "status", "email_rate_limited")
+			writeError(w, http.StatusTooManyRequests, "email confirmation limit reached")
+			return
+		}
+		if emailToken, err = generateEmailToken(); err != nil {
+			logger.Error("action", "action", "update_notification_settings", "status", "token_error", "error", err)
+			writeError(w, http.StatusInternalServerError, "token error")
+			return
+		}
+		update.EmailTokenHash = emailTokenHash(emailToken)
+		update.EmailTokenTTL = emailTokenTTL
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if err := h.repo.UpdateNotificationSettings(ctx, userID, update, time.Now()); err != nil {
+		if errors.Is(err, repository.ErrInvalidTimezone) {
+			logger.Warn("action", "action", "update_notification_settings", "status", "invalid_settings", "error", err)
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		logger.Error("action", "action", "update_notification_settings", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	settings, err := h.repo.GetNotificationSettings(ctx, userID)
+	if err != nil {
+		logger.Error("action", "action", "update_notification_settings", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if emailToken != "" && settings.PendingEmail == *update.Email {
+		if err := h.sendEmailConfirmation(ctx, settings.PendingEmail, emailToken); err != nil {
+			logger.Error("action", "action", "update_notification_settings", "status", "email_error", "error", err)
+			writeError(w, http.StatusBadGateway, "confirmation email failed")
+			return
+		}
+	}
+	logger.Info("action", "action", "update_notification_settings", "status", "success", "all_new_events", settings.AllNewEvents, "muted", settings.Muted, "reminder_offsets", settings.ReminderOffsets)
+	writeJSON(w, http.StatusOK, settings)
+}
+
+// ConfirmEmail confirms a pending notification email by the token from the
+// link sent to it.
+func (h *Handler) ConfirmEmail(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	token := strings.TrimSpace(r.URL.Query().Get("token"))
+	if token == "" || len(token) > maxEmailTokenLength {
+		writeText(w, http.StatusNotFound, "Ссылка недействительна или устарела.")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if _, err := h.repo.ConfirmEmail(ctx, emailTokenHash(token), time.Now()); err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("action", "action", "confirm_email", "status", "db_error", "error", err)
+			writeText(w, http.StatusInternalServerError, "Не удалось подтвердить адрес, попробуйте позже.")
+			return
+		}
+		logger.Warn("action", "action", "confirm_email", "status", "invalid_token")
+		writeText(w, http.StatusNotFound, "Ссылка недействительна или устарела.")
+		return
+	}
+	logger.Info("action", "action", "confirm_email", "status", "success")
+	writeText(w, http.StatusOK, "Адрес подтверждён, уведомления будут приходить на него.")
+}
+
+// newEmailMailer returns the mailer for confirmation links, or nil when SMTP
+// or the public API url is not configured.
+func newEmailMailer(cfg *config.Config) *integrations.Mailer {
+	if cfg.SMTP.Host == "" || cfg.SMTP.From == "" || strings.TrimSpace(cfg.APIPublicURL) == "" {
+		return nil
+	}
+	return integrations.NewMailer(integrations.SMTPConfig{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	})
+}
+
+// sendEmailConfirmation emails the confirmation link for a pending address.
+func (h *Handler) sendEmailConfirmation(ctx context.Context, email, token string) error {
+	link := strings.TrimRight(strings.TrimSpace(h.cfg.APIPublicURL), "/") + "/email/confirm?token=" + url.QueryEscape(token)
+	body := fmt.Sprintf("Чтобы получать уведомления на этот адрес, подтвердите его:\n%s\n\nСсылка действует %d часа. Если вы не указывали этот адрес, просто проигнорируйте письмо.", link, int(emailTokenTTL.Hours()))
+	return h.mailer.Send(ctx, email, "Подтвердите адрес для уведомлений", body)
+}
+
+// generateEmailToken returns a random email confirmation token.
+func generateEmailToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// emailTokenHash returns the stored form of an email confirmation token.
+func emailTokenHash(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
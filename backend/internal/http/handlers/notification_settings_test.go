@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"testing"
+)
+
+// TestValidateNotificationSettings verifies validate notification settings behavior.
+func TestValidateNotificationSettings(t *testing.T) {
+	start, end, timezone := "23:30", "08:00", "Europe/Moscow"
+	update, err := validateNotificationSettings(updateNotificationSettingsRequest{
+		Muted:           []string{"Comments", "comments", "new_events"},
+		ReminderOffsets: []int{60, 1440, 60},
+		QuietHoursStart: &start,
+		QuietHoursEnd:   &end,
+		Timezone:        &timezone,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(update.Muted) != 2 || update.Muted[0] != "comments" {
+		t.Fatalf("unexpected muted: %v", update.Muted)
+	}
+	if len(update.ReminderOffsets) != 2 || update.ReminderOffsets[0] != 1440 || update.ReminderOffsets[1] != 60 {
+		t.Fatalf("unexpected offsets: %v", update.ReminderOffsets)
+	}
+	if !update.SetQuietHours || *update.QuietHoursStart != 23*60+30 || *update.QuietHoursEnd != 8*60 {
+		t.Fatalf("unexpected quiet hours: %+v", update)
+	}
+	if update.Timezone == nil || *update.Timezone != timezone {
+		t.Fatalf("unexpected timezone: %v", update.Timezone)
+	}
+}
+
+// TestValidateNotificationSettingsClearsQuietHours verifies validate notification settings clears quiet hours behavior.
+func TestValidateNotificationSettingsClearsQuietHours(t *testing.T) {
+	empty := ""
+	update, err := validateNotificationSettings(updateNotificationSettingsRequest{QuietHoursStart: &empty, QuietHoursEnd: &empty})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !update.SetQuietHours || update.QuietHoursStart != nil || update.QuietHoursEnd != nil {
+		t.Fatalf("expected quiet hours to be cleared, got %+v", update)
+	}
+	if update.Muted != nil || update.ReminderOffsets != nil {
+		t.Fatalf("expected untouched fields to stay nil, got %+v", update)
+	}
+}
+
+// TestValidateNotificationSettingsRejectsInvalid verifies validate notification settings rejects invalid behavior.
+func TestValidateNotificationSettingsRejectsInvalid(t *testing.T) {
+	start, same, bad, zone := "22:00", "22:00", "25:00", "Mars/Base"
+	eight := 8
+	email := "Someone <someone@example.com>"
+	cases := []updateNotificationSettingsRequest{
+		{Muted: []string{"everything"}},
+		{ReminderOffsets: []int{0}},
+		{ReminderOffsets: []int{maxReminderOffsetMinutes + 1}},
+		{ReminderOffsets: []int{1, 2, 3, 4, 5, 6}},
+		{QuietHoursStart: &start},
+		{QuietHoursStart: &start, QuietHoursEnd: &same},
+		{QuietHoursStart: &start, QuietHoursEnd: &bad},
+		{Timezone: &zone},
+		{DigestWeekday: &eight},
+		{DigestTime: &bad},
+		{Channels: []string{"pigeon"}},
+		{Email: &email},
+	}
+	for i, req := range cases {
+		if _, err := validateNotificationSettings(req); err == nil {
+			t.Fatalf("case %d: expected error", i)
+		}
+	}
+}
+
+// TestValidateNotificationSettingsDigest verifies validate notification settings digest behavior.
+func TestValidateNotificationSettingsDigest(t *testing.T) {
+	weekday, at := 7, "18:30"
+	update, err := validateNotificationSettings(updateNotificationSettingsRequest{DigestWeekday: &weekday, DigestTime: &at})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if update.DigestWeekday == nil || *update.DigestWeekday != 7 {
+		t.Fatalf("unexpected digest weekday: %v", update.DigestWeekday)
+	}
+	if update.DigestTime == nil || *update.DigestTime != 18*60+30 {
+		t.Fatalf("unexpected digest time: %v", update.DigestTime)
+	}
+}
+
+// TestValidateNotificationSettingsChannels verifies validate notification settings channels behavior.
+func TestValidateNotificationSettingsChannels(t *testing.T) {
+	email, empty := " user@example.com ", ""
+	update, err := validateNotificationSettings(updateNotificationSettingsRequest{
+		Channels: []string{"Push", "email", "push"},
+		Email:    &email,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(update.Channels) != 2 || update.Channels[0] != "push" || update.Channels[1] != "email" {
+		t.Fatalf("unexpected channels: %v", update.Channels)
+	}
+	if update.Email == nil || *update.Email != "user@example.com" {
+		t.Fatalf("unexpected email: %v", update.Email)
+	}
+	cleared, err := validateNotificationSettings(updateNotificationSettingsRequest{Email: &empty})
+	if err != nil || cleared.Email == nil || *cleared.Email != "" {
+		t.Fatalf("expected email to be cleared: %v %v", cleared.Email, err)
+	}
+}
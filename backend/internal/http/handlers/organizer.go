@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"gigme/backend/internal/http/middleware"
+)
+
+// requireOrganizer returns the current user, whose events scope the organizer API.
+// Ownership itself is enforced by the repository queries.
+func (h *Handler) requireOrganizer(logger *slog.Logger, w http.ResponseWriter, r *http.Request, action string) (int64, bool) {
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", action, "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return 0, false
+	}
+	return userID, true
+}
+
+// ListOrganizerOrders lists orders of the current user's events.
+func (h *Handler) ListOrganizerOrders(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_list_orders")
+	if !ok {
+		return
+	}
+	h.listOrders(w, r, logger, "organizer_list_orders", &ownerID)
+}
+
+// OrganizerStats returns ticket stats of the current user's events.
+func (h *Handler) OrganizerStats(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_stats")
+	if !ok {
+		return
+	}
+	h.ticketStats(w, r, logger, "organizer_stats", &ownerID)
+}
+
+// ListOrganizerTicketProducts lists ticket products of the current user's events.
+func (h *Handler) ListOrganizerTicketProducts(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_list_ticket_products")
+	if !ok {
+		return
+	}
+	h.listTicketProducts(w, r, logger, "organizer_list_ticket_products", &ownerID)
+}
+
+// CreateOrganizerTicketProduct creates a ticket product for the current user's event.
+func (h *Handler) CreateOrganizerTicketProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_create_ticket_product")
+	if !ok {
+		return
+	}
+	h.createTicketProduct(w, r, logger, "organizer_create_ticket_product", &ownerID)
+}
+
+// PatchOrganizerTicketProduct updates a ticket product of the current user's event.
+func (h *Handler) PatchOrganizerTicketProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_patch_ticket_product")
+	if !ok {
+		return
+	}
+	h.patchTicketProduct(w, r, logger, "organizer_patch_ticket_product", &ownerID)
+}
+
+// DeleteOrganizerTicketProduct deletes a ticket product of the current user's event.
+func (h *Handler) DeleteOrganizerTicketProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_delete_ticket_product")
+	if !ok {
+		return
+	}
+	h.deleteTicketProduct(w, r, logger, "organizer_delete_ticket_product", &ownerID)
+}
+
+// ListOrganizerTransferProducts lists transfer products of the current user's events.
+func (h *Handler) ListOrganizerTransferProducts(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_list_transfer_products")
+	if !ok {
+		return
+	}
+	h.listTransferProducts(w, r, logger, "organizer_list_transfer_products", &ownerID)
+}
+
+// CreateOrganizerTransferProduct creates a transfer product for the current user's event.
+func (h *Handler) CreateOrganizerTransferProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_create_transfer_product")
+	if !ok {
+		return
+	}
+	h.createTransferProduct(w, r, logger, "organizer_create_transfer_product", &ownerID)
+}
+
+// PatchOrganizerTransferProduct updates a transfer product of the current user's event.
+func (h *Handler) PatchOrganizerTransferProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_patch_transfer_product")
+	if !ok {
+		return
+	}
+	h.patchTransferProduct(w, r, logger, "organizer_patch_transfer_product", &ownerID)
+}
+
+// DeleteOrganizerTransferProduct deletes a transfer product of the current user's event.
+func (h *Handler) DeleteOrganizerTransferProduct(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_delete_transfer_product")
+	if !ok {
+		return
+	}
+	h.deleteTransferProduct(w, r, logger, "organizer_delete_transfer_product", &ownerID)
+}
+
+// ListOrganizerPromoCodes lists promo codes of the current user's events.
+func (h *Handler) ListOrganizerPromoCodes(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_list_promo_codes")
+	if !ok {
+		return
+	}
+	h.listPromoCodes(w, r, logger, "organizer_list_promo_codes", &ownerID)
+}
+
+// CreateOrganizerPromoCode creates a promo code for the current user's event.
+func (h *Handler) CreateOrganizerPromoCode(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_create_promo_code")
+	if !ok {
+		return
+	}
+	h.createPromoCode(w, r, logger, "organizer_create_promo_code", &ownerID)
+}
+
+// PatchOrganizerPromoCode updates a promo code of the current user's event.
+func (h *Handler) PatchOrganizerPromoCode(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_patch_promo_code")
+	if !ok {
+		return
+	}
+	h.patchPromoCode(w, r, logger, "organizer_patch_promo_code", &ownerID)
+}
+
+// DeleteOrganizerPromoCode deletes a promo code of the current user's event.
+func (h *Handler) DeleteOrganizerPromoCode(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	ownerID, ok := h.requireOrganizer(logger, w, r, "organizer_delete_promo_code")
+	if !ok {
+		return
+	}
+	h.deletePromoCode(w, r, logger, "organizer_delete_promo_code", &ownerID)
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxReportDetailsLength = 500
+
+// reportRequest represents report request.
+type reportRequest struct {
+	Reason  string `json:"reason"`
+	Details string `json:"details"`
+}
+
+// resolveReportRequest represents resolve report request.
+type resolveReportRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+// adminReportsResponse represents admin reports response.
+type adminReportsResponse struct {
+	Items []models.ReportedTarget `json:"items"`
+	Total int                     `json:"total"`
+}
+
+// validateReport validates the reason category and details of a report.
+func validateReport(req reportRequest) (string, string, error) {
+	reason := strings.ToLower(strings.TrimSpace(req.Reason))
+	if !isReportReason(reason) {
+		return "", "", errors.New("invalid reason")
+	}
+	details := strings.TrimSpace(req.Details)
+	if utf8.RuneCountInString(details) > maxReportDetailsLength {
+		return "", "", errors.New("details too long")
+	}
+	return reason, details, nil
+}
+
+// isReportReason reports whether reason is a known report category.
+func isReportReason(reason string) bool {
+	for _, item := range models.ReportReasons {
+		if item == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// isReportTarget reports whether targetType can be reported.
+func isReportTarget(targetType string) bool {
+	switch targetType {
+	case models.ReportTargetEvent, models.ReportTargetComment, models.ReportTargetUser:
+		return true
+	}
+	return false
+}
+
+// ReportEvent reports an event to moderators.
+func (h *Handler) ReportEvent(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "report_event", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	eventID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "report_event", "status", "invalid_event_id")
+		writeError(w, http.StatusBadRequest, "invalid event id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	event, err := h.repo.GetEventByID(ctx, eventID)
+	if err != nil || event.IsHidden || !h.allowPrivateEvent(ctx, event, userID, accessKeyFromRequest(r)) {
+		logger.Warn("action", "action", "report_event", "status", "not_found", "event_id", eventID)
+		writeError(w, http.StatusNotFound, "event not found")
+		return
+	}
+	h.submitReport(w, r, logger, "report_event", models.ReportTargetEvent, eventID, userID)
+}
+
+// ReportComment reports a comment to moderators.
+func (h *Handler) ReportComment(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "report_comment", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	commentID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "report_comment", "status", "invalid_comment_id")
+		writeError(w, http.StatusBadRequest, "invalid comment id")
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	if _, _, ok := h.loadVisibleComment(ctx, r, logger, w, "report_comment", commentID, userID); !ok {
+		return
+	}
+	h.submitReport(w, r, logger, "report_comment", models.ReportTargetComment, commentID, userID)
+}
+
+// ReportUser reports a user to moderators.
+func (h *Handler) ReportUser(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "report_user", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	targetID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "report_user", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	h.submitReport(w, r, logger, "report_user", models.ReportTargetUser, targetID, userID)
+}
+
+// submitReport decodes the report body and stores the report.
+func (h *Handler) submitReport(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action, targetType string, targetID, userID int64) {
+	var req reportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", action, "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	reason, details, err := validateReport(req)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "invalid_report")
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	autoHidden, err := h.repo.CreateReport(ctx, targetType, targetID, userID, reason, details, h.cfg.ReportHide)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			logger.Warn("action", "action", action, "status", "not_found", "target_id", targetID)
+			writeError(w, http.StatusNotFound, targetType+" not found")
+		case errors.Is(err, repository.ErrSelfReport):
+			logger.Warn("action", "action", action, "status", "self_report", "target_id", targetID)
+			writeError(w, http.StatusBadRequest, "cannot report own content")
+		default:
+			logger.Error("action", "action", action, "status", "db_error", "target_id", targetID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+		}
+		return
+	}
+	logger.Info("action", "action", action, "status", "success", "target_id", targetID, "reason", reason, "auto_hidden", autoHidden)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// ListAdminReports lists reported events, comments and users for moderation.
+func (h *Handler) ListAdminReports(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_list_reports"); !ok {
+		return
+	}
+	limit := parseIntQuery(r, "limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+	offset := parseIntQuery(r, "offset", 0)
+	if offset < 0 {
+		offset = 0
+	}
+	var status *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("status")); raw != "" {
+		switch raw {
+		case models.ReportStatusOpen, models.ReportStatusActioned, models.ReportStatusDismissed:
+			status = &raw
+		default:
+			writeError(w, http.StatusBadRequest, "invalid status")
+			return
+		}
+	}
+	var targetType *string
+	if raw := strings.TrimSpace(r.URL.Query().Get("type")); raw != "" {
+		if !isReportTarget(raw) {
+			writeError(w, http.StatusBadRequest, "invalid type")
+			return
+		}
+		targetType = &raw
+	}
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, total, err := h.repo.ListReportedTargets(ctx, status, targetType, limit, offset)
+	if err != nil {
+		logger.Error("action", "action", "admin_list_reports", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, adminReportsResponse{Items: items, Total: total})
+}
+
+// ResolveAdminReport actions or dismisses open reports about a target.
+func (h *Handler) ResolveAdminReport(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_resolve_report"); !ok {
+		return
+	}
+	targetType := chi.URLParam(r, "type")
+	if !isReportTarget(targetType) {
+		logger.Warn("action", "action", "admin_resolve_report", "status", "invalid_type")
+		writeError(w, http.StatusBadRequest, "invalid type")
+		return
+	}
+	targetID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_resolve_report", "status", "invalid_target_id")
+		writeError(w, http.StatusBadRequest, "invalid target id")
+		return
+	}
+	var req resolveReportRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "admin_resolve_report", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	if req.Status != models.ReportStatusActioned && req.Status != models.ReportStatusDismissed {
+		logger.Warn("action", "action", "admin_resolve_report", "status", "invalid_status")
+		writeError(w, http.StatusBadRequest, "status must be actioned or dismissed")
+		return
+	}
+	moderatorID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	item, err := h.repo.ResolveReportTarget(ctx, targetType, targetID, req.Status, moderatorID, req.Note)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "admin_resolve_report", "status", "not_found", "target_id", targetID)
+			writeError(w, http.StatusNotFound, "report not found")
+			return
+		}
+		logger.Error("action", "action", "admin_resolve_report", "status", "db_error", "target_id", targetID, "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "admin_resolve_report", "status", "success", "target_type", targetType, "target_id", targetID, "resolution", req.Status)
+	writeJSON(w, http.StatusOK, item)
+}
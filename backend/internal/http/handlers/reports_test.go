@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateReport verifies validate report behavior.
+func TestValidateReport(t *testing.T) {
+	reason, details, err := validateReport(reportRequest{Reason: " Spam ", Details: "  ads  "})
+	if err != nil || reason != "spam" || details != "ads" {
+		t.Fatalf("unexpected result: %q %q %v", reason, details, err)
+	}
+	if _, _, err := validateReport(reportRequest{Reason: "boring"}); err == nil {
+		t.Fatalf("expected error for unknown reason")
+	}
+	if _, _, err := validateReport(reportRequest{Reason: "other", Details: strings.Repeat("я", maxReportDetailsLength+1)}); err == nil {
+		t.Fatalf("expected error for long details")
+	}
+}
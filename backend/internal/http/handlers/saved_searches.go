@@ -0,0 +1,312 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const (
+	maxSavedSearchesPerUser      = 20
+	maxSavedSearchNameLength     = 80
+	maxSavedSearchRadiusMeters   = 200000
+	maxSavedSearchWithinDays     = 90
+	maxSavedSearchDailyLimit     = 20
+	defaultSavedSearchDailyLimit = 3
+)
+
+// createSavedSearchRequest represents create saved search request.
+type createSavedSearchRequest struct {
+	Name          string   `json:"name"`
+	Query         string   `json:"query"`
+	Filters       []string `json:"filters"`
+	Lat           *float64 `json:"lat"`
+	Lng           *float64 `json:"lng"`
+	RadiusMeters  *int     `json:"radiusM"`
+	StartsAfter   *string  `json:"startsAfter"`
+	StartsBefore  *string  `json:"startsBefore"`
+	WithinDays    *int     `json:"withinDays"`
+	Price         string   `json:"price"`
+	AlertsEnabled *bool    `json:"alertsEnabled"`
+	DailyLimit    *int     `json:"dailyLimit"`
+}
+
+// updateSavedSearchRequest represents update saved search request.
+type updateSavedSearchRequest struct {
+	Name          *string `json:"name"`
+	AlertsEnabled *bool   `json:"alertsEnabled"`
+	DailyLimit    *int    `json:"dailyLimit"`
+}
+
+// normalizeSavedSearchName trims a saved search name and checks its length.
+func normalizeSavedSearchName(raw string) (string, bool) {
+	name := strings.Join(strings.Fields(raw), " ")
+	length := utf8.RuneCountInString(name)
+	return name, length > 0 && length <= maxSavedSearchNameLength
+}
+
+// validateDailyLimit checks the number of alerts a saved search may send per day.
+func validateDailyLimit(limit int) bool {
+	return limit > 0 && limit <= maxSavedSearchDailyLimit
+}
+
+// validateSavedSearch converts a create request into a saved search. At least
+// one criterion besides the name is required.
+func validateSavedSearch(req createSavedSearchRequest) (models.SavedSearch, error) {
+	name, ok := normalizeSavedSearchName(req.Name)
+	if !ok {
+		return models.SavedSearch{}, errors.New("invalid name")
+	}
+	search := models.SavedSearch{
+		Name:          name,
+		Price:         strings.ToLower(strings.TrimSpace(req.Price)),
+		AlertsEnabled: true,
+		DailyLimit:    defaultSavedSearchDailyLimit,
+	}
+	criteria := 0
+	if strings.TrimSpace(req.Query) != "" {
+		query, ok := normalizeSearchQuery(req.Query)
+		if !ok {
+			return models.SavedSearch{}, errors.New("invalid query")
+		}
+		search.Query = query
+		criteria++
+	}
+	filters, err := normalizeEventFilters(req.Filters, maxEventFilters)
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	search.Filters = filters
+	criteria += len(filters)
+	if req.Lat != nil || req.Lng != nil || req.RadiusMeters != nil {
+		if req.Lat == nil || req.Lng == nil || req.RadiusMeters == nil {
+			return models.SavedSearch{}, errors.New("lat, lng and radiusM go together")
+		}
+		if *req.Lat < -90 || *req.Lat > 90 || *req.Lng < -180 || *req.Lng > 180 {
+			return models.SavedSearch{}, errors.New("invalid coordinates")
+		}
+		if *req.RadiusMeters <= 0 || *req.RadiusMeters > maxSavedSearchRadiusMeters {
+			return models.SavedSearch{}, errors.New("invalid radius")
+		}
+		search.Lat, search.Lng, search.RadiusMeters = req.Lat, req.Lng, req.RadiusMeters
+		criteria++
+	}
+	if req.StartsAfter != nil && strings.TrimSpace(*req.StartsAfter) != "" {
+		startsAfter, err := parseEventTime(*req.StartsAfter)
+		if err != nil {
+			return models.SavedSearch{}, errors.New("invalid startsAfter")
+		}
+		search.StartsAfter = &startsAfter
+		criteria++
+	}
+	if req.StartsBefore != nil && strings.TrimSpace(*req.StartsBefore) != "" {
+		startsBefore, err := parseEventTime(*req.StartsBefore)
+		if err != nil {
+			return models.SavedSearch{}, errors.New("invalid startsBefore")
+		}
+		if search.StartsAfter != nil && !startsBefore.After(*search.StartsAfter) {
+			return models.SavedSearch{}, errors.New("startsBefore must be after startsAfter")
+		}
+		search.StartsBefore = &startsBefore
+		criteria++
+	}
+	if req.WithinDays != nil {
+		if *req.WithinDays <= 0 || *req.WithinDays > maxSavedSearchWithinDays {
+			return models.SavedSearch{}, errors.New("invalid withinDays")
+		}
+		search.WithinDays = req.WithinDays
+		criteria++
+	}
+	switch search.Price {
+	case "":
+		search.Price = models.SavedSearchPriceAny
+	case models.SavedSearchPriceAny:
+	case models.SavedSearchPriceFree, models.SavedSearchPricePaid:
+		criteria++
+	default:
+		return models.SavedSearch{}, errors.New("invalid price")
+	}
+	if criteria == 0 {
+		return models.SavedSearch{}, errors.New("search has no criteria")
+	}
+	if req.AlertsEnabled != nil {
+		search.AlertsEnabled = *req.AlertsEnabled
+	}
+	if req.DailyLimit != nil {
+		if !validateDailyLimit(*req.DailyLimit) {
+			return models.SavedSearch{}, errors.New("invalid dailyLimit")
+		}
+		search.DailyLimit = *req.DailyLimit
+	}
+	return search, nil
+}
+
+// alertSavedSearches enqueues alerts for saved searches matching a newly
+// published public event. Failures are logged.
+func (h *Handler) alertSavedSearches(ctx context.Context, logger *slog.Logger, action string, eventID int64, payload map[string]interface{}) {
+	count, err := h.repo.EnqueueSavedSearchAlerts(ctx, eventID, time.Now(), payload)
+	if err != nil {
+		logger.Warn("action", "action", action, "status", "saved_search_alerts_failed", "event_id", eventID, "error", err)
+		return
+	}
+	logger.Info("action", "action", action, "status", "saved_search_alerts_enqueued", "event_id", eventID, "count", count)
+}
+
+// ListSavedSearches lists saved searches of the current user.
+func (h *Handler) ListSavedSearches(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "list_saved_searches", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	items, err := h.repo.ListSavedSearches(ctx, userID)
+	if err != nil {
+		logger.Error("action", "action", "list_saved_searches", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// CreateSavedSearch saves a search of the current user.
+func (h *Handler) CreateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "create_saved_search", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	var req createSavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "create_saved_search", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	search, err := validateSavedSearch(req)
+	if err != nil {
+		logger.Warn("action", "action", "create_saved_search", "status", "invalid_search", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	created, err := h.repo.CreateSavedSearch(ctx, userID, search, maxSavedSearchesPerUser)
+	if err != nil {
+		if errors.Is(err, repository.ErrTooManySavedSearches) {
+			logger.Warn("action", "action", "create_saved_search", "status", "limit_reached")
+			writeError(w, http.StatusConflict, "too many saved searches")
+			return
+		}
+		logger.Error("action", "action", "create_saved_search", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "create_saved_search", "status", "success", "search_id", created.ID)
+	writeJSON(w, http.StatusCreated, created)
+}
+
+// UpdateSavedSearch renames a saved search or changes its alerts.
+func (h *Handler) UpdateSavedSearch(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "update_saved_search", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	searchID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "update_saved_search", "status", "invalid_search_id")
+		writeError(w, http.StatusBadRequest, "invalid search id")
+		return
+	}
+	var req updateSavedSearchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "update_saved_search", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	update := repository.SavedSearchUpdate{AlertsEnabled: req.AlertsEnabled}
+	if req.Name != nil {
+		name, ok := normalizeSavedSearchName(*req.Name)
+		if !ok {
+			logger.Warn("action", "action", "update_saved_search", "status", "invalid_name")
+			writeError(w, http.StatusBadRequest, "invalid name")
+			return
+		}
+		update.Name = &name
+	}
+	if req.DailyLimit != nil {
+		if !validateDailyLimit(*req.DailyLimit) {
+			logger.Warn("action", "action", "update_saved_search", "status", "invalid_daily_limit")
+			writeError(w, http.StatusBadRequest, "invalid dailyLimit")
+			return
+		}
+		update.DailyLimit = req.DailyLimit
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	search, err := h.repo.UpdateSavedSearch(ctx, userID, searchID, update)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "update_saved_search", "status", "not_found", "search_id", searchID)
+			writeError(w, http.StatusNotFound, "saved search not found")
+			return
+		}
+		logger.Error("action", "action", "update_saved_search", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "update_saved_search", "status", "success", "search_id", searchID)
+	writeJSON(w, http.StatusOK, search)
+}
+
+// DeleteSavedSearch removes a saved search of the current user.
+func (h *Handler) DeleteSavedSearch(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	userID, ok := middleware.UserIDFromContext(r.Context())
+	if !ok {
+		logger.Warn("action", "action", "delete_saved_search", "status", "unauthorized")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	searchID, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "delete_saved_search", "status", "invalid_search_id")
+		writeError(w, http.StatusBadRequest, "invalid search id")
+		return
+	}
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	removed, err := h.repo.DeleteSavedSearch(ctx, userID, searchID)
+	if err != nil {
+		logger.Error("action", "action", "delete_saved_search", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	if !removed {
+		logger.Warn("action", "action", "delete_saved_search", "status", "not_found", "search_id", searchID)
+		writeError(w, http.StatusNotFound, "saved search not found")
+		return
+	}
+	logger.Info("action", "action", "delete_saved_search", "status", "success", "search_id", searchID)
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
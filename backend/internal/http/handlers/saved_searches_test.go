@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"testing"
+
+	"gigme/backend/internal/models"
+)
+
+// TestValidateSavedSearch verifies validate saved search behavior.
+func TestValidateSavedSearch(t *testing.T) {
+	lat, lng, radius, days := 55.75, 37.61, 5000, 7
+	search, err := validateSavedSearch(createSavedSearchRequest{
+		Name:         "  Техно   рядом ",
+		Query:        " techno  ",
+		Filters:      []string{"party", "Party"},
+		Lat:          &lat,
+		Lng:          &lng,
+		RadiusMeters: &radius,
+		WithinDays:   &days,
+		Price:        "FREE",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if search.Name != "Техно рядом" || search.Query != "techno" {
+		t.Fatalf("unexpected name or query: %q %q", search.Name, search.Query)
+	}
+	if len(search.Filters) != 1 || search.Filters[0] != "party" {
+		t.Fatalf("unexpected filters: %v", search.Filters)
+	}
+	if search.Price != models.SavedSearchPriceFree || !search.AlertsEnabled || search.DailyLimit != defaultSavedSearchDailyLimit {
+		t.Fatalf("unexpected defaults: %+v", search)
+	}
+}
+
+// TestValidateSavedSearchRejectsInvalid verifies validate saved search rejects invalid behavior.
+func TestValidateSavedSearchRejectsInvalid(t *testing.T) {
+	lat, radius, zero, tooMany := 55.75, 5000, 0, maxSavedSearchDailyLimit+1
+	after, before := "2026-06-10T00:00:00Z", "2026-06-01T00:00:00Z"
+	cases := []createSavedSearchRequest{
+		{Name: "", Query: "techno"},
+		{Name: "empty"},
+		{Name: "price only any", Price: "any"},
+		{Name: "bad price", Price: "cheap"},
+		{Name: "bad filter", Filters: []string{"unknown"}},
+		{Name: "no lng", Lat: &lat, RadiusMeters: &radius},
+		{Name: "bad window", StartsAfter: &after, StartsBefore: &before},
+		{Name: "bad days", WithinDays: &zero},
+		{Name: "bad limit", Query: "techno", DailyLimit: &tooMany},
+	}
+	for _, req := range cases {
+		if _, err := validateSavedSearch(req); err == nil {
+			t.Fatalf("%q: expected error", req.Name)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,33 +11,46 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
 	"gigme/backend/internal/integrations"
 	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
 
 	"github.com/jackc/pgx/v5"
 )
 
 // telegramUpdate represents telegram update.
 type telegramUpdate struct {
-	Message       *telegramMessage       `json:"message"`
-	CallbackQuery *telegramCallbackQuery `json:"callback_query"`
+	UpdateID      int64                      `json:"update_id"`
+	Message       *telegramMessage           `json:"message"`
+	CallbackQuery *telegramCallbackQuery     `json:"callback_query"`
+	MyChatMember  *telegramChatMemberUpdated `json:"my_chat_member"`
 }
 
 // telegramMessage represents telegram message.
 type telegramMessage struct {
-	MessageID int          `json:"message_id"`
-	Text      string       `json:"text"`
-	Caption   string       `json:"caption"`
-	Chat      telegramChat `json:"chat"`
-	From      telegramFrom `json:"from"`
+	MessageID int              `json:"message_id"`
+	Text      string           `json:"text"`
+	Caption   string           `json:"caption"`
+	Chat      telegramChat     `json:"chat"`
+	From      telegramFrom     `json:"from"`
+	Contact   *telegramContact `json:"contact"`
+}
+
+// telegramContact represents a shared contact; user_id is set when the contact
+// is a Telegram user.
+type telegramContact struct {
+	PhoneNumber string `json:"phone_number"`
+	UserID      int64  `json:"user_id"`
 }
 
 // telegramChat represents telegram chat.
 type telegramChat struct {
-	ID int64 `json:"id"`
+	ID   int64  `json:"id"`
+	Type string `json:"type"`
 }
 
 // telegramFrom represents telegram from.
@@ -55,11 +69,29 @@ type telegramCallbackQuery struct {
 	Data    string           `json:"data"`
 }
 
+// telegramChatMemberUpdated represents a change of the bot's membership in a
+// chat; in private chats it reports the user blocking or unblocking the bot.
+type telegramChatMemberUpdated struct {
+	Chat          telegramChat `json:"chat"`
+	From          telegramFrom `json:"from"`
+	NewChatMember struct {
+		Status string `json:"status"`
+	} `json:"new_chat_member"`
+}
+
+// TelegramAllowedUpdates lists the update types the webhook handles; they are
+// registered with setWebhook.
+var TelegramAllowedUpdates = []string{"message", "callback_query", "my_chat_member"}
+
+// telegramWebhookSecretHeader carries the secret_token set with setWebhook.
+const telegramWebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
 var startEventPayloadRe = regexp.MustCompile(`(?i)event_(\d+)(?:_([a-z0-9_-]+))?`)
 var startEventIDRe = regexp.MustCompile(`\d+`)
 var adminReplyPayloadRe = regexp.MustCompile(`(?i)(?:reply|chat)_(\d+)`)
 var adminReplyCallbackDataRe = regexp.MustCompile(`(?i)^reply:(\d+)$`)
 var adminReplyHintCallbackDataRe = regexp.MustCompile(`(?i)^reply_hint:(\d+)$`)
+var muteCallbackDataRe = regexp.MustCompile(`^mute:([a-z_]+)$`)
 
 // parseStartPayload parses start payload.
 func parseStartPayload(payload string) (int64, string) {
@@ -91,19 +123,82 @@ func parseStartPayload(payload string) (int64, string) {
 // TelegramWebhook handles telegram webhook.
 func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
+	if !validTelegramWebhookSecret(h.cfg.Webhook.Secret, r.Header.Get(telegramWebhookSecretHeader)) {
+		logger.Warn("action", "action", "telegram_webhook", "status", "invalid_secret")
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
 	var update telegramUpdate
 	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
 		logger.Warn("action", "action", "telegram_webhook", "status", "invalid_json")
 		writeError(w, http.StatusBadRequest, "invalid json")
 		return
 	}
+	if update.UpdateID > 0 {
+		ctx, cancel := h.withTimeout(r.Context())
+		isNew, err := h.repo.ClaimTelegramUpdate(ctx, update.UpdateID)
+		cancel()
+		if err != nil {
+			// Telegram redelivers the update after an error response.
+			logger.Error("action", "action", "telegram_webhook", "status", "db_error", "update_id", update.UpdateID, "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+			return
+		}
+		if !isNew {
+			logger.Info("action", "action", "telegram_webhook", "status", "duplicate", "update_id", update.UpdateID)
+			writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+			return
+		}
+		// A panic or a timeout leaves Telegram without an answer, so it
+		// redelivers the update; the claim is released for that retry.
+		completed := false
+		defer func() {
+			if !completed {
+				h.releaseTelegramUpdate(logger, update.UpdateID)
+			}
+		}()
+		h.processTelegramUpdate(r.Context(), logger, &update)
+		if err := r.Context().Err(); err != nil {
+			logger.Warn("action", "action", "telegram_webhook", "status", "interrupted", "update_id", update.UpdateID, "error", err)
+			return
+		}
+		completed = true
+	} else {
+		h.processTelegramUpdate(r.Context(), logger, &update)
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+}
+
+// releaseTelegramUpdate forgets a claimed update id so a redelivery of the
+// update is processed.
+func (h *Handler) releaseTelegramUpdate(logger *slog.Logger, updateID int64) {
+	ctx, cancel := h.withTimeout(context.Background())
+	defer cancel()
+	if err := h.repo.ReleaseTelegramUpdate(ctx, updateID); err != nil {
+		logger.Error("action", "action", "telegram_webhook", "status", "release_failed", "update_id", updateID, "error", err)
+	}
+}
+
+// processTelegramUpdate handles a webhook update. Failures are logged; the
+// update is acknowledged either way.
+func (h *Handler) processTelegramUpdate(ctx context.Context, logger *slog.Logger, update *telegramUpdate) {
 	if update.CallbackQuery != nil {
-		h.handleTelegramCallbackQuery(logger, update.CallbackQuery)
-		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+		h.handleTelegramCallbackQuery(ctx, logger, update.CallbackQuery)
+		return
+	}
+	if update.MyChatMember != nil {
+		h.handleMyChatMember(ctx, logger, update.MyChatMember)
 		return
 	}
 	if update.Message == nil || update.Message.Chat.ID == 0 {
-		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+		return
+	}
+	if update.Message.From.ID > 0 && update.Message.Chat.ID == update.Message.From.ID {
+		// Writing to the bot means the user can receive its messages again.
+		h.setTelegramReachable(ctx, logger, update.Message.From.ID, true)
+	}
+	if update.Message.Contact != nil {
+		h.handleTelegramContact(ctx, logger, update.Message)
 		return
 	}
 
@@ -112,14 +207,13 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 	isAdmin := h.isAdminTelegramID(update.Message.From.ID)
 
 	if isAdmin {
-		if h.handleAdminTelegramMessage(r.Context(), logger, update.Message, trimmedText) {
-			writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+		if h.handleAdminTelegramMessage(ctx, logger, update.Message, trimmedText) {
 			return
 		}
 	}
 
 	if !isAdmin {
-		h.storeIncomingBotMessage(r.Context(), logger, update.Message, trimmedText)
+		h.storeIncomingBotMessage(ctx, logger, update.Message, trimmedText)
 		h.notifyAdminsWithMarkup(
 			logger,
 			buildAdminBotMessageNotificationText(*update.Message, h.cfg.TelegramUser),
@@ -129,7 +223,6 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 
 	fields := strings.Fields(trimmedText)
 	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/start") {
-		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 		return
 	}
 
@@ -138,17 +231,17 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 	eventID, accessKey := parseStartPayload(startPayload)
 
 	if eventID > 0 {
-		ctx, cancel := h.withTimeout(r.Context())
+		lookupCtx, cancel := h.withTimeout(ctx)
 		defer cancel()
-		event, err := h.repo.GetEventByID(ctx, eventID)
+		event, err := h.repo.GetEventByID(lookupCtx, eventID)
 		if err == nil && !event.IsHidden {
-			if event.IsPrivate && (accessKey == "" || accessKey != event.AccessKey) {
+			if event.IsDraft() || (event.IsPrivate && (accessKey == "" || accessKey != event.AccessKey)) {
 				event = models.Event{}
 			}
 		}
 		if event.ID > 0 {
 			text := buildEventCardText(event)
-			mediaURL := resolveEventMediaURL(ctx, h, eventID, accessKey)
+			mediaURL := resolveEventMediaURL(lookupCtx, h, eventID, accessKey)
 			var markup *integrations.ReplyMarkup
 			if webAppURL != "" {
 				markup = &integrations.ReplyMarkup{
@@ -162,7 +255,6 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 			}
 			if mediaURL != "" {
 				if err := h.telegram.SendPhotoWithMarkup(update.Message.Chat.ID, mediaURL, text, markup); err == nil {
-					writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 					return
 				} else {
 					logger.Warn("action", "action", "telegram_webhook", "status", "send_photo_failed", "error", err)
@@ -171,7 +263,6 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 			if err := h.telegram.SendMessageWithMarkup(update.Message.Chat.ID, text, markup); err != nil {
 				logger.Warn("action", "action", "telegram_webhook", "status", "send_failed", "error", err)
 			}
-			writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 			return
 		}
 		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
@@ -181,7 +272,6 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 
 	if webAppURL == "" {
 		logger.Warn("action", "action", "telegram_webhook", "status", "missing_base_url")
-		writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
 		return
 	}
 
@@ -196,10 +286,54 @@ func (h *Handler) TelegramWebhook(w http.ResponseWriter, r *http.Request) {
 
 	if err := h.telegram.SendMessageWithMarkup(update.Message.Chat.ID, "Нажмите кнопку, чтобы открыть приложение", markup); err != nil {
 		logger.Warn("action", "action", "telegram_webhook", "status", "send_failed", "error", err)
+	}
+}
 
+// validTelegramWebhookSecret reports whether the secret header of an update
+// matches the configured secret. Without a configured secret every update is
+// rejected.
+func validTelegramWebhookSecret(expected, got string) bool {
+	if expected == "" {
+		return false
 	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(got)) == 1
+}
 
-	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
+// handleTelegramContact stores the phone of a user who shared their own contact
+// with the bot. Contacts of other people are ignored.
+func (h *Handler) handleTelegramContact(ctx context.Context, logger *slog.Logger, message *telegramMessage) {
+	if h == nil || h.telegram == nil || message == nil || message.Contact == nil {
+		return
+	}
+	chatID := message.Chat.ID
+	contact := message.Contact
+	if message.From.ID <= 0 || contact.UserID != message.From.ID || chatID != message.From.ID || strings.TrimSpace(contact.PhoneNumber) == "" {
+		logger.Warn("action", "action", "telegram_contact", "status", "not_own_contact")
+		if err := h.telegram.SendMessage(chatID, "Отправьте свой номер кнопкой «Поделиться номером»."); err != nil {
+			logger.Warn("action", "action", "telegram_contact", "status", "send_failed", "error", err)
+		}
+		return
+	}
+
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	user, err := h.repo.GetUserByTelegramID(ctx, message.From.ID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("action", "action", "telegram_contact", "status", "db_error", "error", err)
+		}
+		return
+	}
+	phoneKey := repository.PhoneContactHash(h.cfg.HMACSecret, phoneNumberHash(contact.PhoneNumber))
+	if err := h.repo.SetUserPhoneHash(ctx, user.ID, phoneKey); err != nil {
+		logger.Error("action", "action", "telegram_contact", "status", "db_error", "user_id", user.ID, "error", err)
+		return
+	}
+	logger.Info("action", "action", "telegram_contact", "status", "phone_verified", "user_id", user.ID)
+	markup := &integrations.ReplyMarkup{RemoveKeyboard: true}
+	if err := h.telegram.SendMessageWithMarkup(chatID, "Номер подтверждён. Друзья из ваших контактов увидят вас в SPACE.", markup); err != nil {
+		logger.Warn("action", "action", "telegram_contact", "status", "send_failed", "error", err)
+	}
 }
 
 // isAdminTelegramID reports whether admin telegram i d condition is met.
@@ -212,11 +346,16 @@ func (h *Handler) isAdminTelegramID(telegramID int64) bool {
 }
 
 // handleTelegramCallbackQuery handles telegram callback query.
-func (h *Handler) handleTelegramCallbackQuery(logger *slog.Logger, query *telegramCallbackQuery) {
+func (h *Handler) handleTelegramCallbackQuery(ctx context.Context, logger *slog.Logger, query *telegramCallbackQuery) {
 	if h == nil || h.telegram == nil || query == nil {
 		return
 	}
 
+	if category, ok := parseMuteCallbackData(query.Data); ok {
+		h.handleMuteCallback(ctx, logger, query, category)
+		return
+	}
+
 	_ = h.telegram.AnswerCallbackQuery(query.ID, "")
 
 	adminTelegramID := query.From.ID
@@ -255,6 +394,82 @@ func (h *Handler) handleTelegramCallbackQuery(logger *slog.Logger, query *telegr
 
 }
 
+// handleMyChatMember tracks users blocking and unblocking the bot.
+func (h *Handler) handleMyChatMember(ctx context.Context, logger *slog.Logger, update *telegramChatMemberUpdated) {
+	reachable, ok := chatMemberReachability(update)
+	if !ok {
+		return
+	}
+	h.setTelegramReachable(ctx, logger, update.From.ID, reachable)
+}
+
+// chatMemberReachability reports whether a private chat member update makes
+// the user reachable (bot started or unblocked) or unreachable (bot blocked).
+func chatMemberReachability(update *telegramChatMemberUpdated) (bool, bool) {
+	if update == nil || update.Chat.Type != "private" || update.From.ID <= 0 {
+		return false, false
+	}
+	switch update.NewChatMember.Status {
+	case "kicked":
+		return false, true
+	case "member":
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// setTelegramReachable records whether the bot can message the user with the telegram id.
+func (h *Handler) setTelegramReachable(ctx context.Context, logger *slog.Logger, telegramID int64, reachable bool) {
+	if h == nil || h.repo == nil {
+		return
+	}
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	var err error
+	if reachable {
+		err = h.repo.ClearTelegramUnreachable(ctx, telegramID)
+	} else {
+		err = h.repo.MarkTelegramChatUnreachable(ctx, telegramID, "bot blocked by user")
+	}
+	if err != nil {
+		logger.Error("action", "action", "telegram_webhook_reachability", "status", "db_error", "telegram_id", telegramID, "error", err)
+		return
+	}
+	if !reachable {
+		logger.Info("action", "action", "telegram_webhook_reachability", "status", "unreachable", "telegram_id", telegramID)
+	}
+}
+
+// handleMuteCallback mutes the notification category picked with the mute
+// button of a notification and confirms it in the callback answer.
+func (h *Handler) handleMuteCallback(ctx context.Context, logger *slog.Logger, query *telegramCallbackQuery, category string) {
+	ctx, cancel := h.withTimeout(ctx)
+	defer cancel()
+	found, err := h.repo.MuteNotificationCategory(ctx, query.From.ID, category)
+	if err != nil {
+		logger.Error("action", "action", "telegram_webhook_mute", "status", "db_error", "telegram_id", query.From.ID, "error", err)
+		_ = h.telegram.AnswerCallbackQuery(query.ID, "Не удалось отключить уведомления, попробуйте позже")
+		return
+	}
+	if !found {
+		logger.Warn("action", "action", "telegram_webhook_mute", "status", "user_not_found", "telegram_id", query.From.ID)
+		_ = h.telegram.AnswerCallbackQuery(query.ID, "")
+		return
+	}
+	logger.Info("action", "action", "telegram_webhook_mute", "status", "success", "telegram_id", query.From.ID, "category", category)
+	_ = h.telegram.AnswerCallbackQuery(query.ID, "Такие уведомления отключены. Включить их снова можно в настройках.")
+}
+
+// parseMuteCallbackData parses the notification category of a mute button.
+func parseMuteCallbackData(data string) (string, bool) {
+	match := muteCallbackDataRe.FindStringSubmatch(strings.TrimSpace(data))
+	if len(match) != 2 || !slices.Contains(models.NotificationCategories, match[1]) {
+		return "", false
+	}
+	return match[1], true
+}
+
 // handleAdminTelegramMessage handles admin telegram message.
 func (h *Handler) handleAdminTelegramMessage(ctx context.Context, logger *slog.Logger, message *telegramMessage, text string) bool {
 	if h == nil || h.telegram == nil || message == nil {
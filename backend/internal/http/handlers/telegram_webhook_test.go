@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+
+	"gigme/backend/internal/config"
 )
 
 // TestNormalizeWebAppBaseURL verifies that host-only URLs get the `/space_app` path.
@@ -85,6 +90,37 @@ func TestParseAdminReplyCallbackData(t *testing.T) {
 	}
 }
 
+// TestParseMuteCallbackData verifies mute callback data accepts known categories only.
+func TestParseMuteCallbackData(t *testing.T) {
+	category, ok := parseMuteCallbackData("mute:comments")
+	if !ok || category != "comments" {
+		t.Fatalf("expected comments category, got %q %v", category, ok)
+	}
+	if _, ok := parseMuteCallbackData("mute:everything"); ok {
+		t.Fatalf("expected unknown category to be rejected")
+	}
+	if _, ok := parseMuteCallbackData("reply:111"); ok {
+		t.Fatalf("expected reply callback to be ignored")
+	}
+}
+
+// TestChatMemberReachability verifies private chat member updates track blocked bots.
+func TestChatMemberReachability(t *testing.T) {
+	update := &telegramChatMemberUpdated{Chat: telegramChat{ID: 42, Type: "private"}, From: telegramFrom{ID: 42}}
+	update.NewChatMember.Status = "kicked"
+	if reachable, ok := chatMemberReachability(update); !ok || reachable {
+		t.Fatalf("expected kicked to be unreachable, got %v %v", reachable, ok)
+	}
+	update.NewChatMember.Status = "member"
+	if reachable, ok := chatMemberReachability(update); !ok || !reachable {
+		t.Fatalf("expected member to be reachable, got %v %v", reachable, ok)
+	}
+	update.Chat.Type = "group"
+	if _, ok := chatMemberReachability(update); ok {
+		t.Fatalf("expected group updates to be ignored")
+	}
+}
+
 // TestParseAdminReplyPayload verifies payload decoding for reply deep links.
 func TestParseAdminReplyPayload(t *testing.T) {
 	chatID, ok := parseAdminReplyPayload("reply_998877")
@@ -103,3 +139,40 @@ func TestParseStartPayloadSkipsReplyPayload(t *testing.T) {
 		t.Fatalf("expected reply payload to be skipped, got eventID=%d key=%q", eventID, key)
 	}
 }
+
+// TestValidTelegramWebhookSecret verifies the secret header check.
+func TestValidTelegramWebhookSecret(t *testing.T) {
+	if validTelegramWebhookSecret("", "") || validTelegramWebhookSecret("", "anything") {
+		t.Fatalf("expected updates to be rejected without a configured secret")
+	}
+	if !validTelegramWebhookSecret("s3cret_token", "s3cret_token") {
+		t.Fatalf("expected matching secret to be accepted")
+	}
+	if validTelegramWebhookSecret("s3cret_token", "") || validTelegramWebhookSecret("s3cret_token", "s3cret") {
+		t.Fatalf("expected missing or wrong secret to be rejected")
+	}
+}
+
+// TestTelegramWebhookRejectsForgedUpdates verifies updates without the secret are rejected before processing.
+func TestTelegramWebhookRejectsForgedUpdates(t *testing.T) {
+	h := &Handler{cfg: &config.Config{Webhook: config.TelegramWebhookConfig{Secret: "s3cret_token"}}}
+	body := `{"update_id": 1, "message": {"message_id": 1, "text": "/reply 42 hi", "chat": {"id": 1}, "from": {"id": 1}}}`
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(body))
+	req.Header.Set(telegramWebhookSecretHeader, "forged")
+	rec := httptest.NewRecorder()
+	h.TelegramWebhook(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rec.Code)
+	}
+}
+
+// TestTelegramWebhookRequiresSecret verifies updates are rejected while no secret is configured.
+func TestTelegramWebhookRequiresSecret(t *testing.T) {
+	h := &Handler{cfg: &config.Config{}}
+	req := httptest.NewRequest(http.MethodPost, "/telegram/webhook", strings.NewReader(`{"update_id": 1}`))
+	rec := httptest.NewRecorder()
+	h.TelegramWebhook(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a configured secret, got %d", rec.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -556,6 +557,11 @@ func (h *Handler) ListAdminOrders(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_list_orders"); !ok {
 		return
 	}
+	h.listOrders(w, r, logger, "admin_list_orders", nil)
+}
+
+// listOrders lists orders, limited to events of ownerID when it is set.
+func (h *Handler) listOrders(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	limit := parseIntQuery(r, "limit", 50)
 	offset := parseIntQuery(r, "offset", 0)
 	status := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("status")))
@@ -591,9 +597,9 @@ func (h *Handler) ListAdminOrders(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	items, total, err := h.repo.ListOrders(ctx, eventID, status, from, to, limit, offset)
+	items, total, err := h.repo.ListOrders(ctx, eventID, status, from, to, limit, offset, ownerID)
 	if err != nil {
-		logger.Error("admin_list_orders", "status", "db_error", "error", err)
+		logger.Error(action, "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
@@ -601,11 +607,9 @@ func (h *Handler) ListAdminOrders(w http.ResponseWriter, r *http.Request) {
 }
 
 // GetAdminOrder returns admin order.
+// Cashiers and co-hosts of the order's event may use it as well.
 func (h *Handler) GetAdminOrder(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_get_order"); !ok {
-		return
-	}
 	orderID := resolveOrderIDParam(r)
 	if orderID == "" {
 		writeError(w, http.StatusBadRequest, "invalid order id")
@@ -614,6 +618,9 @@ func (h *Handler) GetAdminOrder(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
+	if _, ok := h.requireOrderPermission(ctx, logger, w, "admin_get_order", orderID, eventPermOrders); !ok {
+		return
+	}
 	detail, err := h.repo.GetOrderDetail(ctx, orderID, true)
 	if err != nil {
 		h.handleTicketingError(logger, w, "admin_get_order", err)
@@ -628,16 +635,9 @@ func (h *Handler) GetAdminOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // ConfirmOrder handles confirm order.
+// Cashiers and co-hosts of the order's event may use it as well.
 func (h *Handler) ConfirmOrder(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_confirm_order"); !ok {
-		return
-	}
-	adminID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok {
-		writeError(w, http.StatusUnauthorized, "unauthorized")
-		return
-	}
 	orderID := resolveOrderIDParam(r)
 	if orderID == "" {
 		writeError(w, http.StatusBadRequest, "invalid order id")
@@ -646,6 +646,10 @@ func (h *Handler) ConfirmOrder(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
+	adminID, ok := h.requireOrderPermission(ctx, logger, w, "admin_confirm_order", orderID, eventPermOrders)
+	if !ok {
+		return
+	}
 	detail, telegramID, confirmedNow, err := h.repo.ConfirmOrder(ctx, orderID, adminID, h.cfg.HMACSecret)
 	if err != nil {
 		h.handleTicketingError(logger, w, "admin_confirm_order", err)
@@ -721,16 +725,9 @@ func (h *Handler) DeleteAdminOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // CancelOrder handles cancel order.
+// Cashiers and co-hosts of the order's event may use it as well.
 func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_cancel_order"); !ok {
-		return
-	}
-	adminID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok {
-		writeError(w, http.StatusUnauthorized, "unauthorized")
-		return
-	}
 	orderID := resolveOrderIDParam(r)
 	if orderID == "" {
 		writeError(w, http.StatusBadRequest, "invalid order id")
@@ -742,6 +739,10 @@ func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
+	adminID, ok := h.requireOrderPermission(ctx, logger, w, "admin_cancel_order", orderID, eventPermOrders)
+	if !ok {
+		return
+	}
 	detail, err := h.repo.CancelOrder(ctx, orderID, adminID, req.Reason)
 	if err != nil {
 		h.handleTicketingError(logger, w, "admin_cancel_order", err)
@@ -753,16 +754,9 @@ func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
 }
 
 // RedeemTicket handles redeem ticket.
+// Scanners and co-hosts of the ticket's event may use it as well.
 func (h *Handler) RedeemTicket(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_redeem_ticket"); !ok {
-		return
-	}
-	adminID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok {
-		writeError(w, http.StatusUnauthorized, "unauthorized")
-		return
-	}
 	ticketID := strings.TrimSpace(chi.URLParam(r, "id"))
 	if ticketID == "" {
 		writeError(w, http.StatusBadRequest, "invalid ticket id")
@@ -774,6 +768,10 @@ func (h *Handler) RedeemTicket(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
+	adminID, ok := h.requireTicketPermission(ctx, logger, w, "admin_redeem_ticket", ticketID, eventPermRedeem)
+	if !ok {
+		return
+	}
 	result, err := h.repo.RedeemTicket(ctx, ticketID, adminID, strings.TrimSpace(req.QRPayload), h.cfg.HMACSecret)
 	if err != nil {
 		h.handleTicketingError(logger, w, "admin_redeem_ticket", err)
@@ -783,17 +781,9 @@ func (h *Handler) RedeemTicket(w http.ResponseWriter, r *http.Request) {
 }
 
 // AdminRedeemTicket handles admin redeem ticket.
+// Scanners and co-hosts of the ticket's event may use it as well.
 func (h *Handler) AdminRedeemTicket(w http.ResponseWriter, r *http.Request) {
 	logger := h.loggerForRequest(r)
-	if _, ok := h.requireAdmin(logger, w, r, "admin_redeem_ticket"); !ok {
-		return
-	}
-	adminID, ok := middleware.UserIDFromContext(r.Context())
-	if !ok {
-		writeError(w, http.StatusUnauthorized, "unauthorized")
-		return
-	}
-
 	var req adminRedeemTicketRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		writeError(w, http.StatusBadRequest, "invalid json")
@@ -817,6 +807,10 @@ func (h *Handler) AdminRedeemTicket(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
+	adminID, ok := h.requireTicketPermission(ctx, logger, w, "admin_redeem_ticket", ticketID, eventPermRedeem)
+	if !ok {
+		return
+	}
 	result, err := h.repo.RedeemTicket(ctx, ticketID, adminID, qrPayload, h.cfg.HMACSecret)
 	if err != nil {
 		h.handleTicketingError(logger, w, "admin_redeem_ticket", err)
@@ -831,7 +825,11 @@ func (h *Handler) AdminStats(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_stats"); !ok {
 		return
 	}
+	h.ticketStats(w, r, logger, "admin_stats", nil)
+}
 
+// ticketStats writes ticket stats, limited to events of ownerID when it is set.
+func (h *Handler) ticketStats(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	var eventID *int64
 	if raw := strings.TrimSpace(r.URL.Query().Get("event_id")); raw != "" {
 		parsed, err := strconv.ParseInt(raw, 10, 64)
@@ -844,9 +842,9 @@ func (h *Handler) AdminStats(w http.ResponseWriter, r *http.Request) {
 
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	stats, err := h.repo.GetTicketStats(ctx, eventID)
+	stats, err := h.repo.GetTicketStats(ctx, eventID, ownerID)
 	if err != nil {
-		logger.Error("admin_stats", "status", "db_error", "error", err)
+		logger.Error(action, "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
@@ -914,6 +912,11 @@ func (h *Handler) ListAdminTicketProducts(w http.ResponseWriter, r *http.Request
 	if _, ok := h.requireAdmin(logger, w, r, "admin_list_ticket_products"); !ok {
 		return
 	}
+	h.listTicketProducts(w, r, logger, "admin_list_ticket_products", nil)
+}
+
+// listTicketProducts lists ticket products, limited to events of ownerID when it is set.
+func (h *Handler) listTicketProducts(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	eventID, active, err := parseProductFilters(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
@@ -921,9 +924,9 @@ func (h *Handler) ListAdminTicketProducts(w http.ResponseWriter, r *http.Request
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	items, err := h.repo.ListTicketProducts(ctx, eventID, active)
+	items, err := h.repo.ListTicketProducts(ctx, eventID, active, ownerID)
 	if err != nil {
-		logger.Error("admin_list_ticket_products", "status", "db_error", "error", err)
+		logger.Error(action, "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
@@ -936,6 +939,11 @@ func (h *Handler) CreateAdminTicketProduct(w http.ResponseWriter, r *http.Reques
 	if _, ok := h.requireAdmin(logger, w, r, "admin_create_ticket_product"); !ok {
 		return
 	}
+	h.createTicketProduct(w, r, logger, "admin_create_ticket_product", nil)
+}
+
+// createTicketProduct creates a ticket product, limited to events of ownerID when it is set.
+func (h *Handler) createTicketProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	adminID, ok := middleware.UserIDFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "unauthorized")
@@ -948,9 +956,9 @@ func (h *Handler) CreateAdminTicketProduct(w http.ResponseWriter, r *http.Reques
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.CreateTicketProduct(ctx, adminID, req)
+	item, err := h.repo.CreateTicketProduct(ctx, adminID, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_create_ticket_product", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, item)
@@ -962,6 +970,11 @@ func (h *Handler) PatchAdminTicketProduct(w http.ResponseWriter, r *http.Request
 	if _, ok := h.requireAdmin(logger, w, r, "admin_patch_ticket_product"); !ok {
 		return
 	}
+	h.patchTicketProduct(w, r, logger, "admin_patch_ticket_product", nil)
+}
+
+// patchTicketProduct updates a ticket product, limited to events of ownerID when it is set.
+func (h *Handler) patchTicketProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid product id")
@@ -974,9 +987,9 @@ func (h *Handler) PatchAdminTicketProduct(w http.ResponseWriter, r *http.Request
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.UpdateTicketProduct(ctx, id, req)
+	item, err := h.repo.UpdateTicketProduct(ctx, id, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_patch_ticket_product", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, item)
@@ -988,6 +1001,11 @@ func (h *Handler) DeleteAdminTicketProduct(w http.ResponseWriter, r *http.Reques
 	if _, ok := h.requireAdmin(logger, w, r, "admin_delete_ticket_product"); !ok {
 		return
 	}
+	h.deleteTicketProduct(w, r, logger, "admin_delete_ticket_product", nil)
+}
+
+// deleteTicketProduct deletes a ticket product, limited to events of ownerID when it is set.
+func (h *Handler) deleteTicketProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid product id")
@@ -995,8 +1013,8 @@ func (h *Handler) DeleteAdminTicketProduct(w http.ResponseWriter, r *http.Reques
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	if err := h.repo.DeleteTicketProduct(ctx, id); err != nil {
-		h.handleTicketingError(logger, w, "admin_delete_ticket_product", err)
+	if err := h.repo.DeleteTicketProduct(ctx, id, ownerID); err != nil {
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
@@ -1008,6 +1026,11 @@ func (h *Handler) ListAdminTransferProducts(w http.ResponseWriter, r *http.Reque
 	if _, ok := h.requireAdmin(logger, w, r, "admin_list_transfer_products"); !ok {
 		return
 	}
+	h.listTransferProducts(w, r, logger, "admin_list_transfer_products", nil)
+}
+
+// listTransferProducts lists transfer products, limited to events of ownerID when it is set.
+func (h *Handler) listTransferProducts(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	eventID, active, err := parseProductFilters(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
@@ -1015,9 +1038,9 @@ func (h *Handler) ListAdminTransferProducts(w http.ResponseWriter, r *http.Reque
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	items, err := h.repo.ListTransferProducts(ctx, eventID, active)
+	items, err := h.repo.ListTransferProducts(ctx, eventID, active, ownerID)
 	if err != nil {
-		logger.Error("admin_list_transfer_products", "status", "db_error", "error", err)
+		logger.Error(action, "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
@@ -1030,6 +1053,11 @@ func (h *Handler) CreateAdminTransferProduct(w http.ResponseWriter, r *http.Requ
 	if _, ok := h.requireAdmin(logger, w, r, "admin_create_transfer_product"); !ok {
 		return
 	}
+	h.createTransferProduct(w, r, logger, "admin_create_transfer_product", nil)
+}
+
+// createTransferProduct creates a transfer product, limited to events of ownerID when it is set.
+func (h *Handler) createTransferProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	adminID, ok := middleware.UserIDFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "unauthorized")
@@ -1042,9 +1070,9 @@ func (h *Handler) CreateAdminTransferProduct(w http.ResponseWriter, r *http.Requ
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.CreateTransferProduct(ctx, adminID, req)
+	item, err := h.repo.CreateTransferProduct(ctx, adminID, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_create_transfer_product", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, item)
@@ -1056,6 +1084,11 @@ func (h *Handler) PatchAdminTransferProduct(w http.ResponseWriter, r *http.Reque
 	if _, ok := h.requireAdmin(logger, w, r, "admin_patch_transfer_product"); !ok {
 		return
 	}
+	h.patchTransferProduct(w, r, logger, "admin_patch_transfer_product", nil)
+}
+
+// patchTransferProduct updates a transfer product, limited to events of ownerID when it is set.
+func (h *Handler) patchTransferProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid product id")
@@ -1068,9 +1101,9 @@ func (h *Handler) PatchAdminTransferProduct(w http.ResponseWriter, r *http.Reque
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.UpdateTransferProduct(ctx, id, req)
+	item, err := h.repo.UpdateTransferProduct(ctx, id, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_patch_transfer_product", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, item)
@@ -1082,6 +1115,11 @@ func (h *Handler) DeleteAdminTransferProduct(w http.ResponseWriter, r *http.Requ
 	if _, ok := h.requireAdmin(logger, w, r, "admin_delete_transfer_product"); !ok {
 		return
 	}
+	h.deleteTransferProduct(w, r, logger, "admin_delete_transfer_product", nil)
+}
+
+// deleteTransferProduct deletes a transfer product, limited to events of ownerID when it is set.
+func (h *Handler) deleteTransferProduct(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid product id")
@@ -1089,8 +1127,8 @@ func (h *Handler) DeleteAdminTransferProduct(w http.ResponseWriter, r *http.Requ
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	if err := h.repo.DeleteTransferProduct(ctx, id); err != nil {
-		h.handleTicketingError(logger, w, "admin_delete_transfer_product", err)
+	if err := h.repo.DeleteTransferProduct(ctx, id, ownerID); err != nil {
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
@@ -1102,6 +1140,11 @@ func (h *Handler) ListAdminPromoCodes(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_list_promo_codes"); !ok {
 		return
 	}
+	h.listPromoCodes(w, r, logger, "admin_list_promo_codes", nil)
+}
+
+// listPromoCodes lists promo codes, limited to events of ownerID when it is set.
+func (h *Handler) listPromoCodes(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	eventID, active, err := parseProductFilters(r)
 	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
@@ -1109,9 +1152,9 @@ func (h *Handler) ListAdminPromoCodes(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	items, err := h.repo.ListPromoCodes(ctx, eventID, active)
+	items, err := h.repo.ListPromoCodes(ctx, eventID, active, ownerID)
 	if err != nil {
-		logger.Error("admin_list_promo_codes", "status", "db_error", "error", err)
+		logger.Error(action, "status", "db_error", "error", err)
 		writeError(w, http.StatusInternalServerError, "db error")
 		return
 	}
@@ -1124,6 +1167,11 @@ func (h *Handler) CreateAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_create_promo_code"); !ok {
 		return
 	}
+	h.createPromoCode(w, r, logger, "admin_create_promo_code", nil)
+}
+
+// createPromoCode creates a promo code, limited to events of ownerID when it is set.
+func (h *Handler) createPromoCode(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	adminID, ok := middleware.UserIDFromContext(r.Context())
 	if !ok {
 		writeError(w, http.StatusUnauthorized, "unauthorized")
@@ -1136,9 +1184,9 @@ func (h *Handler) CreateAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.CreatePromoCode(ctx, adminID, req)
+	item, err := h.repo.CreatePromoCode(ctx, adminID, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_create_promo_code", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusCreated, item)
@@ -1150,6 +1198,11 @@ func (h *Handler) PatchAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_patch_promo_code"); !ok {
 		return
 	}
+	h.patchPromoCode(w, r, logger, "admin_patch_promo_code", nil)
+}
+
+// patchPromoCode updates a promo code, limited to events of ownerID when it is set.
+func (h *Handler) patchPromoCode(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid promo id")
@@ -1162,9 +1215,9 @@ func (h *Handler) PatchAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	item, err := h.repo.UpdatePromoCode(ctx, id, req)
+	item, err := h.repo.UpdatePromoCode(ctx, id, req, ownerID)
 	if err != nil {
-		h.handleTicketingError(logger, w, "admin_patch_promo_code", err)
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, item)
@@ -1176,6 +1229,11 @@ func (h *Handler) DeleteAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	if _, ok := h.requireAdmin(logger, w, r, "admin_delete_promo_code"); !ok {
 		return
 	}
+	h.deletePromoCode(w, r, logger, "admin_delete_promo_code", nil)
+}
+
+// deletePromoCode deletes a promo code, limited to events of ownerID when it is set.
+func (h *Handler) deletePromoCode(w http.ResponseWriter, r *http.Request, logger *slog.Logger, action string, ownerID *int64) {
 	id := strings.TrimSpace(chi.URLParam(r, "id"))
 	if id == "" {
 		writeError(w, http.StatusBadRequest, "invalid promo id")
@@ -1183,8 +1241,8 @@ func (h *Handler) DeleteAdminPromoCode(w http.ResponseWriter, r *http.Request) {
 	}
 	ctx, cancel := h.withTimeout(r.Context())
 	defer cancel()
-	if err := h.repo.DeletePromoCode(ctx, id); err != nil {
-		h.handleTicketingError(logger, w, "admin_delete_promo_code", err)
+	if err := h.repo.DeletePromoCode(ctx, id, ownerID); err != nil {
+		h.handleTicketingError(logger, w, action, err)
 		return
 	}
 	writeJSON(w, http.StatusOK, map[string]bool{"ok": true})
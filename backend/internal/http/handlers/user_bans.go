@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gigme/backend/internal/http/middleware"
+	"gigme/backend/internal/models"
+	"gigme/backend/internal/repository"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxBanReasonLength = 500
+
+// createBanRequest represents create ban request. ExpiresAt and DurationHours
+// are mutually exclusive; without both the ban is permanent.
+type createBanRequest struct {
+	Scope         string  `json:"scope"`
+	Reason        string  `json:"reason"`
+	ExpiresAt     *string `json:"expiresAt"`
+	DurationHours *int    `json:"durationHours"`
+}
+
+// validateBanRequest normalizes a ban request and returns the expiry time.
+func validateBanRequest(req *createBanRequest, now time.Time) (*time.Time, error) {
+	req.Scope = strings.TrimSpace(req.Scope)
+	if req.Scope == "" {
+		req.Scope = models.BanScopeFull
+	}
+	valid := false
+	for _, scope := range models.BanScopes {
+		if req.Scope == scope {
+			valid = true
+			break
+		}
+	}
+	if !valid {
+		return nil, errors.New("invalid scope")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if utf8.RuneCountInString(req.Reason) > maxBanReasonLength {
+		return nil, errors.New("reason too long")
+	}
+	if req.ExpiresAt != nil && req.DurationHours != nil {
+		return nil, errors.New("use either expiresAt or durationHours")
+	}
+	if req.DurationHours != nil {
+		if *req.DurationHours <= 0 {
+			return nil, errors.New("durationHours must be positive")
+		}
+		expiresAt := now.Add(time.Duration(*req.DurationHours) * time.Hour)
+		return &expiresAt, nil
+	}
+	if req.ExpiresAt != nil && strings.TrimSpace(*req.ExpiresAt) != "" {
+		expiresAt, err := time.Parse(time.RFC3339, strings.TrimSpace(*req.ExpiresAt))
+		if err != nil {
+			return nil, errors.New("invalid expiresAt")
+		}
+		if !expiresAt.After(now) {
+			return nil, errors.New("expiresAt must be in the future")
+		}
+		return &expiresAt, nil
+	}
+	return nil, nil
+}
+
+// CreateAdminUserBan bans a user from the whole app or from commenting, creating
+// events or purchases, permanently or until the expiry time.
+func (h *Handler) CreateAdminUserBan(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_create_ban"); !ok {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_create_ban", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	var req createBanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		logger.Warn("action", "action", "admin_create_ban", "status", "invalid_json")
+		writeError(w, http.StatusBadRequest, "invalid json")
+		return
+	}
+	expiresAt, err := validateBanRequest(&req, time.Now())
+	if err != nil {
+		logger.Warn("action", "action", "admin_create_ban", "status", "invalid_request", "error", err)
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	adminUserID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	ban, err := h.repo.CreateUserBan(ctx, models.UserBan{
+		UserID:    id,
+		Scope:     req.Scope,
+		Reason:    req.Reason,
+		ExpiresAt: expiresAt,
+		CreatedBy: &adminUserID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.Warn("action", "action", "admin_create_ban", "status", "not_found", "user_id", id)
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		logger.Error("action", "action", "admin_create_ban", "status", "db_error", "error", err)
+		writeError(w, http.StatusInternalServerError, "db error")
+		return
+	}
+	logger.Info("action", "action", "admin_create_ban", "status", "success", "user_id", id, "ban_id", ban.ID, "scope", ban.Scope)
+	writeJSON(w, http.StatusCreated, ban)
+}
+
+// LiftAdminUserBan lifts a ban before it expires.
+func (h *Handler) LiftAdminUserBan(w http.ResponseWriter, r *http.Request) {
+	logger := h.loggerForRequest(r)
+	if _, ok := h.requireAdmin(logger, w, r, "admin_lift_ban"); !ok {
+		return
+	}
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_lift_ban", "status", "invalid_user_id")
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+	banID, err := strconv.ParseInt(chi.URLParam(r, "banId"), 10, 64)
+	if err != nil {
+		logger.Warn("action", "action", "admin_lift_ban", "status", "invalid_ban_id")
+		writeError(w, http.StatusBadRequest, "invalid ban id")
+		return
+	}
+	adminUserID, _ := middleware.UserIDFromContext(r.Context())
+
+	ctx, cancel := h.withTimeout(r.Context())
+	defer cancel()
+	ban, err := h.repo.LiftUserBan(ctx, id, banID, adminUserID)
+	if err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			logger.Warn("action", "action", "admin_lift_ban", "status", "not_found", "ban_id", banID)
+			writeError(w, http.StatusNotFound, "ban not found")
+		case errors.Is(err, repository.ErrBanLifted):
+			logger.Warn("action", "action", "admin_lift_ban", "status", "not_active", "ban_id", banID)
+			writeError(w, http.StatusConflict, "ban is not active")
+		default:
+			logger.Error("action", "action", "admin_lift_ban", "status", "db_error", "error", err)
+			writeError(w, http.StatusInternalServerError, "db error")
+		}
+		return
+	}
+	logger.Info("action", "action", "admin_lift_ban", "status", "success", "user_id", id, "ban_id", banID)
+	writeJSON(w, http.StatusOK, ban)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestValidateBanRequest verifies validate ban request behavior.
+func TestValidateBanRequest(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	req := createBanRequest{Reason: "  spam  "}
+	expiresAt, err := validateBanRequest(&req, now)
+	if err != nil || expiresAt != nil || req.Scope != models.BanScopeFull || req.Reason != "spam" {
+		t.Fatalf("unexpected permanent ban: %+v %v %v", req, expiresAt, err)
+	}
+
+	hours := 24
+	req = createBanRequest{Scope: "comments", DurationHours: &hours}
+	expiresAt, err = validateBanRequest(&req, now)
+	if err != nil || expiresAt == nil || !expiresAt.Equal(now.Add(24*time.Hour)) {
+		t.Fatalf("unexpected duration ban: %v %v", expiresAt, err)
+	}
+
+	until := "2024-05-03T00:00:00Z"
+	req = createBanRequest{Scope: "purchases", ExpiresAt: &until}
+	expiresAt, err = validateBanRequest(&req, now)
+	if err != nil || expiresAt == nil || expiresAt.Day() != 3 {
+		t.Fatalf("unexpected expiring ban: %v %v", expiresAt, err)
+	}
+
+	past := "2024-04-30T00:00:00Z"
+	cases := []createBanRequest{
+		{Scope: "likes"},
+		{Scope: "events", ExpiresAt: &past},
+		{Scope: "events", ExpiresAt: &until, DurationHours: &hours},
+	}
+	zero := 0
+	cases = append(cases, createBanRequest{Scope: "events", DurationHours: &zero})
+	for _, tc := range cases {
+		tc := tc
+		if _, err := validateBanRequest(&tc, now); err == nil {
+			t.Fatalf("expected error for %+v", tc)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// TestRequireNoBan verifies require no ban behavior.
+func TestRequireNoBan(t *testing.T) {
+	expiresAt := time.Date(2024, 5, 3, 18, 30, 0, 0, time.UTC)
+	bans := []models.UserBan{{ID: 1, Scope: models.BanScopeComments, Reason: "оскорбления", ExpiresAt: &expiresAt}}
+	handler := RequireNoBan(models.BanScopeComments)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/events/1/comments", nil)
+	req = req.WithContext(context.WithValue(req.Context(), userBansKey, bans))
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+	if resp.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d", resp.Code)
+	}
+	body := resp.Body.String()
+	for _, want := range []string{`"banned"`, "03.05.2024 18:30 UTC", "оскорбления"} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in %s", want, body)
+		}
+	}
+
+	purchases := RequireNoBan(models.BanScopePurchases)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	resp = httptest.NewRecorder()
+	purchases.ServeHTTP(resp, req)
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200 for other scope, got %d", resp.Code)
+	}
+}
+
+// TestFindBan verifies find ban behavior.
+func TestFindBan(t *testing.T) {
+	soon := time.Now().Add(time.Hour)
+	later := time.Now().Add(48 * time.Hour)
+	bans := []models.UserBan{
+		{ID: 1, Scope: models.BanScopeEvents, ExpiresAt: &soon},
+		{ID: 2, Scope: models.BanScopeEvents, ExpiresAt: &later},
+		{ID: 3, Scope: models.BanScopeComments},
+	}
+	if ban, ok := findBan(bans, models.BanScopeEvents); !ok || ban.ID != 2 {
+		t.Fatalf("expected the longest events ban, got %+v", ban)
+	}
+	bans = append(bans, models.UserBan{ID: 4, Scope: models.BanScopeEvents})
+	if ban, ok := findBan(bans, models.BanScopeEvents); !ok || ban.ID != 4 {
+		t.Fatalf("expected the permanent events ban, got %+v", ban)
+	}
+	if _, ok := findBan(bans, models.BanScopeFull); ok {
+		t.Fatalf("expected no full ban")
+	}
+	if got := banMessage(models.UserBan{Scope: models.BanScopeFull}); got != "Ваш аккаунт заблокирован" {
+		t.Fatalf("unexpected message %q", got)
+	}
+}
@@ -1,13 +1,28 @@
 package middleware
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
 
+	"gigme/backend/internal/models"
 	"gigme/backend/internal/repository"
 )
 
-// BlockedUserMiddleware handles blocked user middleware.
+const userBansKey contextKey = "user_bans"
+
+// banExpiryLayout formats ban expiry times in messages shown to users.
+const banExpiryLayout = "02.01.2006 15:04 UTC"
+
+// UserBansFromContext returns scoped bans of the current user loaded by BlockedUserMiddleware.
+func UserBansFromContext(ctx context.Context) []models.UserBan {
+	bans, _ := ctx.Value(userBansKey).([]models.UserBan)
+	return bans
+}
+
+// BlockedUserMiddleware rejects users with a full ban in force and stores
+// their scoped bans in the request context for RequireNoBan.
 func BlockedUserMiddleware(repo *repository.Repository, adminTGIDs map[int64]struct{}) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -31,13 +46,30 @@ func BlockedUserMiddleware(repo *repository.Repository, adminTGIDs map[int64]str
 				next.ServeHTTP(w, r)
 				return
 			}
-			blocked, err := repo.IsUserBlocked(r.Context(), userID)
+			bans, err := repo.GetActiveUserBans(r.Context(), userID)
 			if err != nil {
 				w.WriteHeader(http.StatusInternalServerError)
 				return
 			}
-			if blocked {
-				writeBlocked(w)
+			if ban, ok := findBan(bans, models.BanScopeFull); ok {
+				writeBanned(w, ban)
+				return
+			}
+			if len(bans) > 0 {
+				r = r.WithContext(context.WithValue(r.Context(), userBansKey, bans))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireNoBan rejects users with a ban of the given scope in force. It relies
+// on BlockedUserMiddleware running earlier in the chain.
+func RequireNoBan(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ban, ok := findBan(UserBansFromContext(r.Context()), scope); ok {
+				writeBanned(w, ban)
 				return
 			}
 			next.ServeHTTP(w, r)
@@ -45,12 +77,69 @@ func BlockedUserMiddleware(repo *repository.Repository, adminTGIDs map[int64]str
 	}
 }
 
-// writeBlocked writes blocked.
-func writeBlocked(w http.ResponseWriter) {
+// findBan returns the ban with the given scope, preferring permanent bans and
+// then the one that expires last.
+func findBan(bans []models.UserBan, scope string) (models.UserBan, bool) {
+	var out models.UserBan
+	found := false
+	for _, ban := range bans {
+		if ban.Scope != scope {
+			continue
+		}
+		switch {
+		case !found:
+			out, found = ban, true
+		case out.ExpiresAt == nil:
+		case ban.ExpiresAt == nil || ban.ExpiresAt.After(*out.ExpiresAt):
+			out = ban
+		}
+	}
+	return out, found
+}
+
+// banMessage returns the text shown to a banned user.
+func banMessage(ban models.UserBan) string {
+	var b strings.Builder
+	switch ban.Scope {
+	case models.BanScopeComments:
+		b.WriteString("Вам запрещено оставлять комментарии")
+	case models.BanScopeEvents:
+		b.WriteString("Вам запрещено создавать события")
+	case models.BanScopePurchases:
+		b.WriteString("Вам запрещено совершать покупки")
+	default:
+		b.WriteString("Ваш аккаунт заблокирован")
+	}
+	if ban.ExpiresAt != nil {
+		b.WriteString(" до ")
+		b.WriteString(ban.ExpiresAt.UTC().Format(banExpiryLayout))
+	}
+	if reason := strings.TrimSpace(ban.Reason); reason != "" {
+		b.WriteString(". Причина: ")
+		b.WriteString(reason)
+	}
+	return b.String()
+}
+
+// writeBanned writes a 403 response describing the ban. Full bans keep the
+// "blocked" error code clients already handle.
+func writeBanned(w http.ResponseWriter, ban models.UserBan) {
+	code := "banned"
+	if ban.Scope == models.BanScopeFull {
+		code = "blocked"
+	}
+	body := map[string]interface{}{
+		"error":   code,
+		"message": banMessage(ban),
+		"scope":   ban.Scope,
+	}
+	if ban.Reason != "" {
+		body["reason"] = ban.Reason
+	}
+	if ban.ExpiresAt != nil {
+		body["expiresAt"] = ban.ExpiresAt.UTC()
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusForbidden)
-	_ = json.NewEncoder(w).Encode(map[string]string{
-		"error":   "blocked",
-		"message": "Ваш аккаунт заблокирован",
-	})
+	_ = json.NewEncoder(w).Encode(body)
 }
@@ -0,0 +1,313 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	lineLimit      = 75
+	dateTimeLayout = "20060102T150405"
+)
+
+// Calendar represents an iCalendar object with its events.
+type Calendar struct {
+	ProdID          string
+	Name            string
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event represents a single VEVENT.
+type Event struct {
+	UID         string
+	Sequence    int
+	Start       time.Time
+	End         time.Time
+	Location    *time.Location
+	Summary     string
+	Description string
+	Address     string
+	Lat         float64
+	Lng         float64
+	HasGeo      bool
+	URL         string
+	Cancelled   bool
+	Created     time.Time
+	Updated     time.Time
+}
+
+// Encode renders the calendar as an RFC 5545 document.
+func (c Calendar) Encode() []byte {
+	var b builder
+	b.line("BEGIN:VCALENDAR")
+	b.line("VERSION:2.0")
+	b.line("PRODID:" + c.ProdID)
+	b.line("CALSCALE:GREGORIAN")
+	b.line("METHOD:PUBLISH")
+	if c.Name != "" {
+		b.line("X-WR-CALNAME:" + escapeText(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		b.line("REFRESH-INTERVAL;VALUE=DURATION:" + duration)
+		b.line("X-PUBLISHED-TTL:" + duration)
+	}
+	for _, zone := range collectZones(c.Events) {
+		writeTimezone(&b, zone.loc, zone.from, zone.to)
+	}
+	for _, event := range c.Events {
+		writeEvent(&b, event)
+	}
+	b.line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// builder accumulates folded content lines.
+type builder struct {
+	strings.Builder
+}
+
+// line writes a content line, folding it at 75 octets without splitting runes.
+// Continuation lines start with a space that counts towards the limit.
+func (b *builder) line(content string) {
+	limit := lineLimit
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		limit = lineLimit - 1
+	}
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
+
+// writeEvent writes a VEVENT component.
+func writeEvent(b *builder, event Event) {
+	stamp := event.Updated
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	b.line("BEGIN:VEVENT")
+	b.line("UID:" + event.UID)
+	b.line("DTSTAMP:" + formatUTC(stamp))
+	if !event.Created.IsZero() {
+		b.line("CREATED:" + formatUTC(event.Created))
+	}
+	if !event.Updated.IsZero() {
+		b.line("LAST-MODIFIED:" + formatUTC(event.Updated))
+	}
+	b.line(fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+	b.line("DTSTART" + formatDateTime(event.Start, event.Location))
+	if !event.End.IsZero() {
+		b.line("DTEND" + formatDateTime(event.End, event.Location))
+	}
+	b.line("SUMMARY:" + escapeText(event.Summary))
+	if event.Description != "" {
+		b.line("DESCRIPTION:" + escapeText(event.Description))
+	}
+	if event.Address != "" {
+		b.line("LOCATION:" + escapeText(event.Address))
+	}
+	if event.HasGeo {
+		b.line(fmt.Sprintf("GEO:%.6f;%.6f", event.Lat, event.Lng))
+	}
+	if event.URL != "" {
+		b.line("URL:" + event.URL)
+	}
+	if event.Cancelled {
+		b.line("STATUS:CANCELLED")
+	} else {
+		b.line("STATUS:CONFIRMED")
+	}
+	b.line("END:VEVENT")
+}
+
+// zoneRange is a timezone together with the period its events cover.
+type zoneRange struct {
+	loc  *time.Location
+	from time.Time
+	to   time.Time
+}
+
+// collectZones returns the non-UTC timezones used by events, sorted by name.
+func collectZones(events []Event) []zoneRange {
+	byName := make(map[string]*zoneRange)
+	for _, event := range events {
+		if isUTC(event.Location) {
+			continue
+		}
+		end := event.End
+		if end.IsZero() {
+			end = event.Start
+		}
+		zone, ok := byName[event.Location.String()]
+		if !ok {
+			byName[event.Location.String()] = &zoneRange{loc: event.Location, from: event.Start, to: end}
+			continue
+		}
+		if event.Start.Before(zone.from) {
+			zone.from = event.Start
+		}
+		if end.After(zone.to) {
+			zone.to = end
+		}
+	}
+	out := make([]zoneRange, 0, len(byName))
+	for _, zone := range byName {
+		out = append(out, *zone)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].loc.String() < out[j].loc.String() })
+	return out
+}
+
+// transition is a change of UTC offset in a timezone.
+type transition struct {
+	at         time.Time
+	offsetFrom int
+	offsetTo   int
+	name       string
+	daylight   bool
+}
+
+// writeTimezone writes a VTIMEZONE covering the given period.
+// Observances are listed with explicit onsets instead of recurrence rules,
+// which stays correct for zones whose rules changed over the years.
+func writeTimezone(b *builder, loc *time.Location, from, to time.Time) {
+	start := time.Date(from.In(loc).Year(), time.January, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to.In(loc).Year()+1, time.January, 1, 0, 0, 0, 0, loc)
+
+	name, offset := start.Zone()
+	initial := transition{
+		at:         start,
+		offsetFrom: offset,
+		offsetTo:   offset,
+		name:       name,
+		daylight:   start.IsDST(),
+	}
+
+	b.line("BEGIN:VTIMEZONE")
+	b.line("TZID:" + loc.String())
+	writeObservance(b, initial)
+	for _, item := range zoneTransitions(loc, start, end) {
+		writeObservance(b, item)
+	}
+	b.line("END:VTIMEZONE")
+}
+
+// writeObservance writes a STANDARD or DAYLIGHT sub-component.
+func writeObservance(b *builder, item transition) {
+	kind := "STANDARD"
+	if item.daylight {
+		kind = "DAYLIGHT"
+	}
+	onset := item.at.UTC().Add(time.Duration(item.offsetFrom) * time.Second)
+	b.line("BEGIN:" + kind)
+	b.line("DTSTART:" + onset.Format(dateTimeLayout))
+	b.line("TZOFFSETFROM:" + formatOffset(item.offsetFrom))
+	b.line("TZOFFSETTO:" + formatOffset(item.offsetTo))
+	if item.name != "" {
+		b.line("TZNAME:" + escapeText(item.name))
+	}
+	b.line("END:" + kind)
+}
+
+// zoneTransitions returns offset changes of loc within [from, to).
+func zoneTransitions(loc *time.Location, from, to time.Time) []transition {
+	out := make([]transition, 0, 4)
+	current := from
+	_, offset := current.In(loc).Zone()
+	for current.Before(to) {
+		next := current.Add(24 * time.Hour)
+		_, nextOffset := next.In(loc).Zone()
+		if nextOffset != offset {
+			lo, hi := current.Unix(), next.Unix()
+			for hi-lo > 1 {
+				mid := lo + (hi-lo)/2
+				if _, midOffset := time.Unix(mid, 0).In(loc).Zone(); midOffset == offset {
+					lo = mid
+				} else {
+					hi = mid
+				}
+			}
+			at := time.Unix(hi, 0).In(loc)
+			name, _ := at.Zone()
+			out = append(out, transition{
+				at:         at,
+				offsetFrom: offset,
+				offsetTo:   nextOffset,
+				name:       name,
+				daylight:   at.IsDST(),
+			})
+			offset = nextOffset
+		}
+		current = next
+	}
+	return out
+}
+
+// formatDateTime formats a DTSTART/DTEND value with its parameters.
+func formatDateTime(value time.Time, loc *time.Location) string {
+	if isUTC(loc) {
+		return ":" + formatUTC(value)
+	}
+	return ";TZID=" + loc.String() + ":" + value.In(loc).Format(dateTimeLayout)
+}
+
+// formatUTC formats a UTC date-time value.
+func formatUTC(value time.Time) string {
+	return value.UTC().Format(dateTimeLayout) + "Z"
+}
+
+// formatOffset formats a UTC offset as +HHMM or +HHMMSS.
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	hours := seconds / 3600
+	minutes := seconds % 3600 / 60
+	rest := seconds % 60
+	if rest != 0 {
+		return fmt.Sprintf("%s%02d%02d%02d", sign, hours, minutes, rest)
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, hours, minutes)
+}
+
+// formatDuration formats a positive duration as an iCalendar duration.
+func formatDuration(value time.Duration) string {
+	if value%time.Hour == 0 {
+		return fmt.Sprintf("PT%dH", int(value/time.Hour))
+	}
+	minutes := int(value / time.Minute)
+	if minutes < 1 {
+		minutes = 1
+	}
+	return fmt.Sprintf("PT%dM", minutes)
+}
+
+// escapeText escapes a TEXT property value.
+func escapeText(value string) string {
+	value = strings.ReplaceAll(value, "\r\n", "\n")
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// isUTC reports whether loc is empty or UTC.
+func isUTC(loc *time.Location) bool {
+	return loc == nil || loc == time.UTC || loc.String() == "UTC"
+}
@@ -0,0 +1,83 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncodeEventWithTimezone verifies encode event with timezone behavior.
+func TestEncodeEventWithTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	start := time.Date(2026, 3, 28, 19, 0, 0, 0, loc)
+	out := string(Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{{
+			UID:       "event-1@example.com",
+			Sequence:  3,
+			Start:     start,
+			End:       start.Add(2 * time.Hour),
+			Location:  loc,
+			Summary:   "Jazz, wine; friends",
+			Address:   "Main st. 1",
+			Lat:       52.52,
+			Lng:       13.405,
+			HasGeo:    true,
+			URL:       "https://example.com/space_app#eventId=1",
+			Cancelled: true,
+			Updated:   time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC),
+		}},
+	}.Encode())
+
+	for _, want := range []string{
+		"BEGIN:VTIMEZONE\r\nTZID:Europe/Berlin\r\n",
+		"BEGIN:DAYLIGHT\r\nDTSTART:20260329T020000\r\nTZOFFSETFROM:+0100\r\nTZOFFSETTO:+0200\r\nTZNAME:CEST\r\n",
+		"BEGIN:STANDARD\r\nDTSTART:20261025T030000\r\nTZOFFSETFROM:+0200\r\nTZOFFSETTO:+0100\r\n",
+		"DTSTART;TZID=Europe/Berlin:20260328T190000\r\n",
+		"DTEND;TZID=Europe/Berlin:20260328T210000\r\n",
+		"SEQUENCE:3\r\n",
+		"SUMMARY:Jazz\\, wine\\; friends\r\n",
+		"GEO:52.520000;13.405000\r\n",
+		"STATUS:CANCELLED\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+// TestEncodeUTCEventSkipsTimezone verifies encode u t c event skips timezone behavior.
+func TestEncodeUTCEventSkipsTimezone(t *testing.T) {
+	start := time.Date(2026, 5, 1, 18, 0, 0, 0, time.UTC)
+	out := string(Calendar{
+		ProdID: "-//test//EN",
+		Events: []Event{{UID: "event-2@example.com", Start: start, Summary: "Talk"}},
+	}.Encode())
+	if strings.Contains(out, "VTIMEZONE") {
+		t.Fatalf("unexpected VTIMEZONE for UTC event:\n%s", out)
+	}
+	if !strings.Contains(out, "DTSTART:20260501T180000Z\r\n") || !strings.Contains(out, "STATUS:CONFIRMED\r\n") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
+
+// TestLineFolding verifies line folding behavior.
+func TestLineFolding(t *testing.T) {
+	var b builder
+	b.line("DESCRIPTION:" + strings.Repeat("пр", 60))
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if len(line) > lineLimit {
+			t.Fatalf("line exceeds %d octets: %d", lineLimit, len(line))
+		}
+		if !strings.HasPrefix(line, "DESCRIPTION:") && !strings.HasPrefix(line, " ") {
+			t.Fatalf("continuation line must start with a space: %q", line)
+		}
+	}
+	unfolded := strings.ReplaceAll(b.String(), "\r\n ", "")
+	if unfolded != "DESCRIPTION:"+strings.Repeat("пр", 60)+"\r\n" {
+		t.Fatalf("unfolded line mismatch: %q", unfolded)
+	}
+}
@@ -0,0 +1,70 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig represents SMTP settings of outgoing email.
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// Mailer sends plain text email through an SMTP server.
+type Mailer struct {
+	cfg      SMTPConfig
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+	now      func() time.Time
+}
+
+// NewMailer creates a mailer.
+func NewMailer(cfg SMTPConfig) *Mailer {
+	if cfg.Port <= 0 {
+		cfg.Port = 587
+	}
+	return &Mailer{cfg: cfg, sendMail: smtp.SendMail, now: time.Now}
+}
+
+// Send sends a plain text email. The server's STARTTLS is used when offered.
+func (m *Mailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+	addr := net.JoinHostPort(m.cfg.Host, strconv.Itoa(m.cfg.Port))
+	return m.sendMail(addr, auth, m.cfg.From, []string{to}, buildEmailMessage(m.cfg.From, to, subject, body, m.now()))
+}
+
+// buildEmailMessage builds a UTF-8 plain text message with a base64 body.
+func buildEmailMessage(from, to, subject, body string, now time.Time) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+	encoded := base64.StdEncoding.EncodeToString([]byte(strings.ReplaceAll(body, "\n", "\r\n")))
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+	return buf.Bytes()
+}
@@ -0,0 +1,156 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultAPNsBaseURL = "https://api.push.apple.com"
+	// apnsTokenLifetime is below the one hour after which APNs rejects provider tokens.
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig represents APNs token-based authentication settings. PrivateKey
+// is the PEM contents of the .p8 signing key.
+type APNsConfig struct {
+	KeyID      string
+	TeamID     string
+	BundleID   string
+	PrivateKey string
+	BaseURL    string
+}
+
+// APNsSender sends notifications through APNs with token-based authentication.
+type APNsSender struct {
+	cfg         APNsConfig
+	key         *ecdsa.PrivateKey
+	client      *http.Client
+	now         func() time.Time
+	mu          sync.Mutex
+	cachedToken string
+	issuedAt    time.Time
+}
+
+// NewAPNsSender creates an APNs sender.
+func NewAPNsSender(cfg APNsConfig, client *http.Client) (*APNsSender, error) {
+	if strings.TrimSpace(cfg.KeyID) == "" || strings.TrimSpace(cfg.TeamID) == "" || strings.TrimSpace(cfg.BundleID) == "" {
+		return nil, fmt.Errorf("apns key id, team id and bundle id are required")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse apns private key: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultAPNsBaseURL
+	}
+	return &APNsSender{cfg: cfg, key: key, client: client, now: time.Now}, nil
+}
+
+// apnsAlert represents the alert of an APNs payload.
+type apnsAlert struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// apnsAps represents the aps dictionary of an APNs payload.
+type apnsAps struct {
+	Alert apnsAlert `json:"alert"`
+	Sound string    `json:"sound,omitempty"`
+}
+
+// Send sends a notification to an APNs device token.
+func (s *APNsSender) Send(ctx context.Context, token string, msg Message) error {
+	providerToken, err := s.providerToken()
+	if err != nil {
+		return err
+	}
+	body := map[string]interface{}{
+		"aps": apnsAps{Alert: apnsAlert{Title: msg.Title, Body: msg.Body}, Sound: "default"},
+	}
+	for key, value := range msg.Data {
+		if key != "aps" {
+			body[key] = value
+		}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	target := s.cfg.BaseURL + "/3/device/" + url.PathEscape(token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", s.cfg.BundleID)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	apiErr := &APIError{Provider: "apns", StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(respBody))}
+	var parsed struct {
+		Reason string `json:"reason"`
+	}
+	if json.Unmarshal(respBody, &parsed) == nil {
+		apiErr.Reason = parsed.Reason
+	}
+	apiErr.Unregistered = resp.StatusCode == http.StatusGone || apiErr.Reason == "Unregistered" || apiErr.Reason == "BadDeviceToken"
+	if apiErr.Reason == "ExpiredProviderToken" {
+		s.mu.Lock()
+		s.cachedToken = ""
+		s.mu.Unlock()
+	}
+	return apiErr
+}
+
+// providerToken returns the cached ES256 provider token, signing a new one
+// when it is older than apnsTokenLifetime.
+func (s *APNsSender) providerToken() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.cachedToken != "" && now.Sub(s.issuedAt) < apnsTokenLifetime {
+		return s.cachedToken, nil
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": s.cfg.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = s.cfg.KeyID
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+	s.cachedToken = signed
+	s.issuedAt = now
+	return signed, nil
+}
@@ -0,0 +1,237 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	fcmScope          = "https://www.googleapis.com/auth/firebase.messaging"
+	defaultFCMBaseURL = "https://fcm.googleapis.com"
+	defaultTokenURL   = "https://oauth2.googleapis.com/token"
+)
+
+// FCMConfig represents FCM HTTP v1 service account settings.
+type FCMConfig struct {
+	ProjectID   string
+	ClientEmail string
+	PrivateKey  string
+	TokenURL    string
+	BaseURL     string
+}
+
+// serviceAccountFile represents the fields of a Google service account key file.
+type serviceAccountFile struct {
+	ProjectID   string `json:"project_id"`
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+// LoadFCMConfig reads FCM settings from a service account key file.
+func LoadFCMConfig(path string) (FCMConfig, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return FCMConfig{}, err
+	}
+	var file serviceAccountFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return FCMConfig{}, fmt.Errorf("decode service account: %w", err)
+	}
+	return FCMConfig{
+		ProjectID:   file.ProjectID,
+		ClientEmail: file.ClientEmail,
+		PrivateKey:  file.PrivateKey,
+		TokenURL:    file.TokenURI,
+	}, nil
+}
+
+// FCMSender sends notifications through the FCM HTTP v1 API.
+type FCMSender struct {
+	cfg          FCMConfig
+	key          *rsa.PrivateKey
+	client       *http.Client
+	now          func() time.Time
+	mu           sync.Mutex
+	cachedToken  string
+	cachedExpiry time.Time
+}
+
+// NewFCMSender creates an FCM sender.
+func NewFCMSender(cfg FCMConfig, client *http.Client) (*FCMSender, error) {
+	if strings.TrimSpace(cfg.ProjectID) == "" || strings.TrimSpace(cfg.ClientEmail) == "" {
+		return nil, fmt.Errorf("fcm project id and client email are required")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse fcm private key: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if strings.TrimSpace(cfg.TokenURL) == "" {
+		cfg.TokenURL = defaultTokenURL
+	}
+	cfg.BaseURL = strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/")
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultFCMBaseURL
+	}
+	return &FCMSender{cfg: cfg, key: key, client: client, now: time.Now}, nil
+}
+
+// fcmRequest represents an FCM v1 send request.
+type fcmRequest struct {
+	Message fcmMessage `json:"message"`
+}
+
+// fcmMessage represents an FCM v1 message.
+type fcmMessage struct {
+	Token        string            `json:"token"`
+	Notification fcmNotification   `json:"notification"`
+	Data         map[string]string `json:"data,omitempty"`
+}
+
+// fcmNotification represents an FCM v1 notification.
+type fcmNotification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+}
+
+// fcmErrorResponse represents an FCM v1 error response.
+type fcmErrorResponse struct {
+	Error struct {
+		Status  string `json:"status"`
+		Message string `json:"message"`
+		Details []struct {
+			ErrorCode string `json:"errorCode"`
+		} `json:"details"`
+	} `json:"error"`
+}
+
+// Send sends a notification to an FCM registration token.
+func (s *FCMSender) Send(ctx context.Context, token string, msg Message) error {
+	accessToken, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(fcmRequest{Message: fcmMessage{
+		Token:        token,
+		Notification: fcmNotification{Title: msg.Title, Body: msg.Body},
+		Data:         msg.Data,
+	}})
+	if err != nil {
+		return err
+	}
+	target := fmt.Sprintf("%s/v1/projects/%s/messages:send", s.cfg.BaseURL, url.PathEscape(s.cfg.ProjectID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	apiErr := &APIError{Provider: "fcm", StatusCode: resp.StatusCode, Body: strings.TrimSpace(string(body))}
+	var parsed fcmErrorResponse
+	if json.Unmarshal(body, &parsed) == nil {
+		apiErr.Reason = parsed.Error.Status
+		for _, detail := range parsed.Error.Details {
+			if detail.ErrorCode != "" {
+				apiErr.Reason = detail.ErrorCode
+			}
+		}
+	}
+	apiErr.Unregistered = apiErr.Reason == "UNREGISTERED"
+	if resp.StatusCode == http.StatusUnauthorized {
+		s.mu.Lock()
+		s.cachedToken = ""
+		s.mu.Unlock()
+	}
+	return apiErr
+}
+
+// accessToken returns a cached OAuth access token, exchanging a signed
+// service account assertion when it is missing or about to expire.
+func (s *FCMSender) accessToken(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if s.cachedToken != "" && now.Before(s.cachedExpiry.Add(-time.Minute)) {
+		return s.cachedToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   s.cfg.ClientEmail,
+		"scope": fcmScope,
+		"aud":   s.cfg.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}).SignedString(s.key)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:jwt-bearer")
+	form.Set("assertion", assertion)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("fcm oauth token request failed: status=%d body=%s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	var parsed struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", fmt.Errorf("decode fcm token response: %w", err)
+	}
+	if strings.TrimSpace(parsed.AccessToken) == "" {
+		return "", fmt.Errorf("fcm oauth token response missing access_token")
+	}
+	expiresIn := parsed.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = 300
+	}
+	s.cachedToken = parsed.AccessToken
+	s.cachedExpiry = now.Add(time.Duration(expiresIn) * time.Second)
+	return s.cachedToken, nil
+}
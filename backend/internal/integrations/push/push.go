@@ -0,0 +1,48 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnregistered is returned when the push service reports that a device
+// token is no longer valid and must not be used again.
+var ErrUnregistered = errors.New("push token unregistered")
+
+// Message represents a push notification.
+type Message struct {
+	Title string
+	Body  string
+	Data  map[string]string
+}
+
+// Sender sends push notifications to device tokens.
+type Sender interface {
+	Send(ctx context.Context, token string, msg Message) error
+}
+
+// APIError represents an error response of a push service.
+type APIError struct {
+	Provider     string
+	StatusCode   int
+	Reason       string
+	Body         string
+	Unregistered bool
+}
+
+// Error handles internal error behavior.
+func (e *APIError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("%s push status %d: %s", e.Provider, e.StatusCode, e.Reason)
+	}
+	return fmt.Sprintf("%s push status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// Unwrap makes errors.Is(err, ErrUnregistered) match unregistered tokens.
+func (e *APIError) Unwrap() error {
+	if e.Unregistered {
+		return ErrUnregistered
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package push
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testRSAKey returns a PEM encoded RSA key and its public part.
+func testRSAKey(t *testing.T) (string, *rsa.PublicKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal rsa key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), &key.PublicKey
+}
+
+// testECKey returns a PEM encoded P-256 key like an APNs .p8 file and its public part.
+func testECKey(t *testing.T) (string, *ecdsa.PublicKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ec key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshal ec key: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), &key.PublicKey
+}
+
+// TestFCMSenderSend verifies FCM sender send behavior.
+func TestFCMSenderSend(t *testing.T) {
+	privateKey, publicKey := testRSAKey(t)
+	tokenRequests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			_ = r.ParseForm()
+			if r.Form.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+				t.Errorf("unexpected grant type: %s", r.Form.Get("grant_type"))
+			}
+			claims := jwt.MapClaims{}
+			if _, err := jwt.ParseWithClaims(r.Form.Get("assertion"), claims, func(*jwt.Token) (interface{}, error) {
+				return publicKey, nil
+			}); err != nil {
+				t.Errorf("invalid assertion: %v", err)
+			}
+			if claims["iss"] != "push@example.iam.gserviceaccount.com" || claims["scope"] != fcmScope {
+				t.Errorf("unexpected claims: %v", claims)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access", "expires_in": 3600})
+		case "/v1/projects/demo/messages:send":
+			if got := r.Header.Get("Authorization"); got != "Bearer access" {
+				t.Errorf("unexpected auth header: %s", got)
+			}
+			var req fcmRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Errorf("decode request: %v", err)
+			}
+			if req.Message.Token == "gone-token" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"error":{"code":404,"status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			if req.Message.Token == "busy-token" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"error":{"code":503,"status":"UNAVAILABLE"}}`))
+				return
+			}
+			if req.Message.Notification.Title != "Title" || req.Message.Data["eventId"] != "7" {
+				t.Errorf("unexpected message: %+v", req.Message)
+			}
+			_, _ = w.Write([]byte(`{"name":"projects/demo/messages/1"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	sender, err := NewFCMSender(FCMConfig{
+		ProjectID:   "demo",
+		ClientEmail: "push@example.iam.gserviceaccount.com",
+		PrivateKey:  privateKey,
+		TokenURL:    srv.URL + "/token",
+		BaseURL:     srv.URL,
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("new sender: %v", err)
+	}
+	msg := Message{Title: "Title", Body: "Body", Data: map[string]string{"eventId": "7"}}
+	if err := sender.Send(context.Background(), "device-token", msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := sender.Send(context.Background(), "gone-token", msg); !errors.Is(err, ErrUnregistered) {
+		t.Fatalf("expected unregistered error, got %v", err)
+	}
+	err = sender.Send(context.Background(), "busy-token", msg)
+	if err == nil || errors.Is(err, ErrUnregistered) {
+		t.Fatalf("expected transient error, got %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Fatalf("expected cached access token, got %d token requests", tokenRequests)
+	}
+}
+
+// TestLoadFCMConfig verifies load FCM config behavior.
+func TestLoadFCMConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "service-account.json")
+	raw := `{"type":"service_account","project_id":"demo","client_email":"push@example.com","private_key":"key","token_uri":"https://oauth2.googleapis.com/token"}`
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+	cfg, err := LoadFCMConfig(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.ProjectID != "demo" || cfg.ClientEmail != "push@example.com" || cfg.PrivateKey != "key" || cfg.TokenURL == "" {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+// TestAPNsSenderSend verifies APNs sender send behavior.
+func TestAPNsSenderSend(t *testing.T) {
+	privateKey, publicKey := testECKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "bearer ") {
+			t.Errorf("unexpected auth header: %s", auth)
+		}
+		claims := jwt.MapClaims{}
+		token, err := jwt.ParseWithClaims(strings.TrimPrefix(auth, "bearer "), claims, func(*jwt.Token) (interface{}, error) {
+			return publicKey, nil
+		})
+		if err != nil || token.Header["kid"] != "KEY123" || claims["iss"] != "TEAM123" {
+			t.Errorf("unexpected provider token: %v %v %v", err, token.Header, claims)
+		}
+		if r.Header.Get("apns-topic") != "fun.spacefestival.app" || r.Header.Get("apns-push-type") != "alert" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		switch r.URL.Path {
+		case "/3/device/device-token":
+			var body map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Errorf("decode body: %v", err)
+			}
+			alert, _ := body["aps"].(map[string]interface{})["alert"].(map[string]interface{})
+			if alert["title"] != "Title" || body["eventId"] != "7" {
+				t.Errorf("unexpected body: %v", body)
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/3/device/gone-token":
+			w.WriteHeader(http.StatusGone)
+			_, _ = w.Write([]byte(`{"reason":"Unregistered","timestamp":1700000000000}`))
+		case "/3/device/bad-topic":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"reason":"TopicDisallowed"}`))
+		default:
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	sender, err := NewAPNsSender(APNsConfig{
+		KeyID:      "KEY123",
+		TeamID:     "TEAM123",
+		BundleID:   "fun.spacefestival.app",
+		PrivateKey: privateKey,
+		BaseURL:    srv.URL,
+	}, srv.Client())
+	if err != nil {
+		t.Fatalf("new sender: %v", err)
+	}
+	msg := Message{Title: "Title", Body: "Body", Data: map[string]string{"eventId": "7"}}
+	if err := sender.Send(context.Background(), "device-token", msg); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if err := sender.Send(context.Background(), "gone-token", msg); !errors.Is(err, ErrUnregistered) {
+		t.Fatalf("expected unregistered error, got %v", err)
+	}
+	var apiErr *APIError
+	err = sender.Send(context.Background(), "bad-topic", msg)
+	if !errors.As(err, &apiErr) || apiErr.Reason != "TopicDisallowed" || errors.Is(err, ErrUnregistered) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// decryptWebPush decrypts an aes128gcm body the way a browser does.
+func decryptWebPush(t *testing.T, uaPrivate *ecdh.PrivateKey, authSecret, body []byte) []byte {
+	t.Helper()
+	salt := body[:16]
+	keyLen := int(body[20])
+	asPublicBytes := body[21 : 21+keyLen]
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		t.Fatalf("invalid sender key: %v", err)
+	}
+	shared, err := uaPrivate.ECDH(asPublic)
+	if err != nil {
+		t.Fatalf("ecdh: %v", err)
+	}
+	keyInfo := append([]byte("WebPush: info\x00"), uaPrivate.PublicKey().Bytes()...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm, _ := hkdfBytes(shared, authSecret, keyInfo, 32)
+	cek, _ := hkdfBytes(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce, _ := hkdfBytes(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plaintext, err := gcm.Open(nil, nonce, body[21+keyLen:], nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if plaintext[len(plaintext)-1] != 0x02 {
+		t.Fatalf("missing record delimiter")
+	}
+	return plaintext[:len(plaintext)-1]
+}
+
+// TestWebPushSenderSend verifies web push sender send behavior.
+func TestWebPushSenderSend(t *testing.T) {
+	vapidKey, vapidPublic := testECKey(t)
+	uaPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ua key: %v", err)
+	}
+	authSecret := []byte("0123456789abcdef")
+	var received []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") == "" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		auth := r.Header.Get("Authorization")
+		token, _, ok := strings.Cut(strings.TrimPrefix(auth, "vapid t="), ", k=")
+		if !ok {
+			t.Errorf("unexpected auth header: %s", auth)
+		}
+		claims := jwt.MapClaims{}
+		if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+			return vapidPublic, nil
+		}); err != nil {
+			t.Errorf("invalid vapid token: %v", err)
+		}
+		if !strings.HasPrefix(claims["aud"].(string), "http://127.0.0.1") || claims["sub"] != "mailto:ops@example.com" {
+			t.Errorf("unexpected claims: %v", claims)
+		}
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		received, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	sender, err := NewWebPushSender(WebPushConfig{PrivateKey: vapidKey, Subject: "mailto:ops@example.com"}, srv.Client())
+	if err != nil {
+		t.Fatalf("new sender: %v", err)
+	}
+	sub := WebPushSubscription{
+		Endpoint: srv.URL + "/push/abc",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaPrivate.PublicKey().Bytes()),
+		Auth:     base64.URLEncoding.EncodeToString(authSecret),
+	}
+	if err := sender.Send(context.Background(), sub, Message{Title: "Title", Body: "Body"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(decryptWebPush(t, uaPrivate, authSecret, received), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["title"] != "Title" || payload["body"] != "Body" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+
+	sub.Endpoint = srv.URL + "/gone"
+	if err := sender.Send(context.Background(), sub, Message{Title: "Title"}); !errors.Is(err, ErrUnregistered) {
+		t.Fatalf("expected unregistered error, got %v", err)
+	}
+}
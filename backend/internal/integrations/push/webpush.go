@@ -0,0 +1,220 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	webPushRecordSize = 4096
+	webPushTTL        = 24 * time.Hour
+)
+
+// WebPushSubscription represents a browser push subscription. P256dh and Auth
+// are the base64url keys of the subscription.
+type WebPushSubscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// WebPushConfig represents VAPID settings. PrivateKey is the PEM contents of
+// the P-256 application server key; Subject is a mailto: or https: contact.
+type WebPushConfig struct {
+	PrivateKey string
+	Subject    string
+}
+
+// WebPushSender sends encrypted Web Push messages with VAPID authentication.
+type WebPushSender struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+	subject   string
+	client    *http.Client
+	now       func() time.Time
+}
+
+// NewWebPushSender creates a Web Push sender.
+func NewWebPushSender(cfg WebPushConfig, client *http.Client) (*WebPushSender, error) {
+	if strings.TrimSpace(cfg.Subject) == "" {
+		return nil, fmt.Errorf("web push subject is required")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(cfg.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("parse vapid private key: %w", err)
+	}
+	public, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, fmt.Errorf("vapid public key: %w", err)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebPushSender{
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(public.Bytes()),
+		subject:   strings.TrimSpace(cfg.Subject),
+		client:    client,
+		now:       time.Now,
+	}, nil
+}
+
+// PublicKey returns the base64url VAPID public key browsers subscribe with.
+func (s *WebPushSender) PublicKey() string {
+	return s.publicKey
+}
+
+// Send encrypts msg as JSON for the subscription and posts it to its endpoint.
+func (s *WebPushSender) Send(ctx context.Context, sub WebPushSubscription, msg Message) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return fmt.Errorf("invalid web push endpoint")
+	}
+	plaintext, err := json.Marshal(map[string]interface{}{
+		"title": msg.Title,
+		"body":  msg.Body,
+		"data":  msg.Data,
+	})
+	if err != nil {
+		return err
+	}
+	body, err := encryptWebPush(sub, plaintext)
+	if err != nil {
+		return err
+	}
+	now := s.now()
+	vapid, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": now.Add(12 * time.Hour).Unix(),
+		"sub": s.subject,
+	}).SignedString(s.key)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", vapid, s.publicKey))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", fmt.Sprintf("%d", int(webPushTTL.Seconds())))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return &APIError{
+		Provider:     "webpush",
+		StatusCode:   resp.StatusCode,
+		Body:         strings.TrimSpace(string(respBody)),
+		Unregistered: resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone,
+	}
+}
+
+// encryptWebPush encrypts plaintext for the subscription with the aes128gcm
+// content encoding of RFC 8291 as a single record.
+func encryptWebPush(sub WebPushSubscription, plaintext []byte) ([]byte, error) {
+	uaPublicBytes, err := decodeWebPushKey(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	authSecret, err := decodeWebPushKey(sub.Auth)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auth secret: %w", err)
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid p256dh key: %w", err)
+	}
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+	asPublicBytes := asPrivate.PublicKey().Bytes()
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublicBytes...)
+	keyInfo = append(keyInfo, asPublicBytes...)
+	ikm, err := hkdfBytes(sharedSecret, authSecret, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdfBytes(ikm, salt, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdfBytes(ikm, salt, []byte("Content-Encoding: nonce\x00"), 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	// A single record ends with the 0x02 padding delimiter.
+	record := append(append([]byte{}, plaintext...), 0x02)
+	if len(record)+gcm.Overhead() > webPushRecordSize {
+		return nil, fmt.Errorf("web push payload too large")
+	}
+
+	header := make([]byte, 0, 16+4+1+len(asPublicBytes))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublicBytes)))
+	header = append(header, asPublicBytes...)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// hkdfBytes derives length bytes with HKDF-SHA-256.
+func hkdfBytes(secret, salt, info []byte, length int) ([]byte, error) {
+	out := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, info), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// decodeWebPushKey decodes a base64url key with or without padding.
+func decodeWebPushKey(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(strings.TrimSpace(value), "="))
+}
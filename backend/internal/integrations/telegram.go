@@ -3,19 +3,75 @@ package integrations
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
-// TelegramClient represents telegram client.
+// maxTelegramRetryWait is the longest a request waits out a flood limit;
+// longer pauses fail fast with the remaining retry_after.
+const maxTelegramRetryWait = 30 * time.Second
+
+// TelegramClient represents telegram client. A 429 response pauses every
+// request of the client until its retry_after has passed.
 type TelegramClient struct {
-	token  string
-	client *http.Client
+	token   string
+	baseURL string
+	client  *http.Client
+	now     func() time.Time
+	sleep   func(time.Duration)
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// TelegramAPIError is a failed Bot API call. RetryAfter is set for flood
+// limits (429).
+type TelegramAPIError struct {
+	Method      string
+	StatusCode  int
+	Description string
+	RetryAfter  time.Duration
+}
+
+// Error handles internal error behavior.
+func (e *TelegramAPIError) Error() string {
+	if e.Description == "" {
+		return fmt.Sprintf("telegram %s status %d", e.Method, e.StatusCode)
+	}
+	return fmt.Sprintf("telegram %s status %d: %s", e.Method, e.StatusCode, e.Description)
+}
+
+// Unreachable reports whether the chat can't receive bot messages: the user
+// blocked the bot, deleted the account or never started it.
+func (e *TelegramAPIError) Unreachable() bool {
+	if e.StatusCode == http.StatusForbidden {
+		return true
+	}
+	description := strings.ToLower(e.Description)
+	return e.StatusCode == http.StatusBadRequest &&
+		(strings.Contains(description, "chat not found") || strings.Contains(description, "user not found"))
+}
+
+// IsTelegramUnreachable reports whether err means the chat can't receive bot messages.
+func IsTelegramUnreachable(err error) bool {
+	var apiErr *TelegramAPIError
+	return errors.As(err, &apiErr) && apiErr.Unreachable()
+}
+
+// TelegramRetryAfter returns the flood limit pause of err, if any.
+func TelegramRetryAfter(err error) (time.Duration, bool) {
+	var apiErr *TelegramAPIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > 0 {
+		return apiErr.RetryAfter, true
+	}
+	return 0, false
 }
 
 // WebAppInfo represents web app info.
@@ -39,14 +95,27 @@ type CopyTextButton struct {
 
 // ReplyMarkup represents reply markup.
 type ReplyMarkup struct {
-	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+	InlineKeyboard  [][]InlineKeyboardButton `json:"inline_keyboard,omitempty"`
+	Keyboard        [][]KeyboardButton       `json:"keyboard,omitempty"`
+	ResizeKeyboard  bool                     `json:"resize_keyboard,omitempty"`
+	OneTimeKeyboard bool                     `json:"one_time_keyboard,omitempty"`
+	RemoveKeyboard  bool                     `json:"remove_keyboard,omitempty"`
+}
+
+// KeyboardButton represents a reply keyboard button.
+type KeyboardButton struct {
+	Text           string `json:"text"`
+	RequestContact bool   `json:"request_contact,omitempty"`
 }
 
 // NewTelegramClient creates telegram client.
 func NewTelegramClient(token string) *TelegramClient {
 	return &TelegramClient{
-		token:  token,
-		client: &http.Client{Timeout: 10 * time.Second},
+		token:   token,
+		baseURL: "https://api.telegram.org",
+		client:  &http.Client{Timeout: 10 * time.Second},
+		now:     time.Now,
+		sleep:   time.Sleep,
 	}
 }
 
@@ -120,23 +189,7 @@ func (t *TelegramClient) SendPhotoBytes(chatID int64, filename string, photo []b
 	if err := writer.Close(); err != nil {
 		return err
 	}
-
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/sendPhoto", t.token)
-	req, err := http.NewRequest(http.MethodPost, url, &body)
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	resp, err := t.client.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	_, _ = io.Copy(io.Discard, resp.Body)
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("telegram sendPhoto status %d", resp.StatusCode)
-	}
-	return nil
+	return t.do("sendPhoto", writer.FormDataContentType(), body.Bytes())
 }
 
 // AnswerCallbackQuery handles answer callback query.
@@ -155,18 +208,95 @@ func (t *TelegramClient) AnswerCallbackQuery(callbackQueryID string, text string
 	return t.post("answerCallbackQuery", payload)
 }
 
+// SetWebhook registers the webhook URL with its secret token and the update
+// types Telegram should deliver.
+func (t *TelegramClient) SetWebhook(webhookURL, secret string, allowedUpdates []string, dropPending bool) error {
+	payload := map[string]interface{}{
+		"url":                  webhookURL,
+		"allowed_updates":      allowedUpdates,
+		"drop_pending_updates": dropPending,
+	}
+	if secret != "" {
+		payload["secret_token"] = secret
+	}
+	return t.post("setWebhook", payload)
+}
+
 // post handles internal post behavior.
 func (t *TelegramClient) post(method string, payload map[string]interface{}) error {
 	body, _ := json.Marshal(payload)
-	url := fmt.Sprintf("https://api.telegram.org/bot%s/%s", t.token, method)
-	resp, err := t.client.Post(url, "application/json", bytes.NewReader(body))
+	return t.do(method, "application/json", body)
+}
+
+// do calls a Bot API method, waiting out the client's flood pause first. A
+// 429 pauses the client and the call is repeated once.
+func (t *TelegramClient) do(method, contentType string, body []byte) error {
+	for attempt := 0; ; attempt++ {
+		if err := t.waitForPause(method); err != nil {
+			return err
+		}
+		err := t.send(method, contentType, body)
+		retryAfter, limited := TelegramRetryAfter(err)
+		if !limited || attempt > 0 {
+			return err
+		}
+		t.pause(retryAfter)
+	}
+}
+
+// send posts one request and converts error responses into TelegramAPIError.
+func (t *TelegramClient) send(method, contentType string, body []byte) error {
+	url := fmt.Sprintf("%s/bot%s/%s", t.baseURL, t.token, method)
+	resp, err := t.client.Post(url, contentType, bytes.NewReader(body))
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	_, _ = io.Copy(io.Discard, resp.Body)
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("telegram %s status %d", method, resp.StatusCode)
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	var parsed struct {
+		Description string `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	_ = json.Unmarshal(respBody, &parsed)
+	return &TelegramAPIError{
+		Method:      method,
+		StatusCode:  resp.StatusCode,
+		Description: parsed.Description,
+		RetryAfter:  time.Duration(parsed.Parameters.RetryAfter) * time.Second,
+	}
+}
+
+// pause holds back every request of the client for d.
+func (t *TelegramClient) pause(d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if until := t.now().Add(d); until.After(t.pausedUntil) {
+		t.pausedUntil = until
+	}
+}
+
+// waitForPause sleeps until the flood pause ends. Pauses longer than
+// maxTelegramRetryWait fail with a 429 error carrying the remaining time.
+func (t *TelegramClient) waitForPause(method string) error {
+	t.mu.Lock()
+	remaining := t.pausedUntil.Sub(t.now())
+	t.mu.Unlock()
+	if remaining <= 0 {
+		return nil
+	}
+	if remaining > maxTelegramRetryWait {
+		return &TelegramAPIError{
+			Method:      method,
+			StatusCode:  http.StatusTooManyRequests,
+			Description: "flood limit pause",
+			RetryAfter:  remaining,
+		}
 	}
+	t.sleep(remaining)
 	return nil
 }
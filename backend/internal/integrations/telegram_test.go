@@ -0,0 +1,114 @@
+package integrations
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestTelegramClient creates a client for the fake Bot API with a fake clock.
+func newTestTelegramClient(baseURL string, now *time.Time, slept *[]time.Duration) *TelegramClient {
+	client := NewTelegramClient("TOKEN")
+	client.baseURL = baseURL
+	client.now = func() time.Time { return *now }
+	client.sleep = func(d time.Duration) {
+		*slept = append(*slept, d)
+		*now = now.Add(d)
+	}
+	return client
+}
+
+// TestTelegramClientTypedErrors verifies telegram client typed errors behavior.
+func TestTelegramClientTypedErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/botTOKEN/sendMessage":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+		case "/botTOKEN/sendPhoto":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: chat not found"}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: query is too old"}`))
+		}
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	client := newTestTelegramClient(srv.URL, &now, &slept)
+
+	err := client.SendMessage(42, "hi")
+	if !IsTelegramUnreachable(err) || err.Error() != "telegram sendMessage status 403: Forbidden: bot was blocked by the user" {
+		t.Fatalf("expected blocked error, got %v", err)
+	}
+	if err := client.SendPhotoWithMarkup(42, "https://example.com/a.jpg", "", nil); !IsTelegramUnreachable(err) {
+		t.Fatalf("expected chat not found to be unreachable, got %v", err)
+	}
+	if err := client.AnswerCallbackQuery("query", ""); err == nil || IsTelegramUnreachable(err) {
+		t.Fatalf("expected a reachable bad request, got %v", err)
+	}
+}
+
+// TestTelegramClientHonorsRetryAfter verifies telegram client honors retry after behavior.
+func TestTelegramClientHonorsRetryAfter(t *testing.T) {
+	retryAfter := "3"
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after ` + retryAfter + `","parameters":{"retry_after":` + retryAfter + `}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	client := newTestTelegramClient(srv.URL, &now, &slept)
+
+	if err := client.SendMessage(42, "hi"); err != nil {
+		t.Fatalf("expected retry to succeed, got %v", err)
+	}
+	if calls != 2 || len(slept) != 1 || slept[0] != 3*time.Second {
+		t.Fatalf("unexpected retry: calls=%d slept=%v", calls, slept)
+	}
+
+	client.pause(time.Minute)
+	err := client.SendMessage(42, "hi")
+	wait, limited := TelegramRetryAfter(err)
+	if !limited || wait != time.Minute || calls != 2 {
+		t.Fatalf("expected long pause to fail fast, got %v calls=%d", err, calls)
+	}
+}
+
+// TestTelegramClientSetWebhook verifies telegram client set webhook behavior.
+func TestTelegramClientSetWebhook(t *testing.T) {
+	var got map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/botTOKEN/setWebhook" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"ok":true,"result":true}`))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC)
+	var slept []time.Duration
+	client := newTestTelegramClient(srv.URL, &now, &slept)
+	if err := client.SetWebhook("https://example.com/telegram/webhook", "s3cret", []string{"message"}, true); err != nil {
+		t.Fatalf("set webhook: %v", err)
+	}
+	if got["url"] != "https://example.com/telegram/webhook" || got["secret_token"] != "s3cret" || got["drop_pending_updates"] != true {
+		t.Fatalf("unexpected payload: %v", got)
+	}
+	if updates, ok := got["allowed_updates"].([]interface{}); !ok || len(updates) != 1 || updates[0] != "message" {
+		t.Fatalf("unexpected allowed updates: %v", got["allowed_updates"])
+	}
+}
@@ -0,0 +1,94 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VKMessagesDenied is the VK API error code of users who did not allow
+// messages from the community.
+const VKMessagesDenied = 901
+
+// VKAPIError represents an error returned by a VK API method.
+type VKAPIError struct {
+	Code    int
+	Message string
+}
+
+// Error handles internal error behavior.
+func (e *VKAPIError) Error() string {
+	return fmt.Sprintf("vk api error %d: %s", e.Code, strings.TrimSpace(e.Message))
+}
+
+// VKMessenger sends messages on behalf of a VK community.
+type VKMessenger struct {
+	client  *http.Client
+	token   string
+	baseURL string
+	version string
+}
+
+// NewVKMessenger creates a messenger authorized with a community access token.
+func NewVKMessenger(groupToken string) *VKMessenger {
+	return &VKMessenger{
+		client:  &http.Client{Timeout: 10 * time.Second},
+		token:   strings.TrimSpace(groupToken),
+		baseURL: defaultVKAPIBaseURL,
+		version: defaultVKAPIVersion,
+	}
+}
+
+// SendMessage sends a text message to a VK user. randomID deduplicates
+// retries of the same message.
+func (m *VKMessenger) SendMessage(ctx context.Context, userID int64, text string, randomID int64) error {
+	if m.token == "" {
+		return fmt.Errorf("vk group token is empty")
+	}
+	form := url.Values{}
+	form.Set("user_id", strconv.FormatInt(userID, 10))
+	form.Set("random_id", strconv.FormatInt(randomID, 10))
+	form.Set("message", text)
+	form.Set("access_token", m.token)
+	form.Set("v", m.version)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(m.baseURL, "/")+"/method/messages.send", strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("vk request build failed: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("vk request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("vk response read failed: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("vk messages.send status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		Error *struct {
+			Code    int    `json:"error_code"`
+			Message string `json:"error_msg"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return fmt.Errorf("vk response decode failed: %w", err)
+	}
+	if payload.Error != nil {
+		return &VKAPIError{Code: payload.Error.Code, Message: payload.Error.Message}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package integrations
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVKMessengerSendMessage verifies v k messenger send message behavior.
+func TestVKMessengerSendMessage(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/method/messages.send" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		if r.PostForm.Get("access_token") != "group-token" || r.PostForm.Get("random_id") != "77" {
+			t.Errorf("unexpected form: %v", r.PostForm)
+		}
+		if r.PostForm.Get("user_id") == "2" {
+			_, _ = w.Write([]byte(`{"error":{"error_code":901,"error_msg":"Can't send messages for users without permission"}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"response":123}`))
+	}))
+	defer server.Close()
+
+	messenger := NewVKMessenger("group-token")
+	messenger.baseURL = server.URL
+	if err := messenger.SendMessage(context.Background(), 1, "Привет", 77); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	var apiErr *VKAPIError
+	err := messenger.SendMessage(context.Background(), 2, "Привет", 77)
+	if !errors.As(err, &apiErr) || apiErr.Code != VKMessagesDenied {
+		t.Fatalf("SendMessage() error = %v, want code %d", err, VKMessagesDenied)
+	}
+}
+
+// TestMailerSend verifies mailer send behavior.
+func TestMailerSend(t *testing.T) {
+	t.Parallel()
+
+	mailer := NewMailer(SMTPConfig{Host: "smtp.example.com", From: "noreply@example.com"})
+	mailer.now = func() time.Time { return time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC) }
+	var gotAddr string
+	var gotMsg []byte
+	mailer.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr = addr
+		gotMsg = msg
+		if a != nil || from != "noreply@example.com" || len(to) != 1 || to[0] != "user@example.com" {
+			t.Errorf("unexpected envelope: %v %s %v", a, from, to)
+		}
+		return nil
+	}
+	if err := mailer.Send(context.Background(), "user@example.com", "Новое событие", "Rave\nClub"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" {
+		t.Fatalf("unexpected addr: %s", gotAddr)
+	}
+	headers, body, _ := strings.Cut(string(gotMsg), "\r\n\r\n")
+	if !strings.Contains(headers, "Subject: =?utf-8?q?") || !strings.Contains(headers, "To: user@example.com") {
+		t.Fatalf("unexpected headers: %s", headers)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil || string(decoded) != "Rave\r\nClub" {
+		t.Fatalf("unexpected body: %q %v", decoded, err)
+	}
+}
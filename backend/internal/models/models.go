@@ -31,6 +31,14 @@ type UserPushToken struct {
 	UpdatedAt  time.Time `json:"updatedAt"`
 }
 
+// WebPushSubscription represents a browser Web Push subscription.
+type WebPushSubscription struct {
+	UserID   int64  `json:"userId"`
+	Endpoint string `json:"endpoint"`
+	P256dh   string `json:"p256dh"`
+	Auth     string `json:"auth"`
+}
+
 // Event represents event.
 type Event struct {
 	ID                 int64      `json:"id"`
@@ -62,17 +70,84 @@ type Event struct {
 	LikesCount         int        `json:"likesCount"`
 	CommentsCount      int        `json:"commentsCount"`
 	IsLiked            bool       `json:"isLiked,omitempty"`
+	FriendsGoing       []Friend   `json:"friendsGoing,omitempty"`
+	FriendsGoingCount  int        `json:"friendsGoingCount,omitempty"`
+	SeriesID           *int64     `json:"seriesId,omitempty"`
+	SeriesIndex        *int       `json:"seriesIndex,omitempty"`
+	IsSeriesException  bool       `json:"isSeriesException,omitempty"`
+	RecurrenceRule     string     `json:"recurrenceRule,omitempty"`
+	Timezone           string     `json:"timezone,omitempty"`
+	Revision           int        `json:"revision"`
+	Status             string     `json:"status,omitempty"`
+	PublishAt          *time.Time `json:"publishAt,omitempty"`
+	CanceledAt         *time.Time `json:"canceledAt,omitempty"`
+	CancelReason       string     `json:"cancelReason,omitempty"`
 	CreatedAt          time.Time  `json:"createdAt"`
 	UpdatedAt          time.Time  `json:"updatedAt"`
 }
 
-// UserEvent represents user event.
-type UserEvent struct {
+const (
+	EventStatusDraft     = "draft"
+	EventStatusPublished = "published"
+	EventStatusCanceled  = "canceled"
+)
+
+// IsDraft reports whether the event is not published yet.
+func (e Event) IsDraft() bool {
+	return e.Status == EventStatusDraft
+}
+
+// IsCanceled reports whether the event was canceled by its organizer.
+func (e Event) IsCanceled() bool {
+	return e.Status == EventStatusCanceled
+}
+
+// EventSeries represents a recurring event series.
+type EventSeries struct {
 	ID                int64     `json:"id"`
-	Title             string    `json:"title"`
+	CreatorUserID     int64     `json:"creatorUserId"`
+	RRule             string    `json:"rrule"`
+	Timezone          string    `json:"timezone"`
 	StartsAt          time.Time `json:"startsAt"`
-	ParticipantsCount int       `json:"participantsCount"`
-	ThumbnailURL      string    `json:"thumbnailUrl,omitempty"`
+	DurationSeconds   *int      `json:"durationSeconds,omitempty"`
+	MaterializedUntil time.Time `json:"materializedUntil"`
+	MaterializedCount int       `json:"materializedCount"`
+	IsActive          bool      `json:"isActive"`
+	CreatedAt         time.Time `json:"createdAt"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// Event staff roles.
+const (
+	EventRoleOwner   = "owner"
+	EventRoleCoHost  = "cohost"
+	EventRoleCashier = "cashier"
+	EventRoleScanner = "scanner"
+)
+
+// EventStaffMember represents a user with a scoped role on an event.
+type EventStaffMember struct {
+	EventID   int64     `json:"eventId"`
+	UserID    int64     `json:"userId"`
+	Role      string    `json:"role"`
+	Username  string    `json:"username,omitempty"`
+	FirstName string    `json:"firstName"`
+	LastName  string    `json:"lastName,omitempty"`
+	PhotoURL  string    `json:"photoUrl,omitempty"`
+	InvitedBy *int64    `json:"invitedBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// UserEvent represents user event.
+type UserEvent struct {
+	ID                int64      `json:"id"`
+	Title             string     `json:"title"`
+	StartsAt          time.Time  `json:"startsAt"`
+	ParticipantsCount int        `json:"participantsCount"`
+	ThumbnailURL      string     `json:"thumbnailUrl,omitempty"`
+	Status            string     `json:"status,omitempty"`
+	PublishAt         *time.Time `json:"publishAt,omitempty"`
 }
 
 // LandingContent represents landing content.
@@ -102,6 +177,24 @@ type EventMarker struct {
 	Filters    []string  `json:"filters,omitempty"`
 }
 
+// MapCluster represents an aggregated group of events in a map grid cell.
+type MapCluster struct {
+	Lat        float64  `json:"lat"`
+	Lng        float64  `json:"lng"`
+	Count      int      `json:"count"`
+	IsPromoted bool     `json:"isPromoted"`
+	TopFilters []string `json:"topFilters,omitempty"`
+	EventID    *int64   `json:"eventId,omitempty"`
+}
+
+// BoundingBox represents a lng/lat rectangle.
+type BoundingBox struct {
+	MinLng float64 `json:"minLng"`
+	MinLat float64 `json:"minLat"`
+	MaxLng float64 `json:"maxLng"`
+	MaxLat float64 `json:"maxLat"`
+}
+
 // Participant represents participant.
 type Participant struct {
 	UserID   int64     `json:"userId"`
@@ -109,6 +202,160 @@ type Participant struct {
 	JoinedAt time.Time `json:"joinedAt"`
 }
 
+// Friend represents a user who shares contacts with the current user both ways.
+type Friend struct {
+	UserID   int64  `json:"userId"`
+	Name     string `json:"name"`
+	Username string `json:"username,omitempty"`
+	PhotoURL string `json:"photoUrl,omitempty"`
+}
+
+// PublicProfile represents the public profile of a user with follower counters.
+type PublicProfile struct {
+	ID             int64   `json:"id"`
+	Username       string  `json:"username,omitempty"`
+	FirstName      string  `json:"firstName"`
+	LastName       string  `json:"lastName,omitempty"`
+	PhotoURL       string  `json:"photoUrl,omitempty"`
+	Rating         float64 `json:"rating"`
+	RatingCount    int     `json:"ratingCount"`
+	FollowersCount int     `json:"followersCount"`
+	FollowingCount int     `json:"followingCount"`
+	EventsCount    int     `json:"eventsCount"`
+	IsFollowing    bool    `json:"isFollowing"`
+}
+
+// FollowedUser represents an organizer the current user follows.
+type FollowedUser struct {
+	UserID         int64     `json:"userId"`
+	Name           string    `json:"name"`
+	Username       string    `json:"username,omitempty"`
+	PhotoURL       string    `json:"photoUrl,omitempty"`
+	FollowersCount int       `json:"followersCount"`
+	FollowedAt     time.Time `json:"followedAt"`
+}
+
+// NotificationSettings represents notification settings of the current user.
+// Quiet hours and the digest time are "HH:MM" in Timezone, quiet hours are
+// empty when disabled; reminder offsets are minutes before the event start.
+// DigestWeekday is an ISO weekday (1 is Monday) and 0 when the weekly digest
+// is off. Email is the confirmed address; PendingEmail waits for the link
+// sent to it.
+type NotificationSettings struct {
+	AllNewEvents    bool     `json:"allNewEvents"`
+	Muted           []string `json:"muted"`
+	ReminderOffsets []int    `json:"reminderOffsets"`
+	QuietHoursStart string   `json:"quietHoursStart,omitempty"`
+	QuietHoursEnd   string   `json:"quietHoursEnd,omitempty"`
+	Timezone        string   `json:"timezone,omitempty"`
+	DigestWeekday   int      `json:"digestWeekday"`
+	DigestTime      string   `json:"digestTime"`
+	Channels        []string `json:"channels"`
+	Email           string   `json:"email,omitempty"`
+	PendingEmail    string   `json:"pendingEmail,omitempty"`
+}
+
+// DefaultTimezone is the timezone of users who never set one; most of the
+// audience is in Moscow.
+const DefaultTimezone = "Europe/Moscow"
+
+const (
+	NotificationCategoryNewEvents    = "new_events"
+	NotificationCategoryFollowed     = "followed"
+	NotificationCategoryJoined       = "joined"
+	NotificationCategoryReminders    = "reminders"
+	NotificationCategoryComments     = "comments"
+	NotificationCategoryEventChanges = "event_changes"
+	NotificationCategoryReviews      = "reviews"
+	NotificationCategoryPayments     = "payments"
+	NotificationCategoryReports      = "reports"
+	NotificationCategorySavedSearch  = "saved_searches"
+	NotificationCategoryDigest       = "digest"
+)
+
+// NotificationCategories lists categories users can mute.
+var NotificationCategories = []string{
+	NotificationCategoryNewEvents,
+	NotificationCategoryFollowed,
+	NotificationCategoryJoined,
+	NotificationCategoryReminders,
+	NotificationCategoryComments,
+	NotificationCategoryEventChanges,
+	NotificationCategoryReviews,
+	NotificationCategoryPayments,
+	NotificationCategoryReports,
+	NotificationCategorySavedSearch,
+	NotificationCategoryDigest,
+}
+
+const (
+	NotificationChannelTelegram = "telegram"
+	NotificationChannelPush     = "push"
+	NotificationChannelWebPush  = "web_push"
+	NotificationChannelVK       = "vk"
+	NotificationChannelEmail    = "email"
+)
+
+// NotificationChannels lists delivery channels in the default routing order.
+var NotificationChannels = []string{
+	NotificationChannelTelegram,
+	NotificationChannelPush,
+	NotificationChannelWebPush,
+	NotificationChannelVK,
+	NotificationChannelEmail,
+}
+
+const (
+	DeliveryStatusSent    = "sent"
+	DeliveryStatusFailed  = "failed"
+	DeliveryStatusSkipped = "skipped"
+)
+
+// NotificationDelivery records one delivery attempt of a notification job over a channel.
+type NotificationDelivery struct {
+	Channel string    `json:"channel"`
+	Status  string    `json:"status"`
+	Error   string    `json:"error,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+// NotificationCategory returns the mutable category of a notification job kind,
+// or an empty string when the kind can't be muted.
+func NotificationCategory(kind string) string {
+	switch kind {
+	case "event_created", "event_nearby":
+		return NotificationCategoryNewEvents
+	case "event_followed":
+		return NotificationCategoryFollowed
+	case "joined":
+		return NotificationCategoryJoined
+	case "reminder", "reminder_60m":
+		return NotificationCategoryReminders
+	case "comment_added", "comment_reply", "comment_mention":
+		return NotificationCategoryComments
+	case "event_updated", "event_rescheduled", "event_canceled":
+		return NotificationCategoryEventChanges
+	case "review_request":
+		return NotificationCategoryReviews
+	case "payment_confirmed":
+		return NotificationCategoryPayments
+	case "report_resolved":
+		return NotificationCategoryReports
+	case "saved_search_alert":
+		return NotificationCategorySavedSearch
+	case "weekly_digest":
+		return NotificationCategoryDigest
+	default:
+		return ""
+	}
+}
+
+// PrivacySettings represents privacy settings of the current user.
+type PrivacySettings struct {
+	HideAttendance bool `json:"hideAttendance"`
+	HasPhone       bool `json:"hasPhone"`
+}
+
 // NotificationJob represents notification job.
 type NotificationJob struct {
 	ID        int64                  `json:"id"`
@@ -122,14 +369,54 @@ type NotificationJob struct {
 	LastError string                 `json:"lastError,omitempty"`
 }
 
+// AdminNotificationJob represents a notification job with its delivery
+// attempts as shown to admins.
+type AdminNotificationJob struct {
+	NotificationJob
+	Deliveries []NotificationDelivery `json:"deliveries"`
+	CreatedAt  time.Time              `json:"createdAt"`
+	UpdatedAt  time.Time              `json:"updatedAt"`
+}
+
 // EventComment represents event comment.
 type EventComment struct {
-	ID        int64     `json:"id"`
-	EventID   int64     `json:"eventId"`
-	UserID    int64     `json:"userId"`
-	UserName  string    `json:"userName"`
-	Body      string    `json:"body"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID        int64             `json:"id"`
+	EventID   int64             `json:"eventId"`
+	ParentID  *int64            `json:"parentId,omitempty"`
+	UserID    int64             `json:"userId"`
+	UserName  string            `json:"userName"`
+	Body      string            `json:"body"`
+	Reactions []CommentReaction `json:"reactions"`
+	CreatedAt time.Time         `json:"createdAt"`
+	EditedAt  *time.Time        `json:"editedAt,omitempty"`
+}
+
+// CommentReaction represents the count of one emoji on a comment.
+type CommentReaction struct {
+	Emoji   string `json:"emoji"`
+	Count   int    `json:"count"`
+	Reacted bool   `json:"reacted,omitempty"`
+}
+
+// EventReview represents a participant's rating and review of an ended event.
+type EventReview struct {
+	ID           int64     `json:"id"`
+	EventID      int64     `json:"eventId"`
+	EventTitle   string    `json:"eventTitle,omitempty"`
+	UserID       int64     `json:"userId"`
+	UserName     string    `json:"userName"`
+	Rating       int       `json:"rating"`
+	Body         string    `json:"body,omitempty"`
+	IsHidden     bool      `json:"isHidden"`
+	HiddenReason string    `json:"hiddenReason,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// EventReviewSummary represents aggregated ratings of an event.
+type EventReviewSummary struct {
+	Average float64 `json:"average"`
+	Count   int     `json:"count"`
 }
 
 // AdminUser represents admin user.
@@ -227,3 +514,107 @@ type AdminParsedEvent struct {
 	CreatedAt       time.Time  `json:"createdAt"`
 	UpdatedAt       time.Time  `json:"updatedAt"`
 }
+
+const (
+	ReportTargetEvent   = "event"
+	ReportTargetComment = "comment"
+	ReportTargetUser    = "user"
+
+	ReportStatusOpen      = "open"
+	ReportStatusActioned  = "actioned"
+	ReportStatusDismissed = "dismissed"
+)
+
+// ReportReasons lists accepted report reason categories.
+var ReportReasons = []string{"spam", "abuse", "fraud", "inappropriate", "other"}
+
+// ReportedTarget represents reports about one event, comment or user aggregated for moderation.
+type ReportedTarget struct {
+	TargetType     string         `json:"targetType"`
+	TargetID       int64          `json:"targetId"`
+	EventID        *int64         `json:"eventId,omitempty"`
+	Preview        string         `json:"preview"`
+	Status         string         `json:"status"`
+	IsHidden       bool           `json:"isHidden"`
+	AutoHidden     bool           `json:"autoHidden"`
+	ReportsCount   int            `json:"reportsCount"`
+	OpenCount      int            `json:"openCount"`
+	Reasons        map[string]int `json:"reasons"`
+	LastReportedAt time.Time      `json:"lastReportedAt"`
+	ResolvedAt     *time.Time     `json:"resolvedAt,omitempty"`
+	ResolutionNote string         `json:"resolutionNote,omitempty"`
+}
+
+const (
+	ContentStatusPending  = "pending"
+	ContentStatusApproved = "approved"
+	ContentStatusRejected = "rejected"
+)
+
+// ContentFilterEntry records a comment or event held or rejected by the content filter.
+type ContentFilterEntry struct {
+	ID         int64      `json:"id"`
+	Kind       string     `json:"kind"`
+	TargetID   *int64     `json:"targetId,omitempty"`
+	UserID     int64      `json:"userId"`
+	UserName   string     `json:"userName"`
+	EventID    *int64     `json:"eventId,omitempty"`
+	Content    string     `json:"content"`
+	Decision   string     `json:"decision"`
+	Rule       string     `json:"rule"`
+	Match      string     `json:"match,omitempty"`
+	Status     string     `json:"status"`
+	ReviewedBy *int64     `json:"reviewedBy,omitempty"`
+	ReviewedAt *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+const (
+	SavedSearchPriceAny  = "any"
+	SavedSearchPriceFree = "free"
+	SavedSearchPricePaid = "paid"
+)
+
+// SavedSearch represents search criteria a user is alerted about when new
+// events match. Empty criteria match any event.
+type SavedSearch struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Query         string     `json:"query,omitempty"`
+	Filters       []string   `json:"filters"`
+	Lat           *float64   `json:"lat,omitempty"`
+	Lng           *float64   `json:"lng,omitempty"`
+	RadiusMeters  *int       `json:"radiusM,omitempty"`
+	StartsAfter   *time.Time `json:"startsAfter,omitempty"`
+	StartsBefore  *time.Time `json:"startsBefore,omitempty"`
+	WithinDays    *int       `json:"withinDays,omitempty"`
+	Price         string     `json:"price"`
+	AlertsEnabled bool       `json:"alertsEnabled"`
+	DailyLimit    int        `json:"dailyLimit"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+const (
+	BanScopeFull      = "full"
+	BanScopeComments  = "comments"
+	BanScopeEvents    = "events"
+	BanScopePurchases = "purchases"
+)
+
+// BanScopes lists accepted ban scopes.
+var BanScopes = []string{BanScopeFull, BanScopeComments, BanScopeEvents, BanScopePurchases}
+
+// UserBan represents a full or scoped restriction of a user; a nil ExpiresAt means permanent.
+type UserBan struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"userId"`
+	Scope     string     `json:"scope"`
+	Reason    string     `json:"reason,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy *int64     `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	LiftedAt  *time.Time `json:"liftedAt,omitempty"`
+	LiftedBy  *int64     `json:"liftedBy,omitempty"`
+	Active    bool       `json:"active"`
+}
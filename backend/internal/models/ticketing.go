@@ -10,6 +10,12 @@ const (
 	OrderStatusRedeemed  = "REDEEMED"
 )
 
+const (
+	RefundStatusPending  = "pending"
+	RefundStatusRefunded = "refunded"
+	RefundStatusRejected = "rejected"
+)
+
 const (
 	ItemTypeTicket   = "TICKET"
 	ItemTypeTransfer = "TRANSFER"
@@ -359,3 +365,19 @@ type TicketStats struct {
 	Global TicketStatsBreakdown   `json:"global"`
 	Events []TicketStatsBreakdown `json:"events"`
 }
+
+// OrderRefund represents a paid order queued for refund after event cancellation.
+type OrderRefund struct {
+	OrderID     string            `json:"orderId"`
+	EventID     int64             `json:"eventId"`
+	EventTitle  string            `json:"eventTitle,omitempty"`
+	Status      string            `json:"status"`
+	Reason      string            `json:"reason,omitempty"`
+	AmountCents int64             `json:"amountCents"`
+	Currency    string            `json:"currency"`
+	User        *OrderUserSummary `json:"user,omitempty"`
+	ResolvedBy  *int64            `json:"resolvedBy,omitempty"`
+	ResolvedAt  *time.Time        `json:"resolvedAt,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
@@ -0,0 +1,396 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency represents recurrence frequency.
+type Frequency string
+
+const (
+	FrequencyWeekly  Frequency = "WEEKLY"
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+const (
+	maxInterval = 52
+	maxCount    = 365
+	// maxPeriods bounds expansion so a sparse rule can not spin forever.
+	maxPeriods = 1200
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// WeekdayNum represents a BYDAY entry, optionally with a month ordinal (1FR, -1SU).
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Rule represents the supported RRULE subset.
+type Rule struct {
+	Freq     Frequency
+	Interval int
+	ByDay    []WeekdayNum
+	Until    *time.Time
+	Count    int
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "MO",
+	time.Tuesday:   "TU",
+	time.Wednesday: "WE",
+	time.Thursday:  "TH",
+	time.Friday:    "FR",
+	time.Saturday:  "SA",
+	time.Sunday:    "SU",
+}
+
+// Parse parses RRULE text such as "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10".
+func Parse(raw string) (Rule, error) {
+	value := strings.TrimSpace(raw)
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "RRULE:"), "rrule:")
+	if value == "" {
+		return Rule{}, fmt.Errorf("%w: empty", ErrInvalidRule)
+	}
+	rule := Rule{Interval: 1}
+	seen := make(map[string]struct{})
+	for _, part := range strings.Split(value, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.ToUpper(strings.TrimSpace(val))
+		if _, dup := seen[key]; dup {
+			return Rule{}, fmt.Errorf("%w: duplicate %s", ErrInvalidRule, key)
+		}
+		seen[key] = struct{}{}
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%w: interval %q", ErrInvalidRule, val)
+			}
+			rule.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil {
+				return Rule{}, fmt.Errorf("%w: count %q", ErrInvalidRule, val)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.Until = &until
+		case "BYDAY":
+			days, err := parseByDay(val)
+			if err != nil {
+				return Rule{}, err
+			}
+			rule.ByDay = days
+		case "WKST":
+			if val != "MO" {
+				return Rule{}, fmt.Errorf("%w: only WKST=MO is supported", ErrInvalidRule)
+			}
+		default:
+			return Rule{}, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+	if err := rule.Validate(); err != nil {
+		return Rule{}, err
+	}
+	return rule, nil
+}
+
+// Validate validates rule.
+func (r Rule) Validate() error {
+	if r.Freq != FrequencyWeekly && r.Freq != FrequencyMonthly {
+		return fmt.Errorf("%w: FREQ must be WEEKLY or MONTHLY", ErrInvalidRule)
+	}
+	if r.Interval < 1 || r.Interval > maxInterval {
+		return fmt.Errorf("%w: INTERVAL must be between 1 and %d", ErrInvalidRule, maxInterval)
+	}
+	if r.Count < 0 || r.Count > maxCount {
+		return fmt.Errorf("%w: COUNT must be between 1 and %d", ErrInvalidRule, maxCount)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	}
+	for _, day := range r.ByDay {
+		if day.N != 0 && r.Freq != FrequencyMonthly {
+			return fmt.Errorf("%w: BYDAY ordinals are only allowed for MONTHLY", ErrInvalidRule)
+		}
+		if day.N < -5 || day.N > 5 {
+			return fmt.Errorf("%w: BYDAY ordinal out of range", ErrInvalidRule)
+		}
+	}
+	return nil
+}
+
+// String returns the canonical RRULE text.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, day := range r.ByDay {
+			code := weekdayNames[day.Weekday]
+			if day.N != 0 {
+				code = strconv.Itoa(day.N) + code
+			}
+			days = append(days, code)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Expand returns occurrence starts beginning at dtstart, in dtstart's location.
+// Wall-clock time is kept across DST changes. Expansion stops at end (if non-zero),
+// after limit occurrences (if > 0), or when the rule itself ends.
+func (r Rule) Expand(dtstart, end time.Time, limit int) []time.Time {
+	if r.Validate() != nil {
+		return nil
+	}
+	out := make([]time.Time, 0)
+	for period := 0; period < maxPeriods; period++ {
+		candidates := r.periodCandidates(dtstart, period*r.Interval)
+		if len(candidates) == 0 {
+			continue
+		}
+		for _, candidate := range candidates {
+			if candidate.Before(dtstart) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return out
+			}
+			if !end.IsZero() && candidate.After(end) {
+				return out
+			}
+			out = append(out, candidate)
+			if r.Count > 0 && len(out) >= r.Count {
+				return out
+			}
+			if limit > 0 && len(out) >= limit {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+// Next returns occurrences following the first skip ones up to horizon, capped at max,
+// and whether the rule has no further occurrences beyond them.
+func (r Rule) Next(dtstart time.Time, skip int, horizon time.Time, max int) ([]time.Time, bool) {
+	limit := 0
+	if max > 0 {
+		limit = skip + max
+	}
+	all := r.Expand(dtstart, horizon, limit)
+	if len(all) <= skip {
+		return nil, r.Exhausted(len(all), horizon)
+	}
+	out := all[skip:]
+	if max > 0 && len(out) >= max {
+		return out, r.Count > 0 && len(all) >= r.Count
+	}
+	return out, r.Exhausted(len(all), horizon)
+}
+
+// Exhausted reports whether no occurrences exist after end given generated occurrences so far.
+func (r Rule) Exhausted(generated int, end time.Time) bool {
+	if r.Count > 0 && generated >= r.Count {
+		return true
+	}
+	return r.Until != nil && !r.Until.After(end)
+}
+
+// Remaining returns the rule for occurrences starting from index skipped.
+func (r Rule) Remaining(skipped int) Rule {
+	out := r
+	out.ByDay = append([]WeekdayNum(nil), r.ByDay...)
+	if out.Count > 0 {
+		out.Count -= skipped
+		if out.Count < 1 {
+			out.Count = 1
+		}
+	}
+	return out
+}
+
+// Truncate returns the rule limited to occurrences before index (whose start is at).
+func (r Rule) Truncate(index int, at time.Time) Rule {
+	out := r
+	out.ByDay = append([]WeekdayNum(nil), r.ByDay...)
+	if out.Count > 0 {
+		out.Count = index
+		if out.Count < 1 {
+			out.Count = 1
+		}
+		return out
+	}
+	until := at.Add(-time.Second).UTC()
+	out.Until = &until
+	return out
+}
+
+// periodCandidates returns sorted candidate starts for the period offset from dtstart.
+func (r Rule) periodCandidates(dtstart time.Time, offset int) []time.Time {
+	loc := dtstart.Location()
+	hour, min, sec := dtstart.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, loc)
+	}
+
+	out := make([]time.Time, 0, 7)
+	switch r.Freq {
+	case FrequencyWeekly:
+		shift := (int(dtstart.Weekday()) + 6) % 7
+		monday := at(dtstart.Year(), dtstart.Month(), dtstart.Day()-shift+offset*7)
+		if len(r.ByDay) == 0 {
+			out = append(out, at(monday.Year(), monday.Month(), monday.Day()+shift))
+			break
+		}
+		for _, day := range r.ByDay {
+			delta := (int(day.Weekday) + 6) % 7
+			out = append(out, at(monday.Year(), monday.Month(), monday.Day()+delta))
+		}
+	case FrequencyMonthly:
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(offset), 1, 0, 0, 0, 0, loc)
+		year, month := first.Year(), first.Month()
+		daysInMonth := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+		if len(r.ByDay) == 0 {
+			if dtstart.Day() <= daysInMonth {
+				out = append(out, at(year, month, dtstart.Day()))
+			}
+			break
+		}
+		firstWeekday := first.Weekday()
+		for _, day := range r.ByDay {
+			firstMatch := 1 + (int(day.Weekday)-int(firstWeekday)+7)%7
+			matches := make([]int, 0, 5)
+			for d := firstMatch; d <= daysInMonth; d += 7 {
+				matches = append(matches, d)
+			}
+			switch {
+			case day.N == 0:
+				for _, d := range matches {
+					out = append(out, at(year, month, d))
+				}
+			case day.N > 0 && day.N <= len(matches):
+				out = append(out, at(year, month, matches[day.N-1]))
+			case day.N < 0 && -day.N <= len(matches):
+				out = append(out, at(year, month, matches[len(matches)+day.N]))
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Before(out[j]) })
+	return dedupeTimes(out)
+}
+
+// LoadLocation returns the named location, falling back to UTC.
+func LoadLocation(name string) *time.Location {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseUntil parses UNTIL value in date or UTC date-time form.
+func parseUntil(val string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405", "20060102"} {
+		parsed, err := time.Parse(layout, val)
+		if err != nil {
+			continue
+		}
+		if layout == "20060102" {
+			parsed = parsed.Add(24*time.Hour - time.Second)
+		}
+		return parsed.UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: until %q", ErrInvalidRule, val)
+}
+
+// parseByDay parses BYDAY list.
+func parseByDay(val string) ([]WeekdayNum, error) {
+	out := make([]WeekdayNum, 0, 7)
+	seen := make(map[WeekdayNum]struct{})
+	for _, raw := range strings.Split(val, ",") {
+		raw = strings.TrimSpace(raw)
+		if len(raw) < 2 {
+			return nil, fmt.Errorf("%w: byday %q", ErrInvalidRule, raw)
+		}
+		code := raw[len(raw)-2:]
+		weekday, ok := weekdayCodes[code]
+		if !ok {
+			return nil, fmt.Errorf("%w: byday %q", ErrInvalidRule, raw)
+		}
+		n := 0
+		if prefix := raw[:len(raw)-2]; prefix != "" {
+			parsed, err := strconv.Atoi(prefix)
+			if err != nil || parsed == 0 {
+				return nil, fmt.Errorf("%w: byday %q", ErrInvalidRule, raw)
+			}
+			n = parsed
+		}
+		item := WeekdayNum{Weekday: weekday, N: n}
+		if _, dup := seen[item]; dup {
+			continue
+		}
+		seen[item] = struct{}{}
+		out = append(out, item)
+	}
+	return out, nil
+}
+
+// dedupeTimes removes equal neighbours from sorted times.
+func dedupeTimes(values []time.Time) []time.Time {
+	if len(values) < 2 {
+		return values
+	}
+	out := values[:1]
+	for _, value := range values[1:] {
+		if !value.Equal(out[len(out)-1]) {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,176 @@
+package recurrence
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestParseRoundTrip verifies parse round trip behavior.
+func TestParseRoundTrip(t *testing.T) {
+	rule, err := Parse("RRULE:FREQ=weekly;INTERVAL=2;BYDAY=MO,FR;COUNT=6")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if rule.Freq != FrequencyWeekly || rule.Interval != 2 || rule.Count != 6 || len(rule.ByDay) != 2 {
+		t.Fatalf("unexpected rule: %+v", rule)
+	}
+	if got := rule.String(); got != "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=6" {
+		t.Fatalf("unexpected string: %q", got)
+	}
+}
+
+// TestParseRejectsUnsupported verifies parse rejects unsupported behavior.
+func TestParseRejectsUnsupported(t *testing.T) {
+	cases := []string{
+		"",
+		"FREQ=DAILY",
+		"FREQ=WEEKLY;COUNT=3;UNTIL=20260101",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYHOUR=10",
+		"FREQ=MONTHLY;BYDAY=XX",
+	}
+	for _, raw := range cases {
+		if _, err := Parse(raw); !errors.Is(err, ErrInvalidRule) {
+			t.Fatalf("expected ErrInvalidRule for %q, got %v", raw, err)
+		}
+	}
+}
+
+// TestExpandWeeklyByDay verifies expand weekly by day behavior.
+func TestExpandWeeklyByDay(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=WE,FR;COUNT=4")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	// Thursday: the Wednesday of the first week is skipped.
+	dtstart := time.Date(2026, 3, 5, 20, 0, 0, 0, time.UTC)
+	got := rule.Expand(dtstart, time.Time{}, 0)
+	want := []time.Time{
+		time.Date(2026, 3, 6, 20, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 11, 20, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 13, 20, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 18, 20, 0, 0, 0, time.UTC),
+	}
+	assertTimes(t, got, want)
+}
+
+// TestExpandKeepsWallClockAcrossDST verifies expand keeps wall clock across d s t behavior.
+func TestExpandKeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("tzdata unavailable: %v", err)
+	}
+	rule, _ := Parse("FREQ=WEEKLY")
+	dtstart := time.Date(2026, 3, 21, 19, 0, 0, 0, loc)
+	got := rule.Expand(dtstart, time.Time{}, 3)
+	for _, occurrence := range got {
+		if occurrence.Hour() != 19 {
+			t.Fatalf("expected 19:00 local, got %s", occurrence)
+		}
+	}
+	if got[2].Sub(got[1]) != 167*time.Hour {
+		t.Fatalf("expected DST-shortened week, got %s", got[2].Sub(got[1]))
+	}
+}
+
+// TestExpandMonthlyOrdinal verifies expand monthly ordinal behavior.
+func TestExpandMonthlyOrdinal(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYDAY=-1FR;UNTIL=20260630")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	dtstart := time.Date(2026, 4, 1, 18, 30, 0, 0, time.UTC)
+	got := rule.Expand(dtstart, time.Time{}, 0)
+	want := []time.Time{
+		time.Date(2026, 4, 24, 18, 30, 0, 0, time.UTC),
+		time.Date(2026, 5, 29, 18, 30, 0, 0, time.UTC),
+		time.Date(2026, 6, 26, 18, 30, 0, 0, time.UTC),
+	}
+	assertTimes(t, got, want)
+	if !rule.Exhausted(len(got), time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected rule to be exhausted after UNTIL")
+	}
+}
+
+// TestExpandMonthlySkipsShortMonths verifies expand monthly skips short months behavior.
+func TestExpandMonthlySkipsShortMonths(t *testing.T) {
+	rule, _ := Parse("FREQ=MONTHLY;COUNT=3")
+	dtstart := time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC)
+	got := rule.Expand(dtstart, time.Time{}, 0)
+	want := []time.Time{
+		time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC),
+	}
+	assertTimes(t, got, want)
+}
+
+// TestExpandStopsAtHorizon verifies expand stops at horizon behavior.
+func TestExpandStopsAtHorizon(t *testing.T) {
+	rule, _ := Parse("FREQ=WEEKLY")
+	dtstart := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	got := rule.Expand(dtstart, dtstart.Add(4*7*24*time.Hour), 0)
+	if len(got) != 5 {
+		t.Fatalf("expected 5 occurrences within horizon, got %d", len(got))
+	}
+	if rule.Exhausted(len(got), dtstart.Add(4*7*24*time.Hour)) {
+		t.Fatalf("open-ended rule must not be exhausted")
+	}
+}
+
+// TestNextSkipsMaterialized verifies next skips materialized behavior.
+func TestNextSkipsMaterialized(t *testing.T) {
+	rule, _ := Parse("FREQ=WEEKLY;COUNT=5")
+	dtstart := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	horizon := dtstart.Add(10 * 7 * 24 * time.Hour)
+
+	got, exhausted := rule.Next(dtstart, 3, horizon, 0)
+	assertTimes(t, got, []time.Time{
+		time.Date(2026, 3, 23, 10, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC),
+	})
+	if !exhausted {
+		t.Fatalf("expected COUNT rule to be exhausted")
+	}
+
+	open, _ := Parse("FREQ=WEEKLY")
+	got, exhausted = open.Next(dtstart, 0, horizon, 2)
+	if len(got) != 2 || exhausted {
+		t.Fatalf("expected capped, non-exhausted expansion, got %d exhausted=%v", len(got), exhausted)
+	}
+}
+
+// TestTruncateAndRemaining verifies truncate and remaining behavior.
+func TestTruncateAndRemaining(t *testing.T) {
+	rule, _ := Parse("FREQ=WEEKLY;COUNT=10")
+	if got := rule.Truncate(4, time.Now()).Count; got != 4 {
+		t.Fatalf("expected truncated count 4, got %d", got)
+	}
+	if got := rule.Remaining(4).Count; got != 6 {
+		t.Fatalf("expected remaining count 6, got %d", got)
+	}
+
+	open, _ := Parse("FREQ=WEEKLY")
+	at := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	truncated := open.Truncate(3, at)
+	if truncated.Until == nil || !truncated.Until.Before(at) {
+		t.Fatalf("expected until before split point, got %v", truncated.Until)
+	}
+	if open.Until != nil {
+		t.Fatalf("truncate must not mutate the original rule")
+	}
+}
+
+// assertTimes handles assert times.
+func assertTimes(t *testing.T, got, want []time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("expected %d occurrences, got %d: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("occurrence %d: expected %s, got %s", i, want[i], got[i])
+		}
+	}
+}
@@ -80,9 +80,14 @@ RETURNING id, telegram_id, username, first_name, last_name, photo_url, rating, r
 	return out, nil
 }
 
-// IsUserBlocked reports whether user blocked condition is met.
+// IsUserBlocked reports whether the user has a full ban in force. A user
+// blocked through users.is_blocked without a ban record counts as blocked.
 func (r *Repository) IsUserBlocked(ctx context.Context, userID int64) (bool, error) {
-	row := r.pool.QueryRow(ctx, `SELECT is_blocked FROM users WHERE id = $1`, userID)
+	row := r.pool.QueryRow(ctx, `
+SELECT `+activeFullBanCondition+`
+	OR (is_blocked AND NOT EXISTS (SELECT 1 FROM user_bans WHERE user_id = $1 AND scope = 'full' AND lifted_at IS NULL))
+FROM users
+WHERE id = $1;`, userID)
 	var blocked bool
 	if err := row.Scan(&blocked); err != nil {
 		return false, err
@@ -90,15 +95,20 @@ func (r *Repository) IsUserBlocked(ctx context.Context, userID int64) (bool, err
 	return blocked, nil
 }
 
-// BlockUser handles block user.
-func (r *Repository) BlockUser(ctx context.Context, userID int64, reason string) error {
-	_, err := r.pool.Exec(ctx, `UPDATE users SET is_blocked = true, blocked_reason = $2, blocked_at = now(), updated_at = now() WHERE id = $1`, userID, nullString(reason))
+// BlockUser bans the user permanently from the whole app.
+func (r *Repository) BlockUser(ctx context.Context, userID int64, reason string, adminID int64) error {
+	_, err := r.CreateUserBan(ctx, models.UserBan{
+		UserID:    userID,
+		Scope:     models.BanScopeFull,
+		Reason:    reason,
+		CreatedBy: &adminID,
+	})
 	return err
 }
 
-// UnblockUser handles unblock user.
-func (r *Repository) UnblockUser(ctx context.Context, userID int64) error {
-	_, err := r.pool.Exec(ctx, `UPDATE users SET is_blocked = false, blocked_reason = NULL, blocked_at = NULL, updated_at = now() WHERE id = $1`, userID)
+// UnblockUser lifts all full bans of the user.
+func (r *Repository) UnblockUser(ctx context.Context, userID int64, adminID int64) error {
+	_, err := r.LiftUserBans(ctx, userID, models.BanScopeFull, adminID)
 	return err
 }
 
@@ -287,7 +297,7 @@ func (r *Repository) InsertAdminBroadcastJobsForAll(ctx context.Context, broadca
 INSERT INTO admin_broadcast_jobs (broadcast_id, target_user_id)
 SELECT $1, id
 FROM users
-WHERE is_blocked = false AND telegram_id IS NOT NULL;`, broadcastID)
+WHERE is_blocked = false AND telegram_id > 0 AND telegram_unreachable_at IS NULL;`, broadcastID)
 	if err != nil {
 		return 0, err
 	}
@@ -303,7 +313,7 @@ func (r *Repository) InsertAdminBroadcastJobsForSelected(ctx context.Context, br
 INSERT INTO admin_broadcast_jobs (broadcast_id, target_user_id)
 SELECT $1, id
 FROM users
-WHERE id = ANY($2) AND is_blocked = false AND telegram_id IS NOT NULL;`, broadcastID, userIDs)
+WHERE id = ANY($2) AND is_blocked = false AND telegram_id > 0 AND telegram_unreachable_at IS NULL;`, broadcastID, userIDs)
 	if err != nil {
 		return 0, err
 	}
@@ -312,7 +322,7 @@ WHERE id = ANY($2) AND is_blocked = false AND telegram_id IS NOT NULL;`, broadca
 
 // InsertAdminBroadcastJobsForFilter handles insert admin broadcast jobs for filter.
 func (r *Repository) InsertAdminBroadcastJobsForFilter(ctx context.Context, broadcastID int64, minBalance *int64, lastSeenAfter *time.Time) (int64, error) {
-	clauses := []string{"is_blocked = false", "telegram_id IS NOT NULL"}
+	clauses := []string{"is_blocked = false", "telegram_id > 0", "telegram_unreachable_at IS NULL"}
 	args := []interface{}{broadcastID}
 	idx := 2
 	if minBalance != nil {
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// AnnouncementPolicy selects who hears about a new public event besides
+// followers of its creator and users who opted into all new events.
+type AnnouncementPolicy struct {
+	// RadiusMeters limits targeting to users last seen within this distance of the event.
+	RadiusMeters int
+	// SeenWithin limits targeting to users active within this period; zero means any time.
+	SeenWithin time.Duration
+	// DailyCap is the number of targeted announcements a user gets per 24 hours.
+	DailyCap int
+	// FollowedDailyCap is the number of follower announcements a user gets per
+	// 24 hours; zero means no cap.
+	FollowedDailyCap int
+}
+
+// maxAnnouncementCandidates caps the nearby users considered for one
+// announcement; the most recently active are kept.
+const maxAnnouncementCandidates = 5000
+
+// NewAnnouncementPolicy builds a policy from a radius in kilometers, an
+// activity window in days and the daily caps of targeted and follower
+// announcements.
+func NewAnnouncementPolicy(radiusKm float64, seenDays, dailyCap, followedDailyCap int) AnnouncementPolicy {
+	policy := AnnouncementPolicy{DailyCap: dailyCap, FollowedDailyCap: followedDailyCap}
+	if radiusKm > 0 {
+		policy.RadiusMeters = int(radiusKm * 1000)
+	}
+	if seenDays > 0 {
+		policy.SeenWithin = time.Duration(seenDays) * 24 * time.Hour
+	}
+	return policy
+}
+
+// AnnouncementCounts is the audience of an event announcement by notification kind.
+type AnnouncementCounts struct {
+	Followers int64 `json:"followers"`
+	AllEvents int64 `json:"allEvents"`
+	Nearby    int64 `json:"nearby"`
+	// Capped counts nearby users and followers skipped because they reached
+	// their daily cap.
+	Capped int64 `json:"capped"`
+	Total  int64 `json:"total"`
+}
+
+// announcementAudienceCTE selects announcement recipients as audience(user_id,
+// kind, sent_today). $1 is the event, $2 the current time and $3 the nearby
+// candidates; sent_today counts announcements of the same kind in the last
+// 24 hours. Nearby candidates qualify when the event has no tags, they have
+// no interest history yet, or their interests share a tag with the event.
+// Users who muted the category, already have an announcement of the event or
+// can't be reached by any channel are skipped.
+const announcementAudienceCTE = `
+WITH interests AS (
+	SELECT user_id, array_agg(DISTINCT tag) AS tags
+	FROM (
+		SELECT ip.user_id, unnest(ie.filters) AS tag
+		FROM event_participants ip
+		JOIN events ie ON ie.id = ip.event_id
+		WHERE ip.user_id = ANY($3) AND ie.creator_user_id <> ip.user_id
+		UNION ALL
+		SELECT il.user_id, unnest(ie.filters)
+		FROM event_likes il
+		JOIN events ie ON ie.id = il.event_id
+		WHERE il.user_id = ANY($3)
+	) signals
+	GROUP BY user_id
+), audience AS (
+	SELECT u.id AS user_id,
+		CASE
+			WHEN f.follower_user_id IS NOT NULL THEN 'event_followed'
+			WHEN u.notify_all_events THEN 'event_created'
+			ELSE 'event_nearby'
+		END AS kind,
+		(SELECT count(*) FROM notification_jobs nj
+			WHERE nj.user_id = u.id
+				AND nj.kind = CASE WHEN f.follower_user_id IS NOT NULL THEN 'event_followed' ELSE 'event_nearby' END
+				AND nj.created_at >= $2::timestamptz - interval '24 hours') AS sent_today
+	FROM events e
+	JOIN users u ON u.id <> e.creator_user_id AND u.is_blocked = false
+	LEFT JOIN user_follows f ON f.follower_user_id = u.id AND f.followee_user_id = e.creator_user_id
+	LEFT JOIN interests i ON i.user_id = u.id
+	WHERE e.id = $1
+		AND (
+			f.follower_user_id IS NOT NULL
+			OR u.notify_all_events = true
+			OR (u.id = ANY($3) AND (COALESCE(cardinality(e.filters), 0) = 0 OR i.tags IS NULL OR i.tags && e.filters))
+		)
+		AND NOT (CASE WHEN f.follower_user_id IS NOT NULL THEN 'followed' ELSE 'new_events' END) = ANY(u.notify_muted)
+		AND ` + notifiableUserCondition + `
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_jobs prev
+			WHERE prev.user_id = u.id AND prev.event_id = e.id
+				AND prev.kind IN ('event_followed', 'event_created', 'event_nearby')
+		)
+)`
+
+// nearbyAnnouncementCandidates returns recently active users near the event.
+func (r *Repository) nearbyAnnouncementCandidates(ctx context.Context, eventID int64, now time.Time, policy AnnouncementPolicy) ([]int64, error) {
+	if policy.RadiusMeters <= 0 || policy.DailyCap <= 0 {
+		return []int64{}, nil
+	}
+	var lat, lng float64
+	var creatorID int64
+	if err := r.pool.QueryRow(ctx, `
+SELECT ST_Y(location::geometry), ST_X(location::geometry), creator_user_id
+FROM events
+WHERE id = $1;`, eventID).Scan(&lat, &lng, &creatorID); err != nil {
+		return nil, err
+	}
+	var seenAfter *time.Time
+	if policy.SeenWithin > 0 {
+		since := now.Add(-policy.SeenWithin)
+		seenAfter = &since
+	}
+	return r.GetNearbyUserIDs(ctx, lat, lng, policy.RadiusMeters, creatorID, seenAfter, maxAnnouncementCandidates)
+}
+
+// PreviewEventAnnouncements counts who would be notified about the event
+// without enqueuing anything.
+func (r *Repository) PreviewEventAnnouncements(ctx context.Context, eventID int64, now time.Time, policy AnnouncementPolicy) (AnnouncementCounts, error) {
+	candidates, err := r.nearbyAnnouncementCandidates(ctx, eventID, now, policy)
+	if err != nil {
+		return AnnouncementCounts{}, err
+	}
+	var out AnnouncementCounts
+	err = r.pool.QueryRow(ctx, announcementAudienceCTE+`
+SELECT
+	count(*) FILTER (WHERE kind = 'event_followed' AND ($5 <= 0 OR sent_today < $5)),
+	count(*) FILTER (WHERE kind = 'event_created'),
+	count(*) FILTER (WHERE kind = 'event_nearby' AND sent_today < $4),
+	count(*) FILTER (WHERE (kind = 'event_nearby' AND sent_today >= $4) OR (kind = 'event_followed' AND $5 > 0 AND sent_today >= $5))
+FROM audience;`, eventID, now, candidates, policy.DailyCap, policy.FollowedDailyCap).Scan(&out.Followers, &out.AllEvents, &out.Nearby, &out.Capped)
+	if err != nil {
+		return AnnouncementCounts{}, err
+	}
+	out.Total = out.Followers + out.AllEvents + out.Nearby
+	return out, nil
+}
+
+// EnqueueEventAnnouncements announces a newly published event. Followers of the
+// creator get event_followed, users who opted into all new events get
+// event_created and users selected by policy get event_nearby; followers and
+// targeted users are limited by their daily caps. The creator and blocked
+// users get nothing, and the payload gains the creator name.
+func (r *Repository) EnqueueEventAnnouncements(ctx context.Context, eventID int64, now time.Time, payload map[string]interface{}, policy AnnouncementPolicy) (AnnouncementCounts, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return AnnouncementCounts{}, err
+	}
+	candidates, err := r.nearbyAnnouncementCandidates(ctx, eventID, now, policy)
+	if err != nil {
+		return AnnouncementCounts{}, err
+	}
+	rows, err := r.pool.Query(ctx, announcementAudienceCTE+`
+INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
+SELECT a.user_id, $1, a.kind, $2,
+	$5::jsonb || jsonb_build_object('creatorName', c.name),
+	'pending'
+FROM audience a
+CROSS JOIN (
+	SELECT COALESCE(cu.first_name || ' ' || cu.last_name, cu.first_name) AS name
+	FROM events ce
+	JOIN users cu ON cu.id = ce.creator_user_id
+	WHERE ce.id = $1
+) c
+WHERE a.kind = 'event_created'
+	OR (a.kind = 'event_nearby' AND a.sent_today < $4)
+	OR (a.kind = 'event_followed' AND ($6 <= 0 OR a.sent_today < $6))
+RETURNING kind;`, eventID, now, candidates, policy.DailyCap, payloadBytes, policy.FollowedDailyCap)
+	if err != nil {
+		return AnnouncementCounts{}, err
+	}
+	defer rows.Close()
+
+	var out AnnouncementCounts
+	for rows.Next() {
+		var kind string
+		if err := rows.Scan(&kind); err != nil {
+			return AnnouncementCounts{}, err
+		}
+		switch kind {
+		case "event_followed":
+			out.Followers++
+		case "event_created":
+			out.AllEvents++
+		default:
+			out.Nearby++
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return AnnouncementCounts{}, err
+	}
+	out.Total = out.Followers + out.AllEvents + out.Nearby
+	return out, nil
+}
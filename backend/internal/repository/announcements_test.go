@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+// TestNewAnnouncementPolicy verifies new announcement policy behavior.
+func TestNewAnnouncementPolicy(t *testing.T) {
+	policy := NewAnnouncementPolicy(2.5, 14, 3, 10)
+	if policy.RadiusMeters != 2500 {
+		t.Fatalf("expected 2500 meters, got %d", policy.RadiusMeters)
+	}
+	if policy.SeenWithin != 14*24*time.Hour {
+		t.Fatalf("expected 14 days, got %s", policy.SeenWithin)
+	}
+	if policy.DailyCap != 3 || policy.FollowedDailyCap != 10 {
+		t.Fatalf("expected caps 3 and 10, got %d and %d", policy.DailyCap, policy.FollowedDailyCap)
+	}
+
+	policy = NewAnnouncementPolicy(-1, 0, 3, 0)
+	if policy.RadiusMeters != 0 || policy.SeenWithin != 0 {
+		t.Fatalf("expected disabled radius and activity window, got %+v", policy)
+	}
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetOrCreateCalendarToken returns the user's calendar feed token, storing candidate if none exists.
+func (r *Repository) GetOrCreateCalendarToken(ctx context.Context, userID int64, candidate string) (string, error) {
+	var token string
+	err := r.pool.QueryRow(ctx, `
+UPDATE users
+SET calendar_token = COALESCE(calendar_token, $2)
+WHERE id = $1
+RETURNING calendar_token;`, userID, candidate).Scan(&token)
+	return token, err
+}
+
+// RotateCalendarToken replaces the user's calendar feed token.
+func (r *Repository) RotateCalendarToken(ctx context.Context, userID int64, token string) error {
+	command, err := r.pool.Exec(ctx, `UPDATE users SET calendar_token = $2, updated_at = now() WHERE id = $1`, userID, token)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// GetUserIDByCalendarToken returns the active user owning a calendar feed token.
+func (r *Repository) GetUserIDByCalendarToken(ctx context.Context, token string) (int64, error) {
+	var userID int64
+	err := r.pool.QueryRow(ctx, `
+SELECT id
+FROM users
+WHERE calendar_token = $1
+	AND is_blocked = false;`, token).Scan(&userID)
+	return userID, err
+}
+
+// ListCalendarEvents lists events the user joined or holds paid tickets for.
+// Hidden events are kept so calendar clients can mark them as cancelled.
+func (r *Repository) ListCalendarEvents(ctx context.Context, userID int64, since time.Time, limit int) ([]models.Event, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT e.id, e.creator_user_id, e.title, e.description, e.starts_at, e.ends_at,
+	ST_Y(e.location::geometry) AS lat,
+	ST_X(e.location::geometry) AS lng,
+	e.address_label, e.is_hidden, e.is_private, e.access_key,
+	COALESCE(e.timezone, s.timezone), e.revision, e.status, e.created_at, e.updated_at
+FROM events e
+LEFT JOIN event_series s ON s.id = e.series_id
+WHERE e.status IN ('published', 'canceled')
+	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= $2
+	AND (
+		EXISTS (SELECT 1 FROM event_participants ep WHERE ep.event_id = e.id AND ep.user_id = $1)
+		OR EXISTS (
+			SELECT 1
+			FROM tickets t
+			JOIN orders o ON o.id = t.order_id
+			WHERE t.event_id = e.id
+				AND t.user_id = $1
+				AND o.status IN ('PAID', 'REDEEMED')
+		)
+	)
+ORDER BY e.starts_at ASC
+LIMIT $3;`, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Event, 0)
+	for rows.Next() {
+		var e models.Event
+		var address sql.NullString
+		var accessKey sql.NullString
+		var timezone sql.NullString
+		if err := rows.Scan(
+			&e.ID,
+			&e.CreatorUserID,
+			&e.Title,
+			&e.Description,
+			&e.StartsAt,
+			&e.EndsAt,
+			&e.Lat,
+			&e.Lng,
+			&address,
+			&e.IsHidden,
+			&e.IsPrivate,
+			&accessKey,
+			&timezone,
+			&e.Revision,
+			&e.Status,
+			&e.CreatedAt,
+			&e.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		e.AddressLabel = address.String
+		e.AccessKey = accessKey.String
+		e.Timezone = timezone.String
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,220 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrContentReviewed = errors.New("content already reviewed")
+
+// contentFilterSelect selects content filter entries joined with the author name.
+const contentFilterSelect = `
+SELECT l.id, l.kind, l.target_id, l.user_id,
+	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS user_name,
+	l.event_id, l.content, l.decision, l.rule, l.match, l.status,
+	l.reviewed_by, l.reviewed_at, l.created_at
+FROM content_filter_log l
+JOIN users u ON u.id = l.user_id`
+
+// CountUserContentSince counts comments or events the user created since the given time.
+func (r *Repository) CountUserContentSince(ctx context.Context, kind string, userID int64, since time.Time) (int, error) {
+	query := `SELECT count(*) FROM event_comments WHERE user_id = $1 AND created_at >= $2`
+	if kind == "event" {
+		query = `SELECT count(*) FROM events WHERE creator_user_id = $1 AND created_at >= $2 AND COALESCE(series_index, 0) = 0`
+	}
+	var count int
+	if err := r.pool.QueryRow(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountUserDuplicatesSince counts comments or events of the user with the same
+// normalized text created since the given time. Event text is the title
+// followed by the description.
+func (r *Repository) CountUserDuplicatesSince(ctx context.Context, kind string, userID int64, text string, since time.Time) (int, error) {
+	query := `
+SELECT count(*)
+FROM event_comments
+WHERE user_id = $1 AND created_at >= $2
+	AND lower(regexp_replace(btrim(body), '\s+', ' ', 'g')) = $3`
+	if kind == "event" {
+		query = `
+SELECT count(*)
+FROM events
+WHERE creator_user_id = $1 AND created_at >= $2
+	AND COALESCE(series_index, 0) = 0
+	AND lower(regexp_replace(btrim(title || ' ' || description), '\s+', ' ', 'g')) = $3`
+	}
+	var count int
+	if err := r.pool.QueryRow(ctx, query, userID, since, text).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// LogContentFilter stores a held or rejected item. Rejected items are stored as
+// already rejected; held items wait for review.
+func (r *Repository) LogContentFilter(ctx context.Context, entry models.ContentFilterEntry) (int64, error) {
+	status := models.ContentStatusPending
+	if entry.Decision == "reject" {
+		status = models.ContentStatusRejected
+	}
+	var id int64
+	err := r.pool.QueryRow(ctx, `
+INSERT INTO content_filter_log (kind, target_id, user_id, event_id, content, decision, rule, match, status)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id;`,
+		entry.Kind,
+		nullInt64Ptr(entry.TargetID),
+		entry.UserID,
+		nullInt64Ptr(entry.EventID),
+		entry.Content,
+		entry.Decision,
+		entry.Rule,
+		nullString(entry.Match),
+		status,
+	).Scan(&id)
+	return id, err
+}
+
+// ListContentFilterEntries lists content filter entries, optionally filtered by decision, status and kind.
+func (r *Repository) ListContentFilterEntries(ctx context.Context, decision, status, kind *string, limit, offset int) ([]models.ContentFilterEntry, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+SELECT count(*)
+FROM content_filter_log l
+WHERE ($1::text IS NULL OR l.decision = $1)
+	AND ($2::text IS NULL OR l.status = $2)
+	AND ($3::text IS NULL OR l.kind = $3);`, decision, status, kind).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, contentFilterSelect+`
+WHERE ($1::text IS NULL OR l.decision = $1)
+	AND ($2::text IS NULL OR l.status = $2)
+	AND ($3::text IS NULL OR l.kind = $3)
+ORDER BY l.created_at DESC, l.id DESC
+LIMIT $4 OFFSET $5;`, decision, status, kind, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ContentFilterEntry, 0)
+	for rows.Next() {
+		item, err := scanContentFilterEntry(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ResolveHeldContent approves or rejects a held item. Approving makes the
+// comment, or the event with all occurrences of its series, visible again;
+// rejected items stay hidden. It returns ErrContentReviewed when the item is
+// not pending.
+func (r *Repository) ResolveHeldContent(ctx context.Context, entryID int64, status string, reviewerID int64) (models.ContentFilterEntry, error) {
+	var out models.ContentFilterEntry
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var kind string
+		var targetID sql.NullInt64
+		var current string
+		if err := tx.QueryRow(ctx, `
+SELECT kind, target_id, status
+FROM content_filter_log
+WHERE id = $1 AND decision = 'hold'
+FOR UPDATE;`, entryID).Scan(&kind, &targetID, &current); err != nil {
+			return err
+		}
+		if current != models.ContentStatusPending {
+			return ErrContentReviewed
+		}
+
+		if status == models.ContentStatusApproved && targetID.Valid {
+			query := `UPDATE event_comments SET is_hidden = false WHERE id = $1`
+			if kind == "event" {
+				query = `
+UPDATE events
+SET is_hidden = false,
+	revision = revision + 1,
+	updated_at = now()
+WHERE id = $1
+	OR series_id = (SELECT series_id FROM events WHERE id = $1);`
+			}
+			if _, err := tx.Exec(ctx, query, targetID.Int64); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+UPDATE content_filter_log
+SET status = $2,
+	reviewed_by = $3,
+	reviewed_at = now()
+WHERE id = $1;`, entryID, status, reviewerID); err != nil {
+			return err
+		}
+		var err error
+		out, err = scanContentFilterEntry(tx.QueryRow(ctx, contentFilterSelect+`
+WHERE l.id = $1;`, entryID))
+		return err
+	})
+	if err != nil {
+		return models.ContentFilterEntry{}, err
+	}
+	return out, nil
+}
+
+// scanContentFilterEntry scans a row selected with contentFilterSelect.
+func scanContentFilterEntry(row pgx.Row) (models.ContentFilterEntry, error) {
+	var item models.ContentFilterEntry
+	var targetID sql.NullInt64
+	var eventID sql.NullInt64
+	var match sql.NullString
+	var reviewedBy sql.NullInt64
+	var reviewedAt sql.NullTime
+	if err := row.Scan(
+		&item.ID,
+		&item.Kind,
+		&targetID,
+		&item.UserID,
+		&item.UserName,
+		&eventID,
+		&item.Content,
+		&item.Decision,
+		&item.Rule,
+		&match,
+		&item.Status,
+		&reviewedBy,
+		&reviewedAt,
+		&item.CreatedAt,
+	); err != nil {
+		return models.ContentFilterEntry{}, err
+	}
+	if targetID.Valid {
+		item.TargetID = &targetID.Int64
+	}
+	if eventID.Valid {
+		item.EventID = &eventID.Int64
+	}
+	item.Match = match.String
+	if reviewedBy.Valid {
+		item.ReviewedBy = &reviewedBy.Int64
+	}
+	if reviewedAt.Valid {
+		item.ReviewedAt = &reviewedAt.Time
+	}
+	return item, nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// DigestOptions selects events of a weekly digest.
+type DigestOptions struct {
+	Ranking FeedRanking
+	// SocialWeight boosts events by followed organizers and events friends are going to.
+	SocialWeight float64
+	// RadiusMeters limits events to this distance from the user's last location; zero means anywhere.
+	RadiusMeters int
+	// Horizon is how far ahead events are taken from.
+	Horizon time.Duration
+	Size    int
+}
+
+// ListDueDigestUsers returns users whose digest weekday and time have come in
+// their timezone and who got no digest in the last six days. Users without a
+// timezone, or with one Postgres does not know, are read in
+// models.DefaultTimezone. Blocked users and users who muted digests are left out.
+func (r *Repository) ListDueDigestUsers(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT id
+FROM (
+	SELECT id, digest_weekday, digest_time, digest_last_sent_at,
+		$1::timestamptz AT TIME ZONE CASE
+			WHEN timezone IN (SELECT name FROM pg_timezone_names) THEN timezone
+			ELSE $3
+		END AS local_now
+	FROM users
+	WHERE digest_weekday IS NOT NULL
+		AND is_blocked = false
+		AND NOT 'digest' = ANY(notify_muted)
+) u
+WHERE extract(isodow FROM local_now) = digest_weekday
+	AND extract(hour FROM local_now) * 60 + extract(minute FROM local_now) >= digest_time
+	AND (digest_last_sent_at IS NULL OR digest_last_sent_at < $1::timestamptz - interval '6 days')
+ORDER BY id
+LIMIT $2;`, now, limit, models.DefaultTimezone)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// ListDigestEvents returns upcoming public events for the weekly digest of the
+// user, best first by the feed score plus the social boost. Events the user
+// created or joined are left out.
+func (r *Repository) ListDigestEvents(ctx context.Context, userID int64, now time.Time, opts DigestOptions) ([]models.Event, error) {
+	var lat, lng sql.NullFloat64
+	if err := r.pool.QueryRow(ctx, `
+SELECT ST_Y(last_location::geometry), ST_X(last_location::geometry)
+FROM users
+WHERE id = $1;`, userID).Scan(&lat, &lng); err != nil {
+		return nil, err
+	}
+	var latPtr, lngPtr *float64
+	if lat.Valid && lng.Valid {
+		latPtr, lngPtr = &lat.Float64, &lng.Float64
+	}
+
+	args := []interface{}{userID, now, now.Add(opts.Horizon), opts.Size}
+	query := feedAffinityCTE + feedEventSelect + feedStatsJoin + `
+WHERE e.is_hidden = false
+	AND e.status = 'published'
+	AND e.is_private = false
+	AND e.creator_user_id <> $1
+	AND ep.user_id IS NULL
+	AND e.starts_at > $2
+	AND e.starts_at <= $3
+	AND ` + nextSeriesOccurrenceCondition("prev.starts_at > $2")
+	if latPtr != nil && opts.RadiusMeters > 0 {
+		query += fmt.Sprintf(`
+	AND ST_DWithin(e.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)`, len(args)+1, len(args)+2, len(args)+3)
+		args = append(args, *lngPtr, *latPtr, opts.RadiusMeters)
+	}
+	score, args := feedScoreExpression(opts.Ranking, latPtr, lngPtr, args)
+	if opts.SocialWeight > 0 {
+		score = fmt.Sprintf(`%s
+		+ $%d::float8 * (
+			CASE WHEN EXISTS (
+				SELECT 1 FROM user_follows f WHERE f.follower_user_id = $1 AND f.followee_user_id = e.creator_user_id
+			) THEN 1 ELSE 0 END
+			+ LEAST((
+				SELECT count(*)
+				FROM event_participants fp
+				JOIN user_contact_matches m ON m.user_id = $1 AND m.contact_user_id = fp.user_id
+				JOIN user_contact_matches b ON b.user_id = fp.user_id AND b.contact_user_id = $1
+				JOIN users fu ON fu.id = fp.user_id
+				WHERE fp.event_id = e.id AND fu.hide_attendance = false
+			), 3) / 3.0
+		)`, score, len(args)+1)
+		args = append(args, opts.SocialWeight)
+	}
+	query += `
+ORDER BY ` + score + ` DESC, e.starts_at ASC, e.id ASC
+LIMIT $4;`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Event, 0)
+	for rows.Next() {
+		e, err := scanFeedEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// EnqueueWeeklyDigest records that the user got this week's digest and, when
+// payload is not nil, enqueues the weekly_digest job.
+func (r *Repository) EnqueueWeeklyDigest(ctx context.Context, userID int64, now time.Time, payload map[string]interface{}) error {
+	var payloadBytes []byte
+	if payload != nil {
+		var err error
+		if payloadBytes, err = json.Marshal(payload); err != nil {
+			return err
+		}
+	}
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE users SET digest_last_sent_at = $2 WHERE id = $1`, userID, now); err != nil {
+			return err
+		}
+		if payloadBytes == nil {
+			return nil
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO notification_jobs (user_id, kind, run_at, payload, status)
+VALUES ($1, 'weekly_digest', $2, $3, 'pending');`, userID, now, payloadBytes)
+		return err
+	})
+}
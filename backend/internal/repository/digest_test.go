@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/db"
+)
+
+// TestListDueDigestUsersDefaultsTimezone verifies a user without a timezone
+// Postgres knows gets the digest in Moscow time and doesn't break the query.
+func TestListDueDigestUsersDefaultsTimezone(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778501)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	timezone := "Local"
+	if err := repo.UpdateNotificationSettings(ctx, userID, NotificationSettingsUpdate{Timezone: &timezone}, time.Now()); !errors.Is(err, ErrInvalidTimezone) {
+		t.Fatalf("expected ErrInvalidTimezone, got %v", err)
+	}
+	// Values saved before the check existed must not fail the digest query.
+	if _, err := pool.Exec(ctx, `UPDATE users SET timezone = $2, digest_weekday = 1, digest_time = 600 WHERE id = $1`, userID, timezone); err != nil {
+		t.Fatalf("set digest schedule: %v", err)
+	}
+
+	// Monday 09:30 and 10:30 in Moscow.
+	early := time.Date(2026, 10, 19, 6, 30, 0, 0, time.UTC)
+	due := time.Date(2026, 10, 19, 7, 30, 0, 0, time.UTC)
+	ids, err := repo.ListDueDigestUsers(ctx, early, 10000)
+	if err != nil {
+		t.Fatalf("ListDueDigestUsers(): %v", err)
+	}
+	if slices.Contains(ids, userID) {
+		t.Fatalf("digest must wait for 10:00 Moscow time")
+	}
+	ids, err = repo.ListDueDigestUsers(ctx, due, 10000)
+	if err != nil {
+		t.Fatalf("ListDueDigestUsers(): %v", err)
+	}
+	if !slices.Contains(ids, userID) {
+		t.Fatalf("expected user %d to be due at 10:30 Moscow time", userID)
+	}
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// eventCommentSelect selects comments joined with the author name.
+const eventCommentSelect = `
+SELECT c.id, c.event_id, c.parent_id, c.user_id, c.body, c.created_at, c.edited_at,
+	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS user_name
+FROM event_comments c
+JOIN users u ON u.id = c.user_id`
+
+// GetEventComment returns a single visible comment without reactions.
+func (r *Repository) GetEventComment(ctx context.Context, commentID int64) (models.EventComment, error) {
+	return scanEventComment(r.pool.QueryRow(ctx, eventCommentSelect+`
+WHERE c.id = $1 AND c.is_hidden = false;`, commentID))
+}
+
+// UpdateEventComment changes the body of a comment written by userID after editableSince.
+// It returns pgx.ErrNoRows when the comment is missing, not owned or too old.
+func (r *Repository) UpdateEventComment(ctx context.Context, commentID, userID int64, body string, editableSince time.Time) (models.EventComment, error) {
+	command, err := r.pool.Exec(ctx, `
+UPDATE event_comments
+SET body = $3,
+	edited_at = now()
+WHERE id = $1 AND user_id = $2 AND created_at >= $4 AND is_hidden = false;`, commentID, userID, body, editableSince)
+	if err != nil {
+		return models.EventComment{}, err
+	}
+	if command.RowsAffected() == 0 {
+		return models.EventComment{}, pgx.ErrNoRows
+	}
+	comment, err := r.GetEventComment(ctx, commentID)
+	if err != nil {
+		return models.EventComment{}, err
+	}
+	comments := []models.EventComment{comment}
+	if err := r.attachCommentReactions(ctx, comments, userID); err != nil {
+		return models.EventComment{}, err
+	}
+	return comments[0], nil
+}
+
+// SetEventCommentHidden hides or restores a comment.
+func (r *Repository) SetEventCommentHidden(ctx context.Context, commentID int64, hidden bool) error {
+	_, err := r.pool.Exec(ctx, `UPDATE event_comments SET is_hidden = $2 WHERE id = $1`, commentID, hidden)
+	return err
+}
+
+// AddCommentReaction adds the user's emoji reaction to a comment.
+func (r *Repository) AddCommentReaction(ctx context.Context, commentID, userID int64, emoji string) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO event_comment_reactions (comment_id, user_id, emoji)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;`, commentID, userID, emoji)
+	return err
+}
+
+// RemoveCommentReaction removes the user's emoji reaction from a comment.
+func (r *Repository) RemoveCommentReaction(ctx context.Context, commentID, userID int64, emoji string) error {
+	_, err := r.pool.Exec(ctx, `
+DELETE FROM event_comment_reactions
+WHERE comment_id = $1 AND user_id = $2 AND emoji = $3;`, commentID, userID, emoji)
+	return err
+}
+
+// ListCommentReactions returns reaction counts of a comment as seen by viewerID.
+func (r *Repository) ListCommentReactions(ctx context.Context, commentID, viewerID int64) ([]models.CommentReaction, error) {
+	comments := []models.EventComment{{ID: commentID}}
+	if err := r.attachCommentReactions(ctx, comments, viewerID); err != nil {
+		return nil, err
+	}
+	return comments[0].Reactions, nil
+}
+
+// GetUserIDsByUsernames resolves Telegram usernames (case-insensitive) to user ids.
+// The result is keyed by lower-case username.
+func (r *Repository) GetUserIDsByUsernames(ctx context.Context, usernames []string) (map[string]int64, error) {
+	out := make(map[string]int64, len(usernames))
+	if len(usernames) == 0 {
+		return out, nil
+	}
+	lowered := make([]string, 0, len(usernames))
+	for _, username := range usernames {
+		lowered = append(lowered, strings.ToLower(username))
+	}
+	rows, err := r.pool.Query(ctx, `
+SELECT DISTINCT ON (lower(username)) lower(username), id
+FROM users
+WHERE lower(username) = ANY($1)
+ORDER BY lower(username), id ASC;`, lowered)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var username string
+		var id int64
+		if err := rows.Scan(&username, &id); err != nil {
+			return nil, err
+		}
+		out[username] = id
+	}
+	return out, rows.Err()
+}
+
+// attachCommentReactions loads reaction counts for comments in place.
+func (r *Repository) attachCommentReactions(ctx context.Context, comments []models.EventComment, viewerID int64) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(comments))
+	index := make(map[int64]int, len(comments))
+	for i := range comments {
+		comments[i].Reactions = []models.CommentReaction{}
+		ids = append(ids, comments[i].ID)
+		index[comments[i].ID] = i
+	}
+	rows, err := r.pool.Query(ctx, `
+SELECT comment_id, emoji, count(*), bool_or(user_id = $2)
+FROM event_comment_reactions
+WHERE comment_id = ANY($1)
+GROUP BY comment_id, emoji
+ORDER BY comment_id, count(*) DESC, min(created_at) ASC;`, ids, viewerID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var commentID int64
+		var reaction models.CommentReaction
+		if err := rows.Scan(&commentID, &reaction.Emoji, &reaction.Count, &reaction.Reacted); err != nil {
+			return err
+		}
+		if i, ok := index[commentID]; ok {
+			comments[i].Reactions = append(comments[i].Reactions, reaction)
+		}
+	}
+	return rows.Err()
+}
+
+// scanEventComment scans a row selected with eventCommentSelect.
+func scanEventComment(row pgx.Row) (models.EventComment, error) {
+	var comment models.EventComment
+	if err := row.Scan(
+		&comment.ID,
+		&comment.EventID,
+		&comment.ParentID,
+		&comment.UserID,
+		&comment.Body,
+		&comment.CreatedAt,
+		&comment.EditedAt,
+		&comment.UserName,
+	); err != nil {
+		return models.EventComment{}, err
+	}
+	return comment, nil
+}
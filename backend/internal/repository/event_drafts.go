@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// draftScopeCondition matches a draft event and, for series, its other draft occurrences.
+const draftScopeCondition = `
+status = 'draft'
+	AND (
+		id = $1
+		OR (series_id IS NOT NULL AND series_id = (SELECT series_id FROM events WHERE id = $1))
+	)`
+
+// eventStatus returns the stored status, defaulting to published.
+func eventStatus(status string) string {
+	if status == models.EventStatusDraft {
+		return models.EventStatusDraft
+	}
+	return models.EventStatusPublished
+}
+
+// PublishEvent publishes a draft event together with the drafts of its series.
+func (r *Repository) PublishEvent(ctx context.Context, eventID int64) error {
+	command, err := r.pool.Exec(ctx, `
+UPDATE events
+SET status = 'published',
+	publish_at = NULL,
+	published_at = now(),
+	revision = revision + 1,
+	updated_at = now()
+WHERE `+draftScopeCondition+`;`, eventID)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ScheduleEventPublish sets or clears publish time of a draft event and its series drafts.
+func (r *Repository) ScheduleEventPublish(ctx context.Context, eventID int64, publishAt *time.Time) error {
+	command, err := r.pool.Exec(ctx, `
+UPDATE events
+SET publish_at = $2,
+	updated_at = now()
+WHERE `+draftScopeCondition+`;`, eventID, publishAt)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// PublishDueEvents publishes drafts whose publish time has come and returns them.
+// Hidden drafts wait until they are restored.
+func (r *Repository) PublishDueEvents(ctx context.Context, now time.Time, limit int) ([]models.Event, error) {
+	rows, err := r.pool.Query(ctx, `
+UPDATE events e
+SET status = 'published',
+	publish_at = NULL,
+	published_at = now(),
+	revision = e.revision + 1,
+	updated_at = now()
+WHERE e.id IN (
+	SELECT id
+	FROM events
+	WHERE status = 'draft'
+		AND is_hidden = false
+		AND publish_at IS NOT NULL
+		AND publish_at <= $1
+	ORDER BY publish_at ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING e.id, e.creator_user_id, e.title, e.starts_at, e.address_label, e.is_private, e.series_id, e.series_index,
+	(SELECT url FROM event_media WHERE event_id = e.id ORDER BY id ASC LIMIT 1);`, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Event, 0)
+	for rows.Next() {
+		var e models.Event
+		var address sql.NullString
+		var thumb sql.NullString
+		if err := rows.Scan(&e.ID, &e.CreatorUserID, &e.Title, &e.StartsAt, &address, &e.IsPrivate, &e.SeriesID, &e.SeriesIndex, &thumb); err != nil {
+			return nil, err
+		}
+		e.AddressLabel = address.String
+		e.ThumbnailURL = thumb.String
+		e.Status = models.EventStatusPublished
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrEventCanceled     = errors.New("event canceled")
+	ErrEventNotPublished = errors.New("event not published")
+)
+
+// EventCancellation summarizes side effects of canceling an event.
+type EventCancellation struct {
+	CanceledOrders int64 `json:"canceledOrders"`
+	QueuedRefunds  int64 `json:"queuedRefunds"`
+}
+
+// CancelEvent marks a published event as canceled, cancels its pending orders,
+// queues paid orders for refund and drops pending reminders.
+func (r *Repository) CancelEvent(ctx context.Context, eventID, actorID int64, reason string) (EventCancellation, error) {
+	var out EventCancellation
+	reason = strings.TrimSpace(reason)
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var status string
+		if err := tx.QueryRow(ctx, `SELECT status FROM events WHERE id = $1 FOR UPDATE`, eventID).Scan(&status); err != nil {
+			return err
+		}
+		if status == models.EventStatusCanceled {
+			return ErrEventCanceled
+		}
+		if status != models.EventStatusPublished {
+			return ErrEventNotPublished
+		}
+
+		if _, err := tx.Exec(ctx, `
+UPDATE events
+SET status = 'canceled',
+	canceled_at = now(),
+	cancel_reason = $2,
+	is_series_exception = is_series_exception OR series_id IS NOT NULL,
+	revision = revision + 1,
+	updated_at = now()
+WHERE id = $1;`, eventID, nullString(reason)); err != nil {
+			return err
+		}
+
+		var err error
+		out, err = cancelEventSalesTx(ctx, tx, eventID, actorID, reason)
+		return err
+	})
+	if err != nil {
+		return EventCancellation{}, err
+	}
+	return out, nil
+}
+
+// cancelEventSalesTx cancels pending orders of a canceled event, queues refunds
+// of its paid orders and drops its pending reminders.
+func cancelEventSalesTx(ctx context.Context, tx pgx.Tx, eventID, actorID int64, reason string) (EventCancellation, error) {
+	var out EventCancellation
+	if _, err := tx.Exec(ctx, `
+UPDATE promo_codes p
+SET used_count = GREATEST(0, p.used_count - c.cnt),
+	updated_at = now()
+FROM (
+	SELECT promo_code_id, count(*) AS cnt
+	FROM orders
+	WHERE event_id = $1 AND status = $2 AND promo_code_id IS NOT NULL
+	GROUP BY promo_code_id
+) c
+WHERE p.id = c.promo_code_id;`, eventID, models.OrderStatusPending); err != nil {
+		return EventCancellation{}, err
+	}
+
+	command, err := tx.Exec(ctx, `
+UPDATE orders
+SET status = $3,
+	canceled_at = now(),
+	canceled_by = $4,
+	canceled_reason = $5,
+	updated_at = now()
+WHERE event_id = $1 AND status = $2;`, eventID, models.OrderStatusPending, models.OrderStatusCanceled, actorID, "event canceled")
+	if err != nil {
+		return EventCancellation{}, err
+	}
+	out.CanceledOrders = command.RowsAffected()
+
+	command, err = tx.Exec(ctx, `
+INSERT INTO order_refunds (order_id, event_id, status, reason, amount_cents, currency)
+SELECT id, event_id, $3, $4, total_cents, currency
+FROM orders
+WHERE event_id = $1 AND status = $2
+ON CONFLICT (order_id) DO NOTHING;`, eventID, models.OrderStatusPaid, models.RefundStatusPending, nullString(reason))
+	if err != nil {
+		return EventCancellation{}, err
+	}
+	out.QueuedRefunds = command.RowsAffected()
+
+	if _, err := tx.Exec(ctx, `
+DELETE FROM notification_jobs
+WHERE event_id = $1 AND kind IN ('reminder', 'reminder_60m') AND status = 'pending';`, eventID); err != nil {
+		return EventCancellation{}, err
+	}
+	return out, nil
+}
+
+// RescheduleEvent moves an event to new times and recomputes pending reminders
+// of its participants. It returns the number of reminders scheduled.
+func (r *Repository) RescheduleEvent(ctx context.Context, eventID int64, startsAt time.Time, endsAt *time.Time, now time.Time) (int64, error) {
+	var reminders int64
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		command, err := tx.Exec(ctx, `
+UPDATE events
+SET starts_at = $2,
+	ends_at = $3,
+	is_series_exception = is_series_exception OR series_id IS NOT NULL,
+	revision = revision + 1,
+	updated_at = now()
+WHERE id = $1 AND status <> 'canceled';`, eventID, startsAt, endsAt)
+		if err != nil {
+			return err
+		}
+		if command.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		reminders, err = recomputeRemindersTx(ctx, tx, eventID, now)
+		return err
+	})
+	return reminders, err
+}
+
+// RecomputeEventReminders replaces pending reminders of an event after its start moved.
+func (r *Repository) RecomputeEventReminders(ctx context.Context, eventID int64, now time.Time) (int64, error) {
+	var reminders int64
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var err error
+		reminders, err = recomputeRemindersTx(ctx, tx, eventID, now)
+		return err
+	})
+	return reminders, err
+}
+
+// recomputeRemindersTx drops pending reminders of an event and schedules new
+// ones for all participants whose reminder times are still ahead.
+func recomputeRemindersTx(ctx context.Context, tx pgx.Tx, eventID int64, now time.Time) (int64, error) {
+	if _, err := tx.Exec(ctx, `
+DELETE FROM notification_jobs
+WHERE event_id = $1 AND kind IN ('reminder', 'reminder_60m') AND status = 'pending';`, eventID); err != nil {
+		return 0, err
+	}
+	command, err := tx.Exec(ctx, reminderJobsInsert+`
+	AND p.event_id = $2;`, now, eventID)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
+
+// RefreshPendingEventCards updates event details in pending event_created,
+// event_followed, event_nearby and saved_search_alert jobs so they are not
+// sent with outdated data.
+func (r *Repository) RefreshPendingEventCards(ctx context.Context, event models.Event) (int64, error) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"title":        event.Title,
+		"startsAt":     event.StartsAt.UTC().Format(time.RFC3339),
+		"addressLabel": event.AddressLabel,
+	})
+	if err != nil {
+		return 0, err
+	}
+	command, err := r.pool.Exec(ctx, `
+UPDATE notification_jobs
+SET payload = payload || $2::jsonb,
+	updated_at = now()
+WHERE event_id = $1
+	AND status = 'pending'
+	AND kind IN ('event_created', 'event_followed', 'event_nearby', 'saved_search_alert');`, event.ID, patch)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
+
+// CreateEventAudienceNotificationJobs enqueues a job for every participant and
+// ticket holder of an event, except excludeUserID.
+func (r *Repository) CreateEventAudienceNotificationJobs(ctx context.Context, eventID int64, kind string, runAt time.Time, payload map[string]interface{}, excludeUserID int64) (int64, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	command, err := r.pool.Exec(ctx, `
+INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
+SELECT a.user_id, $1, $2, $3, $4, 'pending'
+FROM (
+	SELECT user_id FROM event_participants WHERE event_id = $1
+	UNION
+	SELECT user_id FROM orders WHERE event_id = $1 AND status IN ($6, $7)
+) a
+JOIN users u ON u.id = a.user_id
+WHERE a.user_id <> $5
+	AND `+notifiableUserCondition+`;`, eventID, kind, runAt, payloadBytes, excludeUserID, models.OrderStatusPaid, models.OrderStatusRedeemed)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
+
+// ListOrderRefunds lists queued refunds, newest first.
+func (r *Repository) ListOrderRefunds(ctx context.Context, status string, eventID *int64, limit, offset int) ([]models.OrderRefund, int, error) {
+	status = strings.ToLower(strings.TrimSpace(status))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+SELECT count(*)
+FROM order_refunds f
+WHERE ($1::text = '' OR f.status = $1)
+	AND ($2::bigint IS NULL OR f.event_id = $2);`, status, nullInt64Ptr(eventID)).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT f.order_id::text, f.event_id, e.title, f.status, f.reason, f.amount_cents, f.currency,
+	f.resolved_by, f.resolved_at, f.created_at, f.updated_at,
+	u.id, u.telegram_id, u.first_name, u.last_name, u.username
+FROM order_refunds f
+JOIN orders o ON o.id = f.order_id
+JOIN users u ON u.id = o.user_id
+JOIN events e ON e.id = f.event_id
+WHERE ($1::text = '' OR f.status = $1)
+	AND ($2::bigint IS NULL OR f.event_id = $2)
+ORDER BY f.created_at DESC, f.order_id ASC
+LIMIT $3 OFFSET $4;`, status, nullInt64Ptr(eventID), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.OrderRefund, 0)
+	for rows.Next() {
+		var item models.OrderRefund
+		var reason sql.NullString
+		var user models.OrderUserSummary
+		var lastName sql.NullString
+		var username sql.NullString
+		if err := rows.Scan(
+			&item.OrderID,
+			&item.EventID,
+			&item.EventTitle,
+			&item.Status,
+			&reason,
+			&item.AmountCents,
+			&item.Currency,
+			&item.ResolvedBy,
+			&item.ResolvedAt,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+			&user.ID,
+			&user.TelegramID,
+			&user.FirstName,
+			&lastName,
+			&username,
+		); err != nil {
+			return nil, 0, err
+		}
+		item.Reason = reason.String
+		user.LastName = lastName.String
+		user.Username = username.String
+		item.User = &user
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ResolveOrderRefund closes a pending refund. Refunded orders are canceled and
+// their inventory is released; rejected refunds leave the order paid.
+func (r *Repository) ResolveOrderRefund(ctx context.Context, orderID, status string, resolvedBy int64, note string) error {
+	note = strings.TrimSpace(note)
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		var current string
+		if err := tx.QueryRow(ctx, `
+SELECT status
+FROM order_refunds
+WHERE order_id = $1::uuid
+FOR UPDATE;`, orderID).Scan(&current); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+		if current != models.RefundStatusPending {
+			return ErrOrderStateNotAllowed
+		}
+		if status == models.RefundStatusRefunded {
+			reason := note
+			if reason == "" {
+				reason = "event canceled"
+			}
+			if err := r.cancelOrderTx(ctx, tx, orderID, resolvedBy, reason); err != nil {
+				return err
+			}
+		}
+		_, err := tx.Exec(ctx, `
+UPDATE order_refunds
+SET status = $2,
+	reason = COALESCE($4, reason),
+	resolved_by = $3,
+	resolved_at = now(),
+	updated_at = now()
+WHERE order_id = $1::uuid;`, orderID, status, resolvedBy, nullString(note))
+		return err
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/models"
+)
+
+// TestCancelEventKeepsCanceledOccurrenceOutOfSeriesTemplate verifies cancel event keeps canceled occurrence out of series template behavior.
+func TestCancelEventKeepsCanceledOccurrenceOutOfSeriesTemplate(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778302)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM event_series WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	starts := []time.Time{first, first.AddDate(0, 0, 7)}
+	duration := 3600
+	series := models.EventSeries{
+		CreatorUserID:     userID,
+		RRule:             "FREQ=WEEKLY",
+		Timezone:          "Europe/Moscow",
+		StartsAt:          first,
+		DurationSeconds:   &duration,
+		MaterializedUntil: starts[1],
+	}
+	event := models.Event{CreatorUserID: userID, Title: "Series Cancel Test", Description: "Test series", Lat: 55.75, Lng: 37.61, Status: models.EventStatusPublished}
+	seriesID, eventIDs, err := repo.CreateEventSeries(ctx, series, event, nil, starts)
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+
+	if _, err := repo.CancelEvent(ctx, eventIDs[1], userID, "venue closed"); err != nil {
+		t.Fatalf("CancelEvent(): %v", err)
+	}
+	var exception bool
+	if err := pool.QueryRow(ctx, `SELECT is_series_exception FROM events WHERE id = $1`, eventIDs[1]).Scan(&exception); err != nil {
+		t.Fatalf("load canceled occurrence: %v", err)
+	}
+	if !exception {
+		t.Fatalf("expected canceled occurrence to become a series exception")
+	}
+
+	stored, err := repo.GetEventSeries(ctx, seriesID)
+	if err != nil {
+		t.Fatalf("GetEventSeries(): %v", err)
+	}
+	next := []time.Time{first.AddDate(0, 0, 14), first.AddDate(0, 0, 21)}
+	appended, err := repo.AppendEventSeriesOccurrences(ctx, stored, next, next[1], true)
+	if err != nil {
+		t.Fatalf("AppendEventSeriesOccurrences(): %v", err)
+	}
+	if len(appended) != len(next) {
+		t.Fatalf("expected %d appended occurrences, got %d", len(next), len(appended))
+	}
+	for _, id := range appended {
+		var status string
+		if err := pool.QueryRow(ctx, `SELECT status FROM events WHERE id = $1`, id).Scan(&status); err != nil {
+			t.Fatalf("load appended occurrence: %v", err)
+		}
+		if status != models.EventStatusPublished {
+			t.Fatalf("expected appended occurrence %d to be published, got %s", id, status)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrEventNotEnded    = errors.New("event has not ended")
+	ErrReviewNotAllowed = errors.New("review not allowed")
+)
+
+// eventEndExpr is the effective end of an event; open-ended events last two hours.
+const eventEndExpr = `COALESCE(e.ends_at, e.starts_at + interval '2 hours')`
+
+// reviewAudienceSelect selects users who may review event $1: checked-in
+// participants and holders of checked-in tickets.
+const reviewAudienceSelect = `
+SELECT p.user_id FROM event_participants p WHERE p.event_id = $1 AND p.checked_in_at IS NOT NULL
+UNION
+SELECT t.user_id
+FROM tickets t
+JOIN orders o ON o.id = t.order_id
+WHERE t.event_id = $1
+	AND t.redeemed_at IS NOT NULL
+	AND o.status IN ('PAID', 'REDEEMED')`
+
+// eventReviewSelect selects reviews joined with author and event.
+const eventReviewSelect = `
+SELECT rv.id, rv.event_id, e.title, rv.user_id,
+	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS user_name,
+	rv.rating, rv.body, rv.is_hidden, rv.hidden_reason, rv.created_at, rv.updated_at
+FROM event_reviews rv
+JOIN users u ON u.id = rv.user_id
+JOIN events e ON e.id = rv.event_id`
+
+// UpsertEventReview creates or updates the user's review of an ended event and
+// refreshes the organizer rating.
+func (r *Repository) UpsertEventReview(ctx context.Context, eventID, userID int64, rating int, body string) (models.EventReview, error) {
+	var review models.EventReview
+	body = strings.TrimSpace(body)
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var creatorID int64
+		var status string
+		var ended bool
+		if err := tx.QueryRow(ctx, `
+SELECT e.creator_user_id, e.status, `+eventEndExpr+` <= now()
+FROM events e
+WHERE e.id = $1;`, eventID).Scan(&creatorID, &status, &ended); err != nil {
+			return err
+		}
+		if creatorID == userID || status != models.EventStatusPublished {
+			return ErrReviewNotAllowed
+		}
+		if !ended {
+			return ErrEventNotEnded
+		}
+		var eligible bool
+		if err := tx.QueryRow(ctx, `SELECT $2::bigint IN (`+reviewAudienceSelect+`)`, eventID, userID).Scan(&eligible); err != nil {
+			return err
+		}
+		if !eligible {
+			return ErrReviewNotAllowed
+		}
+
+		var reviewID int64
+		if err := tx.QueryRow(ctx, `
+INSERT INTO event_reviews (event_id, user_id, rating, body)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (event_id, user_id) DO UPDATE
+SET rating = EXCLUDED.rating,
+	body = EXCLUDED.body,
+	updated_at = now()
+RETURNING id;`, eventID, userID, rating, nullString(body)).Scan(&reviewID); err != nil {
+			return err
+		}
+		if err := refreshCreatorRating(ctx, tx, creatorID); err != nil {
+			return err
+		}
+		var err error
+		review, err = scanEventReview(tx.QueryRow(ctx, eventReviewSelect+`
+WHERE rv.id = $1;`, reviewID))
+		return err
+	})
+	if err != nil {
+		return models.EventReview{}, err
+	}
+	return review, nil
+}
+
+// ListEventReviews lists visible reviews of an event with the rating summary.
+func (r *Repository) ListEventReviews(ctx context.Context, eventID int64, limit, offset int) ([]models.EventReview, models.EventReviewSummary, error) {
+	var summary models.EventReviewSummary
+	if err := r.pool.QueryRow(ctx, `
+SELECT COALESCE(round(avg(rating), 2), 0)::float8, count(*)
+FROM event_reviews
+WHERE event_id = $1 AND is_hidden = false;`, eventID).Scan(&summary.Average, &summary.Count); err != nil {
+		return nil, summary, err
+	}
+
+	rows, err := r.pool.Query(ctx, eventReviewSelect+`
+WHERE rv.event_id = $1 AND rv.is_hidden = false
+ORDER BY rv.created_at DESC, rv.id DESC
+LIMIT $2 OFFSET $3;`, eventID, limit, offset)
+	if err != nil {
+		return nil, summary, err
+	}
+	defer rows.Close()
+
+	items := make([]models.EventReview, 0)
+	for rows.Next() {
+		review, err := scanEventReview(rows)
+		if err != nil {
+			return nil, summary, err
+		}
+		items = append(items, review)
+	}
+	return items, summary, rows.Err()
+}
+
+// ListEventReviewsForModeration lists reviews for admins, optionally filtered by visibility and event.
+func (r *Repository) ListEventReviewsForModeration(ctx context.Context, hidden *bool, eventID *int64, limit, offset int) ([]models.EventReview, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+SELECT count(*)
+FROM event_reviews rv
+WHERE ($1::boolean IS NULL OR rv.is_hidden = $1)
+	AND ($2::bigint IS NULL OR rv.event_id = $2);`, boolPtrOrNil(hidden), nullInt64Ptr(eventID)).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, eventReviewSelect+`
+WHERE ($1::boolean IS NULL OR rv.is_hidden = $1)
+	AND ($2::bigint IS NULL OR rv.event_id = $2)
+ORDER BY rv.created_at DESC, rv.id DESC
+LIMIT $3 OFFSET $4;`, boolPtrOrNil(hidden), nullInt64Ptr(eventID), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.EventReview, 0)
+	for rows.Next() {
+		review, err := scanEventReview(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// SetEventReviewHidden hides or restores a review and refreshes the organizer rating.
+func (r *Repository) SetEventReviewHidden(ctx context.Context, reviewID int64, hidden bool, reason string, moderatorID int64) error {
+	reason = strings.TrimSpace(reason)
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		var creatorID int64
+		if err := tx.QueryRow(ctx, `
+UPDATE event_reviews rv
+SET is_hidden = $2,
+	hidden_reason = CASE WHEN $2 THEN $3 ELSE NULL END,
+	moderated_by = $4,
+	moderated_at = now(),
+	updated_at = now()
+FROM events e
+WHERE rv.id = $1 AND e.id = rv.event_id
+RETURNING e.creator_user_id;`, reviewID, hidden, nullString(reason), moderatorID).Scan(&creatorID); err != nil {
+			return err
+		}
+		return refreshCreatorRating(ctx, tx, creatorID)
+	})
+}
+
+// DeleteEventReview deletes a review and refreshes the organizer rating.
+func (r *Repository) DeleteEventReview(ctx context.Context, reviewID int64) error {
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		var creatorID int64
+		if err := tx.QueryRow(ctx, `
+DELETE FROM event_reviews rv
+USING events e
+WHERE rv.id = $1 AND e.id = rv.event_id
+RETURNING e.creator_user_id;`, reviewID).Scan(&creatorID); err != nil {
+			return err
+		}
+		return refreshCreatorRating(ctx, tx, creatorID)
+	})
+}
+
+// QueueReviewRequests enqueues review_request jobs for events that ended before
+// endedBefore and marks them as requested. It returns the number of events.
+func (r *Repository) QueueReviewRequests(ctx context.Context, endedBefore, runAt time.Time, limit int) (int, int64, error) {
+	var eventsCount int
+	var jobs int64
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+SELECT e.id, e.creator_user_id, e.title
+FROM events e
+WHERE e.status = 'published'
+	AND e.review_requested_at IS NULL
+	AND `+eventEndExpr+` <= $1
+ORDER BY e.starts_at ASC
+LIMIT $2
+FOR UPDATE SKIP LOCKED;`, endedBefore, limit)
+		if err != nil {
+			return err
+		}
+		type dueEvent struct {
+			id        int64
+			creatorID int64
+			title     string
+		}
+		due := make([]dueEvent, 0)
+		for rows.Next() {
+			var item dueEvent
+			if err := rows.Scan(&item.id, &item.creatorID, &item.title); err != nil {
+				rows.Close()
+				return err
+			}
+			due = append(due, item)
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return err
+		}
+		rows.Close()
+
+		for _, event := range due {
+			payload, err := json.Marshal(map[string]interface{}{"eventId": event.id, "title": event.title})
+			if err != nil {
+				return err
+			}
+			command, err := tx.Exec(ctx, `
+INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
+SELECT a.user_id, $1, 'review_request', $3, $4, 'pending'
+FROM (`+reviewAudienceSelect+`) a
+WHERE a.user_id <> $2;`, event.id, event.creatorID, runAt, payload)
+			if err != nil {
+				return err
+			}
+			jobs += command.RowsAffected()
+			if _, err := tx.Exec(ctx, `UPDATE events SET review_requested_at = now() WHERE id = $1`, event.id); err != nil {
+				return err
+			}
+		}
+		eventsCount = len(due)
+		return nil
+	})
+	return eventsCount, jobs, err
+}
+
+// refreshCreatorRating recomputes users.rating and rating_count from visible reviews of the creator's events.
+func refreshCreatorRating(ctx context.Context, tx pgx.Tx, creatorID int64) error {
+	_, err := tx.Exec(ctx, `
+UPDATE users u
+SET rating = s.avg_rating,
+	rating_count = s.review_count,
+	updated_at = now()
+FROM (
+	SELECT COALESCE(round(avg(rv.rating), 2), 0) AS avg_rating, count(*) AS review_count
+	FROM event_reviews rv
+	JOIN events e ON e.id = rv.event_id
+	WHERE e.creator_user_id = $1 AND rv.is_hidden = false
+) s
+WHERE u.id = $1;`, creatorID)
+	return err
+}
+
+// scanEventReview scans a row selected with eventReviewSelect.
+func scanEventReview(row pgx.Row) (models.EventReview, error) {
+	var review models.EventReview
+	var body sql.NullString
+	var hiddenReason sql.NullString
+	if err := row.Scan(
+		&review.ID,
+		&review.EventID,
+		&review.EventTitle,
+		&review.UserID,
+		&review.UserName,
+		&review.Rating,
+		&body,
+		&review.IsHidden,
+		&hiddenReason,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	); err != nil {
+		return models.EventReview{}, err
+	}
+	review.Body = body.String
+	review.HiddenReason = hiddenReason.String
+	return review, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// searchTrigramThreshold is the word similarity needed for a typo-tolerant title/address match.
+const searchTrigramThreshold = "0.4"
+
+// SearchEvents searches visible events by text, ranked by relevance and start-time proximity.
+// Full-text matches use Russian, English and simple configurations; trigram word
+// similarity on title and address catches misspelled queries.
+func (r *Repository) SearchEvents(ctx context.Context, userID int64, text string, limit, offset int, lat, lng *float64, radiusMeters int, filters []string, accessKeys []string) ([]models.Event, error) {
+	query := `
+SELECT e.id, e.title, e.description, e.starts_at, e.ends_at,
+	ST_Y(e.location::geometry) AS lat,
+	ST_X(e.location::geometry) AS lng,
+	e.capacity, e.promoted_until, e.filters, e.links, e.is_private, e.is_landing_published,
+	e.contact_telegram, e.contact_whatsapp, e.contact_wechat, e.contact_fb_messenger, e.contact_snapchat,
+	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS creator_name,
+	(SELECT url FROM event_media WHERE event_id = e.id ORDER BY id ASC LIMIT 1) AS thumbnail_url,
+	(SELECT count(*) FROM event_participants WHERE event_id = e.id) AS participants_count,
+	(SELECT count(*) FROM event_likes WHERE event_id = e.id) AS likes_count,
+	(SELECT count(*) FROM event_comments WHERE event_id = e.id AND is_hidden = false) AS comments_count,
+	(ep.user_id IS NOT NULL) AS is_joined,
+	(SELECT EXISTS(SELECT 1 FROM event_likes WHERE event_id = e.id AND user_id = $1)) AS is_liked,
+	e.series_id
+FROM events e
+JOIN users u ON u.id = e.creator_user_id
+LEFT JOIN event_participants ep ON ep.event_id = e.id AND ep.user_id = $1
+CROSS JOIN LATERAL (
+	SELECT websearch_to_tsquery('russian', $4)
+		|| websearch_to_tsquery('english', $4)
+		|| websearch_to_tsquery('simple', $4) AS tsq
+) q
+WHERE e.is_hidden = false
+	AND e.status = 'published'
+	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()
+	AND ` + nextSeriesOccurrenceCondition(prevOccurrenceRunning) + `
+	AND (
+		e.search_vector @@ q.tsq
+		OR $4 <% e.title
+		OR $4 <% e.address_label
+	)`
+	args := []interface{}{userID, limit, offset, text}
+	privacy := "e.is_private = false OR e.creator_user_id = $1 OR ep.user_id IS NOT NULL"
+	if len(accessKeys) > 0 {
+		keyIdx := len(args) + 1
+		privacy = fmt.Sprintf("%s OR e.access_key = ANY($%d)", privacy, keyIdx)
+		args = append(args, accessKeys)
+	}
+	query += fmt.Sprintf(`
+	AND (%s)`, privacy)
+	if lat != nil && lng != nil && radiusMeters > 0 {
+		lngIdx := len(args) + 1
+		latIdx := len(args) + 2
+		radiusIdx := len(args) + 3
+		query += fmt.Sprintf(`
+	AND ST_DWithin(e.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography, $%d)`, lngIdx, latIdx, radiusIdx)
+		args = append(args, *lng, *lat, radiusMeters)
+	}
+	if len(filters) > 0 {
+		filterIdx := len(args) + 1
+		query += fmt.Sprintf(`
+	AND e.filters && $%d`, filterIdx)
+		args = append(args, filters)
+	}
+	// Relevance is boosted by up to 2x for events starting soon; the boost fades
+	// as 1 / (1 + weeks between now and the start).
+	query += `
+ORDER BY
+	(
+		ts_rank_cd(e.search_vector, q.tsq, 32)
+		+ 0.5 * GREATEST(word_similarity($4, e.title), word_similarity($4, COALESCE(e.address_label, '')))
+	) * (1 + 1 / (1 + abs(extract(epoch FROM e.starts_at - now())) / 604800.0)) DESC,
+	e.starts_at ASC
+LIMIT $2 OFFSET $3;`
+
+	out := make([]models.Event, 0)
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, searchTrigramThreshold); err != nil {
+			return err
+		}
+		rows, err := tx.Query(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			e, err := scanFeedEvent(rows)
+			if err != nil {
+				return err
+			}
+			out = append(out, e)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/models"
+)
+
+// TestSearchEventsVisibility verifies search events visibility behavior.
+func TestSearchEventsVisibility(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	ownerID, err := insertTicketingTestUser(ctx, pool, 778601)
+	if err != nil {
+		t.Fatalf("insert owner: %v", err)
+	}
+	searcherID, err := insertTicketingTestUser(ctx, pool, 778602)
+	if err != nil {
+		t.Fatalf("insert searcher: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE creator_user_id = $1`, ownerID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, []int64{ownerID, searcherID})
+	})
+
+	insert := func(title, status string, private bool, accessKey *string) int64 {
+		var id int64
+		if err := pool.QueryRow(ctx, `
+INSERT INTO events (creator_user_id, title, description, starts_at, location, status, is_private, access_key)
+VALUES ($1, $2, 'Search test', now() + interval '1 day', ST_SetSRID(ST_MakePoint(37.61, 55.75), 4326)::geography, $3, $4, $5)
+RETURNING id;`, ownerID, title, status, private, accessKey).Scan(&id); err != nil {
+			t.Fatalf("insert event %q: %v", title, err)
+		}
+		return id
+	}
+	key := "search_key_778601"
+	publicID := insert("Xylophonia Night", models.EventStatusPublished, false, nil)
+	privateID := insert("Xylophonia Secret", models.EventStatusPublished, true, &key)
+	draftID := insert("Xylophonia Draft", models.EventStatusDraft, false, nil)
+
+	search := func(text string, accessKeys []string) map[int64]bool {
+		items, err := repo.SearchEvents(ctx, searcherID, text, 50, 0, nil, nil, 0, nil, accessKeys)
+		if err != nil {
+			t.Fatalf("SearchEvents(%q): %v", text, err)
+		}
+		found := make(map[int64]bool, len(items))
+		for _, item := range items {
+			found[item.ID] = true
+		}
+		return found
+	}
+
+	found := search("Xylophonia", nil)
+	if !found[publicID] {
+		t.Fatalf("expected public event %d in results", publicID)
+	}
+	if found[privateID] || found[draftID] {
+		t.Fatalf("private or draft event leaked into results: %v", found)
+	}
+
+	found = search("Xylophonia", []string{key})
+	if !found[privateID] {
+		t.Fatalf("expected private event %d with its access key", privateID)
+	}
+	if found[draftID] {
+		t.Fatalf("draft event %d must never be found", draftID)
+	}
+
+	found = search("Xylophonya", nil)
+	if !found[publicID] {
+		t.Fatalf("expected misspelled query to match event %d", publicID)
+	}
+	if found[privateID] || found[draftID] {
+		t.Fatalf("private or draft event leaked into typo results: %v", found)
+	}
+}
@@ -0,0 +1,491 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SeriesDroppedReason is the cancel reason of occurrences dropped by a series edit.
+const SeriesDroppedReason = "Дата убрана из расписания"
+
+// prevOccurrenceRunning matches earlier occurrences that have not ended yet.
+const prevOccurrenceRunning = "COALESCE(prev.ends_at, prev.starts_at + interval '2 hours') >= now()"
+
+// nextSeriesOccurrenceCondition keeps only the next occurrence of a series in
+// listings of e: it rejects e when an earlier visible, published occurrence
+// matching prevFilter exists. prevFilter refers to that occurrence as prev.
+func nextSeriesOccurrenceCondition(prevFilter string) string {
+	return fmt.Sprintf(`(e.series_id IS NULL OR NOT EXISTS (
+		SELECT 1 FROM events prev
+		WHERE prev.series_id = e.series_id
+			AND prev.is_hidden = false
+			AND prev.status = 'published'
+			AND prev.starts_at < e.starts_at
+			AND %s
+	))`, prevFilter)
+}
+
+// ErrSeriesOccurrenceHasSales is returned when a series edit would drop an
+// occurrence that already has paid orders or tickets.
+var ErrSeriesOccurrenceHasSales = errors.New("series occurrence has sales")
+
+// EventSeriesSplit describes a "this and following" edit of a recurring series.
+type EventSeriesSplit struct {
+	SeriesID     int64
+	FromIndex    int
+	PreviousRule string
+	Series       models.EventSeries
+	Event        models.Event
+	EventIDs     []int64
+	Starts       []time.Time
+	Media        []string
+	ReplaceMedia bool
+	ActorID      int64
+}
+
+// CreateEventSeries creates a series and materializes its first occurrences.
+// The first start is stored from event itself; the rest are copied from it.
+func (r *Repository) CreateEventSeries(ctx context.Context, series models.EventSeries, event models.Event, media []string, starts []time.Time) (int64, []int64, error) {
+	if len(starts) == 0 {
+		return 0, nil, pgx.ErrNoRows
+	}
+	filters := event.Filters
+	if filters == nil {
+		filters = []string{}
+	}
+	links := event.Links
+	if links == nil {
+		links = []string{}
+	}
+	var seriesID int64
+	eventIDs := make([]int64, 0, len(starts))
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		row := tx.QueryRow(ctx, `
+INSERT INTO event_series (creator_user_id, rrule, timezone, starts_at, duration_seconds, materialized_until, materialized_count, is_active)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id;`,
+			series.CreatorUserID,
+			series.RRule,
+			series.Timezone,
+			series.StartsAt,
+			series.DurationSeconds,
+			series.MaterializedUntil,
+			len(starts),
+			series.IsActive,
+		)
+		if err := row.Scan(&seriesID); err != nil {
+			return err
+		}
+
+		var firstID int64
+		row = tx.QueryRow(ctx, `
+INSERT INTO events (
+	creator_user_id, title, description, starts_at, ends_at, location, address_label,
+	contact_telegram, contact_whatsapp, contact_wechat, contact_fb_messenger, contact_snapchat,
+	capacity, is_hidden, is_private, access_key, promoted_until, filters, links,
+	series_id, series_index, status, publish_at, published_at
+) VALUES (
+	$1, $2, $3, $4, $5,
+	ST_SetSRID(ST_MakePoint($6, $7), 4326)::geography,
+	$8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20,
+	$21, 0, $22, $23, CASE WHEN $22 = 'published' THEN now() END
+) RETURNING id;`,
+			event.CreatorUserID,
+			event.Title,
+			event.Description,
+			starts[0],
+			seriesEndsAt(starts[0], series.DurationSeconds),
+			event.Lng,
+			event.Lat,
+			nullString(event.AddressLabel),
+			nullString(event.ContactTelegram),
+			nullString(event.ContactWhatsapp),
+			nullString(event.ContactWechat),
+			nullString(event.ContactFbMessenger),
+			nullString(event.ContactSnapchat),
+			event.Capacity,
+			event.IsHidden,
+			event.IsPrivate,
+			nullString(event.AccessKey),
+			event.PromotedUntil,
+			filters,
+			links,
+			seriesID,
+			eventStatus(event.Status),
+			event.PublishAt,
+		)
+		if err := row.Scan(&firstID); err != nil {
+			return err
+		}
+		for _, url := range media {
+			if _, err := tx.Exec(ctx, `INSERT INTO event_media (event_id, url, type) VALUES ($1, $2, 'image')`, firstID, url); err != nil {
+				return err
+			}
+		}
+		eventIDs = append(eventIDs, firstID)
+
+		for i, startsAt := range starts[1:] {
+			id, err := copyEventOccurrence(ctx, tx, firstID, seriesID, i+1, startsAt, seriesEndsAt(startsAt, series.DurationSeconds))
+			if err != nil {
+				return err
+			}
+			eventIDs = append(eventIDs, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return seriesID, eventIDs, nil
+}
+
+// GetEventSeries returns event series.
+func (r *Repository) GetEventSeries(ctx context.Context, seriesID int64) (models.EventSeries, error) {
+	row := r.pool.QueryRow(ctx, `
+SELECT id, creator_user_id, rrule, timezone, starts_at, duration_seconds,
+	materialized_until, materialized_count, is_active, created_at, updated_at
+FROM event_series
+WHERE id = $1;`, seriesID)
+	return scanEventSeries(row)
+}
+
+// ListEventSeriesToMaterialize lists active series whose occurrences end before horizon.
+func (r *Repository) ListEventSeriesToMaterialize(ctx context.Context, horizon time.Time, limit int) ([]models.EventSeries, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	rows, err := r.pool.Query(ctx, `
+SELECT id, creator_user_id, rrule, timezone, starts_at, duration_seconds,
+	materialized_until, materialized_count, is_active, created_at, updated_at
+FROM event_series
+WHERE is_active = true
+	AND materialized_until < $1
+ORDER BY materialized_until ASC
+LIMIT $2;`, horizon, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.EventSeries, 0)
+	for rows.Next() {
+		item, err := scanEventSeries(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, item)
+	}
+	return out, rows.Err()
+}
+
+// AppendEventSeriesOccurrences materializes further occurrences of a series.
+// The latest regular occurrence that is not canceled is used as the template for title, media and products.
+func (r *Repository) AppendEventSeriesOccurrences(ctx context.Context, series models.EventSeries, starts []time.Time, materializedUntil time.Time, active bool) ([]int64, error) {
+	eventIDs := make([]int64, 0, len(starts))
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var templateID int64
+		err := tx.QueryRow(ctx, `
+SELECT id
+FROM events
+WHERE series_id = $1 AND status <> 'canceled'
+ORDER BY is_series_exception ASC, series_index DESC
+LIMIT 1;`, series.ID).Scan(&templateID)
+		if err == pgx.ErrNoRows {
+			// Every occurrence was deleted or canceled: nothing left to copy from.
+			_, err = tx.Exec(ctx, `UPDATE event_series SET is_active = false, updated_at = now() WHERE id = $1`, series.ID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+		for i, startsAt := range starts {
+			id, err := copyEventOccurrence(ctx, tx, templateID, series.ID, series.MaterializedCount+i, startsAt, seriesEndsAt(startsAt, series.DurationSeconds))
+			if err != nil {
+				return err
+			}
+			eventIDs = append(eventIDs, id)
+		}
+		_, err = tx.Exec(ctx, `
+UPDATE event_series
+SET materialized_until = $2,
+	materialized_count = $3,
+	is_active = $4,
+	updated_at = now()
+WHERE id = $1;`, series.ID, materializedUntil, series.MaterializedCount+len(starts), active)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return eventIDs, nil
+}
+
+// ListEventSeriesIDsFrom lists occurrence ids of a series starting at index.
+func (r *Repository) ListEventSeriesIDsFrom(ctx context.Context, seriesID int64, fromIndex int) ([]int64, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT id
+FROM events
+WHERE series_id = $1 AND series_index >= $2
+ORDER BY series_index ASC;`, seriesID, fromIndex)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// SetEventSeriesException marks an occurrence as individually edited.
+func (r *Repository) SetEventSeriesException(ctx context.Context, eventID int64) error {
+	command, err := r.pool.Exec(ctx, `UPDATE events SET is_series_exception = true, updated_at = now() WHERE id = $1 AND series_id IS NOT NULL`, eventID)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// SplitEventSeries ends the old series before FromIndex and moves the following
+// occurrences into a new series, applying the edited content and new start times.
+// Occurrences without a matching new start are canceled and detached from the
+// series; the split is refused if any of them has paid orders or tickets.
+// It returns the new series id and the ids of the canceled occurrences.
+func (r *Repository) SplitEventSeries(ctx context.Context, split EventSeriesSplit) (int64, []int64, error) {
+	filters := split.Event.Filters
+	if filters == nil {
+		filters = []string{}
+	}
+	var dropped []int64
+	if len(split.EventIDs) > len(split.Starts) {
+		dropped = split.EventIDs[len(split.Starts):]
+	}
+	var newSeriesID int64
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		if len(dropped) > 0 {
+			var hasSales bool
+			if err := tx.QueryRow(ctx, `
+SELECT EXISTS (
+	SELECT 1 FROM orders WHERE event_id = ANY($1) AND status IN ($2, $3)
+) OR EXISTS (
+	SELECT 1 FROM tickets WHERE event_id = ANY($1)
+);`, dropped, models.OrderStatusPaid, models.OrderStatusRedeemed).Scan(&hasSales); err != nil {
+				return err
+			}
+			if hasSales {
+				return ErrSeriesOccurrenceHasSales
+			}
+		}
+
+		command, err := tx.Exec(ctx, `
+UPDATE event_series
+SET rrule = $2,
+	materialized_count = $3,
+	is_active = false,
+	updated_at = now()
+WHERE id = $1;`, split.SeriesID, split.PreviousRule, split.FromIndex)
+		if err != nil {
+			return err
+		}
+		if command.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+
+		row := tx.QueryRow(ctx, `
+INSERT INTO event_series (creator_user_id, rrule, timezone, starts_at, duration_seconds, materialized_until, materialized_count, is_active)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id;`,
+			split.Series.CreatorUserID,
+			split.Series.RRule,
+			split.Series.Timezone,
+			split.Series.StartsAt,
+			split.Series.DurationSeconds,
+			split.Series.MaterializedUntil,
+			len(split.Starts),
+			split.Series.IsActive,
+		)
+		if err := row.Scan(&newSeriesID); err != nil {
+			return err
+		}
+
+		for k, eventID := range split.EventIDs {
+			if k >= len(split.Starts) {
+				if _, err := tx.Exec(ctx, `
+UPDATE events
+SET status = 'canceled',
+	canceled_at = COALESCE(canceled_at, now()),
+	cancel_reason = COALESCE(cancel_reason, $2),
+	series_id = NULL,
+	series_index = NULL,
+	is_series_exception = false,
+	revision = revision + 1,
+	updated_at = now()
+WHERE id = $1;`, eventID, SeriesDroppedReason); err != nil {
+					return err
+				}
+				if _, err := cancelEventSalesTx(ctx, tx, eventID, split.ActorID, SeriesDroppedReason); err != nil {
+					return err
+				}
+				continue
+			}
+			startsAt := split.Starts[k]
+			if _, err := tx.Exec(ctx, `
+UPDATE events SET
+	title = $1,
+	description = $2,
+	starts_at = $3,
+	ends_at = $4,
+	location = ST_SetSRID(ST_MakePoint($5, $6), 4326)::geography,
+	address_label = $7,
+	contact_telegram = $8,
+	contact_whatsapp = $9,
+	contact_wechat = $10,
+	contact_fb_messenger = $11,
+	contact_snapchat = $12,
+	capacity = $13,
+	filters = $14,
+	series_id = $15,
+	series_index = $16,
+	is_series_exception = false,
+	revision = revision + 1,
+	updated_at = now()
+WHERE id = $17;`,
+				split.Event.Title,
+				split.Event.Description,
+				startsAt,
+				seriesEndsAt(startsAt, split.Series.DurationSeconds),
+				split.Event.Lng,
+				split.Event.Lat,
+				nullString(split.Event.AddressLabel),
+				nullString(split.Event.ContactTelegram),
+				nullString(split.Event.ContactWhatsapp),
+				nullString(split.Event.ContactWechat),
+				nullString(split.Event.ContactFbMessenger),
+				nullString(split.Event.ContactSnapchat),
+				split.Event.Capacity,
+				filters,
+				newSeriesID,
+				k,
+				eventID,
+			); err != nil {
+				return err
+			}
+			if !split.ReplaceMedia {
+				continue
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM event_media WHERE event_id = $1`, eventID); err != nil {
+				return err
+			}
+			for _, url := range split.Media {
+				if _, err := tx.Exec(ctx, `INSERT INTO event_media (event_id, url, type) VALUES ($1, $2, 'image')`, eventID, url); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return newSeriesID, dropped, nil
+}
+
+// copyEventOccurrence copies template event with its media and products as a new occurrence.
+// A private occurrence keeps the access key of the template, so one invite link opens the whole series.
+func copyEventOccurrence(ctx context.Context, tx pgx.Tx, templateID, seriesID int64, index int, startsAt time.Time, endsAt *time.Time) (int64, error) {
+	var eventID int64
+	err := tx.QueryRow(ctx, `
+INSERT INTO events (
+	creator_user_id, title, description, starts_at, ends_at, location, address_label,
+	contact_telegram, contact_whatsapp, contact_wechat, contact_fb_messenger, contact_snapchat,
+	capacity, is_hidden, is_private, access_key, filters, links,
+	series_id, series_index, status, publish_at, published_at
+)
+SELECT creator_user_id, title, description, $2, $3, location, address_label,
+	contact_telegram, contact_whatsapp, contact_wechat, contact_fb_messenger, contact_snapchat,
+	capacity, is_hidden, is_private, access_key, filters, links,
+	$4, $5, status, publish_at, published_at
+FROM events
+WHERE id = $1
+RETURNING id;`, templateID, startsAt, endsAt, seriesID, index).Scan(&eventID)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO event_media (event_id, url, type)
+SELECT $2, url, type FROM event_media WHERE event_id = $1 ORDER BY id ASC;`, templateID, eventID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO ticket_products (event_id, type, name, price_cents, inventory_limit, is_active, created_by)
+SELECT $2, type, name, price_cents, inventory_limit, is_active, created_by
+FROM ticket_products
+WHERE event_id = $1;`, templateID, eventID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO transfer_products (event_id, direction, name, price_cents, info_json, inventory_limit, is_active, created_by)
+SELECT $2, direction, name, price_cents, info_json, inventory_limit, is_active, created_by
+FROM transfer_products
+WHERE event_id = $1;`, templateID, eventID); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(ctx, `
+INSERT INTO event_participants (event_id, user_id, status)
+SELECT id, creator_user_id, 'joined' FROM events WHERE id = $1
+ON CONFLICT DO NOTHING;`, eventID); err != nil {
+		return 0, err
+	}
+	return eventID, nil
+}
+
+// seriesEndsAt returns occurrence end for series duration.
+func seriesEndsAt(startsAt time.Time, durationSeconds *int) *time.Time {
+	if durationSeconds == nil {
+		return nil
+	}
+	endsAt := startsAt.Add(time.Duration(*durationSeconds) * time.Second)
+	return &endsAt
+}
+
+// scanEventSeries scans event series row.
+func scanEventSeries(row pgx.Row) (models.EventSeries, error) {
+	var item models.EventSeries
+	var duration sql.NullInt32
+	if err := row.Scan(
+		&item.ID,
+		&item.CreatorUserID,
+		&item.RRule,
+		&item.Timezone,
+		&item.StartsAt,
+		&duration,
+		&item.MaterializedUntil,
+		&item.MaterializedCount,
+		&item.IsActive,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	); err != nil {
+		return models.EventSeries{}, err
+	}
+	if duration.Valid {
+		value := int(duration.Int32)
+		item.DurationSeconds = &value
+	}
+	return item, nil
+}
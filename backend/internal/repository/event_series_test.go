@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/models"
+)
+
+// TestSplitEventSeriesKeepsOccurrencesWithSales verifies split event series keeps occurrences with sales behavior.
+func TestSplitEventSeriesKeepsOccurrencesWithSales(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778301)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	starts := []time.Time{first, first.AddDate(0, 0, 7), first.AddDate(0, 0, 14)}
+	duration := 3600
+	series := models.EventSeries{
+		CreatorUserID:     userID,
+		RRule:             "FREQ=WEEKLY;COUNT=3",
+		Timezone:          "Europe/Moscow",
+		StartsAt:          first,
+		DurationSeconds:   &duration,
+		MaterializedUntil: starts[2],
+	}
+	event := models.Event{CreatorUserID: userID, Title: "Series Test", Description: "Test series", Lat: 55.75, Lng: 37.61, Status: models.EventStatusPublished}
+	seriesID, eventIDs, err := repo.CreateEventSeries(ctx, series, event, nil, starts)
+	if err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+	orderID, _, err := insertPaidOrderWithTicketItem(ctx, pool, userID, eventIDs[2], 1)
+	if err != nil {
+		t.Fatalf("insert paid order: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM orders WHERE id = $1::uuid`, orderID)
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM event_series WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	split := EventSeriesSplit{
+		SeriesID:     seriesID,
+		FromIndex:    1,
+		PreviousRule: "FREQ=WEEKLY;COUNT=1",
+		Series:       series,
+		Event:        event,
+		EventIDs:     eventIDs[1:],
+		Starts:       starts[1:2],
+		ActorID:      userID,
+	}
+	split.Series.RRule = "FREQ=WEEKLY;COUNT=1"
+	if _, _, err := repo.SplitEventSeries(ctx, split); !errors.Is(err, ErrSeriesOccurrenceHasSales) {
+		t.Fatalf("expected ErrSeriesOccurrenceHasSales, got %v", err)
+	}
+
+	var status string
+	var seriesIndex *int
+	if err := pool.QueryRow(ctx, `SELECT status, series_index FROM events WHERE id = $1`, eventIDs[2]).Scan(&status, &seriesIndex); err != nil {
+		t.Fatalf("occurrence with an order must survive the split: %v", err)
+	}
+	if status != models.EventStatusPublished || seriesIndex == nil || *seriesIndex != 2 {
+		t.Fatalf("expected untouched occurrence, got status=%s index=%v", status, seriesIndex)
+	}
+
+	if _, err := pool.Exec(ctx, `UPDATE orders SET status = $2 WHERE id = $1::uuid`, orderID, models.OrderStatusPending); err != nil {
+		t.Fatalf("make order pending: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `DELETE FROM tickets WHERE order_id = $1::uuid`, orderID); err != nil {
+		t.Fatalf("delete tickets: %v", err)
+	}
+	_, canceled, err := repo.SplitEventSeries(ctx, split)
+	if err != nil {
+		t.Fatalf("SplitEventSeries(): %v", err)
+	}
+	if len(canceled) != 1 || canceled[0] != eventIDs[2] {
+		t.Fatalf("expected occurrence %d to be canceled, got %v", eventIDs[2], canceled)
+	}
+
+	var seriesRef *int64
+	if err := pool.QueryRow(ctx, `SELECT status, series_id FROM events WHERE id = $1`, eventIDs[2]).Scan(&status, &seriesRef); err != nil {
+		t.Fatalf("dropped occurrence must not be deleted: %v", err)
+	}
+	if status != models.EventStatusCanceled || seriesRef != nil {
+		t.Fatalf("expected canceled detached occurrence, got status=%s series=%v", status, seriesRef)
+	}
+	var orderStatus string
+	if err := pool.QueryRow(ctx, `SELECT status FROM orders WHERE id = $1::uuid`, orderID).Scan(&orderStatus); err != nil {
+		t.Fatalf("order status: %v", err)
+	}
+	if orderStatus != models.OrderStatusCanceled {
+		t.Fatalf("expected pending order to be canceled, got %s", orderStatus)
+	}
+}
+
+// TestCreateEventSeriesSharesPrivateAccessKey verifies create event series shares private access key behavior.
+func TestCreateEventSeriesSharesPrivateAccessKey(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778304)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM event_series WHERE creator_user_id = $1`, userID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	first := time.Now().Add(24 * time.Hour).Truncate(time.Second)
+	starts := []time.Time{first, first.AddDate(0, 0, 7), first.AddDate(0, 0, 14)}
+	series := models.EventSeries{
+		CreatorUserID:     userID,
+		RRule:             "FREQ=WEEKLY;COUNT=3",
+		Timezone:          "Europe/Moscow",
+		StartsAt:          first,
+		MaterializedUntil: starts[2],
+	}
+	event := models.Event{CreatorUserID: userID, Title: "Private Series", Description: "Test series", Lat: 55.75, Lng: 37.61, IsPrivate: true, AccessKey: "series_key_778304", Status: models.EventStatusPublished}
+	if _, _, err := repo.CreateEventSeries(ctx, series, event, nil, starts); err != nil {
+		t.Fatalf("create series: %v", err)
+	}
+
+	var keys []string
+	if err := pool.QueryRow(ctx, `SELECT array_agg(DISTINCT access_key) FROM events WHERE creator_user_id = $1`, userID).Scan(&keys); err != nil {
+		t.Fatalf("load access keys: %v", err)
+	}
+	if len(keys) != 1 || keys[0] != event.AccessKey {
+		t.Fatalf("expected every occurrence to share key %q, got %v", event.AccessKey, keys)
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// eventStaffSelect selects staff members joined with their user profile.
+const eventStaffSelect = `
+SELECT s.event_id, s.user_id, s.role, u.username, u.first_name, u.last_name, u.photo_url,
+	s.invited_by, s.created_at, s.updated_at
+FROM event_staff s
+JOIN users u ON u.id = s.user_id`
+
+// GetEventAccessRole returns the user's role on an event: owner, a staff role, or empty.
+// It returns pgx.ErrNoRows when the event does not exist.
+func (r *Repository) GetEventAccessRole(ctx context.Context, eventID, userID int64) (string, error) {
+	var role string
+	err := r.pool.QueryRow(ctx, `
+SELECT CASE WHEN e.creator_user_id = $2 THEN 'owner' ELSE COALESCE(s.role, '') END
+FROM events e
+LEFT JOIN event_staff s ON s.event_id = e.id AND s.user_id = $2
+WHERE e.id = $1;`, eventID, userID).Scan(&role)
+	return role, err
+}
+
+// GetOrderEventID returns the event an order belongs to.
+func (r *Repository) GetOrderEventID(ctx context.Context, orderID string) (int64, error) {
+	var eventID int64
+	err := r.pool.QueryRow(ctx, `SELECT event_id FROM orders WHERE id = $1::uuid`, orderID).Scan(&eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrOrderNotFound
+	}
+	return eventID, err
+}
+
+// GetTicketEventID returns the event a ticket belongs to.
+func (r *Repository) GetTicketEventID(ctx context.Context, ticketID string) (int64, error) {
+	var eventID int64
+	err := r.pool.QueryRow(ctx, `SELECT event_id FROM tickets WHERE id = $1::uuid`, ticketID).Scan(&eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrTicketNotFound
+	}
+	return eventID, err
+}
+
+// GetUserIDByUsername returns the user with a Telegram username (case-insensitive).
+func (r *Repository) GetUserIDByUsername(ctx context.Context, username string) (int64, error) {
+	var userID int64
+	err := r.pool.QueryRow(ctx, `
+SELECT id
+FROM users
+WHERE lower(username) = lower($1)
+ORDER BY id ASC
+LIMIT 1;`, strings.TrimPrefix(strings.TrimSpace(username), "@")).Scan(&userID)
+	return userID, err
+}
+
+// UpsertEventStaff adds a staff member to an event or changes their role.
+func (r *Repository) UpsertEventStaff(ctx context.Context, eventID, userID int64, role string, invitedBy int64) (models.EventStaffMember, error) {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO event_staff (event_id, user_id, role, invited_by)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (event_id, user_id) DO UPDATE
+SET role = EXCLUDED.role,
+	invited_by = EXCLUDED.invited_by,
+	updated_at = now();`, eventID, userID, role, invitedBy)
+	if err != nil {
+		return models.EventStaffMember{}, err
+	}
+	row := r.pool.QueryRow(ctx, eventStaffSelect+`
+WHERE s.event_id = $1 AND s.user_id = $2;`, eventID, userID)
+	return scanEventStaffMember(row)
+}
+
+// ListEventStaff lists staff members of an event.
+func (r *Repository) ListEventStaff(ctx context.Context, eventID int64) ([]models.EventStaffMember, error) {
+	rows, err := r.pool.Query(ctx, eventStaffSelect+`
+WHERE s.event_id = $1
+ORDER BY s.created_at ASC, s.user_id ASC;`, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.EventStaffMember, 0)
+	for rows.Next() {
+		member, err := scanEventStaffMember(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, member)
+	}
+	return out, rows.Err()
+}
+
+// RemoveEventStaff removes a staff member from an event.
+func (r *Repository) RemoveEventStaff(ctx context.Context, eventID, userID int64) error {
+	command, err := r.pool.Exec(ctx, `DELETE FROM event_staff WHERE event_id = $1 AND user_id = $2`, eventID, userID)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// scanEventStaffMember scans a row selected with eventStaffSelect.
+func scanEventStaffMember(row pgx.Row) (models.EventStaffMember, error) {
+	var member models.EventStaffMember
+	var username sql.NullString
+	var lastName sql.NullString
+	var photoURL sql.NullString
+	if err := row.Scan(
+		&member.EventID,
+		&member.UserID,
+		&member.Role,
+		&username,
+		&member.FirstName,
+		&lastName,
+		&photoURL,
+		&member.InvitedBy,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	); err != nil {
+		return models.EventStaffMember{}, err
+	}
+	member.Username = username.String
+	member.LastName = lastName.String
+	member.PhotoURL = photoURL.String
+	return member, nil
+}
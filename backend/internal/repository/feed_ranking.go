@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FeedRanking holds weights and scales of the personalized feed score.
+// Every component is normalized to [0, 1] before weighting.
+type FeedRanking struct {
+	DistanceWeight   float64
+	TimeWeight       float64
+	PopularityWeight float64
+	AffinityWeight   float64
+	// DistanceScaleKm is the distance at which the distance component halves.
+	DistanceScaleKm float64
+	// TimeScaleHours is the time until start at which the time component halves.
+	TimeScaleHours float64
+}
+
+// feedAffinityCTE collects the user's filter tags from joined, liked and bought events.
+// Purchases count twice; joins of the user's own events are ignored.
+const feedAffinityCTE = `
+WITH affinity AS (
+	SELECT tag, sum(weight)::float8 AS weight
+	FROM (
+		SELECT unnest(ae.filters) AS tag, 1 AS weight
+		FROM event_participants ap
+		JOIN events ae ON ae.id = ap.event_id
+		WHERE ap.user_id = $1 AND ae.creator_user_id <> $1
+		UNION ALL
+		SELECT unnest(ae.filters), 1
+		FROM event_likes al
+		JOIN events ae ON ae.id = al.event_id
+		WHERE al.user_id = $1
+		UNION ALL
+		SELECT unnest(ae.filters), 2
+		FROM tickets pt
+		JOIN orders po ON po.id = pt.order_id
+		JOIN events ae ON ae.id = pt.event_id
+		WHERE pt.user_id = $1 AND po.status IN ('PAID', 'REDEEMED')
+	) signals
+	GROUP BY tag
+), affinity_total AS (
+	SELECT GREATEST(COALESCE(sum(weight), 0), 1) AS total FROM affinity
+)`
+
+// feedStatsJoin adds popularity counters used by the ranked feed.
+const feedStatsJoin = `
+CROSS JOIN LATERAL (
+	SELECT
+		(SELECT count(*) FROM event_participants WHERE event_id = e.id) AS participants,
+		(SELECT count(*) FROM event_likes WHERE event_id = e.id) AS likes,
+		(SELECT count(*) FROM event_comments WHERE event_id = e.id AND is_hidden = false AND created_at >= now() - interval '24 hours') AS recent_comments
+) stats`
+
+// feedScoreExpression builds the SQL score of the ranked feed and appends its args.
+func feedScoreExpression(ranking FeedRanking, lat, lng *float64, args []interface{}) (string, []interface{}) {
+	distanceScale := ranking.DistanceScaleKm
+	if distanceScale <= 0 {
+		distanceScale = 1
+	}
+	timeScale := ranking.TimeScaleHours
+	if timeScale <= 0 {
+		timeScale = 1
+	}
+	parts := make([]string, 0, 4)
+
+	if lat != nil && lng != nil && ranking.DistanceWeight > 0 {
+		base := len(args)
+		parts = append(parts, fmt.Sprintf(
+			"$%d::float8 / (1 + ST_Distance(e.location, ST_SetSRID(ST_MakePoint($%d, $%d), 4326)::geography) / 1000.0 / $%d::float8)",
+			base+1, base+2, base+3, base+4,
+		))
+		args = append(args, ranking.DistanceWeight, *lng, *lat, distanceScale)
+	}
+	if ranking.TimeWeight > 0 {
+		base := len(args)
+		parts = append(parts, fmt.Sprintf(
+			"$%d::float8 / (1 + GREATEST(extract(epoch FROM e.starts_at - now()), 0) / 3600.0 / $%d::float8)",
+			base+1, base+2,
+		))
+		args = append(args, ranking.TimeWeight, timeScale)
+	}
+	if ranking.PopularityWeight > 0 {
+		// Comment velocity (last 24h) weighs twice; the sum is squashed into [0, 1).
+		popularity := "(ln(1 + stats.likes) + ln(1 + stats.participants) + 2 * ln(1 + stats.recent_comments))"
+		parts = append(parts, fmt.Sprintf("$%d::float8 * %s / (%s + 3)", len(args)+1, popularity, popularity))
+		args = append(args, ranking.PopularityWeight)
+	}
+	if ranking.AffinityWeight > 0 {
+		parts = append(parts, fmt.Sprintf(
+			"$%d::float8 * COALESCE((SELECT sum(a.weight) FROM affinity a WHERE a.tag = ANY(e.filters)), 0) / (SELECT total FROM affinity_total)",
+			len(args)+1,
+		))
+		args = append(args, ranking.AffinityWeight)
+	}
+	if len(parts) == 0 {
+		return "0", args
+	}
+	return "(" + strings.Join(parts, "\n\t\t+ ") + ")", args
+}
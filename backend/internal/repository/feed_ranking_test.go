@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestFeedScoreExpressionPlaceholders verifies feed score expression placeholders behavior.
+func TestFeedScoreExpressionPlaceholders(t *testing.T) {
+	lat, lng := 55.75, 37.61
+	args := []interface{}{int64(1), 20, 0}
+	score, args := feedScoreExpression(FeedRanking{
+		DistanceWeight:   1,
+		TimeWeight:       1,
+		PopularityWeight: 0.5,
+		AffinityWeight:   2,
+		DistanceScaleKm:  5,
+		TimeScaleHours:   48,
+	}, &lat, &lng, args)
+
+	if len(args) != 3+4+2+1+1 {
+		t.Fatalf("unexpected args count %d", len(args))
+	}
+	for i := 4; i <= len(args); i++ {
+		if !strings.Contains(score, "$"+strconv.Itoa(i)) {
+			t.Fatalf("placeholder $%d missing from score: %s", i, score)
+		}
+	}
+	if args[4] != lng || args[5] != lat {
+		t.Fatalf("expected lng/lat order for ST_MakePoint, got %v %v", args[4], args[5])
+	}
+}
+
+// TestFeedScoreExpressionSkipsDisabledParts verifies feed score expression skips disabled parts behavior.
+func TestFeedScoreExpressionSkipsDisabledParts(t *testing.T) {
+	score, args := feedScoreExpression(FeedRanking{TimeWeight: 1}, nil, nil, nil)
+	if strings.Contains(score, "ST_Distance") || strings.Contains(score, "affinity") || strings.Contains(score, "stats.") {
+		t.Fatalf("unexpected disabled components in score: %s", score)
+	}
+	if len(args) != 2 {
+		t.Fatalf("expected weight and scale args, got %d", len(args))
+	}
+	if score, _ := feedScoreExpression(FeedRanking{}, nil, nil, nil); score != "0" {
+		t.Fatalf("expected constant score without weights, got %s", score)
+	}
+}
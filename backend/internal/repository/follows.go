@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// FollowUser makes follower follow followee. It returns false when the
+// follow already existed and pgx.ErrNoRows when followee does not exist.
+func (r *Repository) FollowUser(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	var created bool
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var id int64
+		if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 AND is_blocked = false`, followeeID).Scan(&id); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx, `
+INSERT INTO user_follows (follower_user_id, followee_user_id)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;`, followerID, followeeID)
+		if err != nil {
+			return err
+		}
+		created = tag.RowsAffected() > 0
+		return nil
+	})
+	return created, err
+}
+
+// UnfollowUser removes a follow and reports whether it existed.
+func (r *Repository) UnfollowUser(ctx context.Context, followerID, followeeID int64) (bool, error) {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM user_follows WHERE follower_user_id = $1 AND followee_user_id = $2`, followerID, followeeID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetPublicProfile returns the public profile of a user as seen by viewerID.
+// Blocked users are reported as pgx.ErrNoRows.
+func (r *Repository) GetPublicProfile(ctx context.Context, userID, viewerID int64) (models.PublicProfile, error) {
+	var out models.PublicProfile
+	var username sql.NullString
+	var lastName sql.NullString
+	var photoURL sql.NullString
+	err := r.pool.QueryRow(ctx, `
+SELECT u.id, u.username, u.first_name, u.last_name, u.photo_url, u.rating, u.rating_count,
+	(SELECT count(*) FROM user_follows WHERE followee_user_id = u.id) AS followers_count,
+	(SELECT count(*) FROM user_follows WHERE follower_user_id = u.id) AS following_count,
+	(SELECT count(*) FROM events e
+		WHERE e.creator_user_id = u.id AND e.status = 'published'
+			AND e.is_hidden = false AND e.is_private = false) AS events_count,
+	EXISTS (SELECT 1 FROM user_follows WHERE follower_user_id = $2 AND followee_user_id = u.id) AS is_following
+FROM users u
+WHERE u.id = $1 AND u.is_blocked = false;`, userID, viewerID).Scan(
+		&out.ID,
+		&username,
+		&out.FirstName,
+		&lastName,
+		&photoURL,
+		&out.Rating,
+		&out.RatingCount,
+		&out.FollowersCount,
+		&out.FollowingCount,
+		&out.EventsCount,
+		&out.IsFollowing,
+	)
+	if err != nil {
+		return models.PublicProfile{}, err
+	}
+	out.Username = username.String
+	out.LastName = lastName.String
+	out.PhotoURL = photoURL.String
+	return out, nil
+}
+
+// ListFollowing lists organizers the user follows, most recently followed first.
+func (r *Repository) ListFollowing(ctx context.Context, userID int64, limit, offset int) ([]models.FollowedUser, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `SELECT count(*) FROM user_follows WHERE follower_user_id = $1`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.pool.Query(ctx, `
+SELECT u.id, COALESCE(u.first_name || ' ' || u.last_name, u.first_name), u.username, u.photo_url,
+	(SELECT count(*) FROM user_follows WHERE followee_user_id = u.id) AS followers_count,
+	f.created_at
+FROM user_follows f
+JOIN users u ON u.id = f.followee_user_id
+WHERE f.follower_user_id = $1
+ORDER BY f.created_at DESC, u.id DESC
+LIMIT $2 OFFSET $3;`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.FollowedUser, 0)
+	for rows.Next() {
+		var item models.FollowedUser
+		var username sql.NullString
+		var photoURL sql.NullString
+		if err := rows.Scan(&item.UserID, &item.Name, &username, &photoURL, &item.FollowersCount, &item.FollowedAt); err != nil {
+			return nil, 0, err
+		}
+		item.Username = username.String
+		item.PhotoURL = photoURL.String
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strconv"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// friendsCTE selects mutual contacts of $1 as friend_id.
+const friendsCTE = `
+WITH friends AS (
+	SELECT m.contact_user_id AS friend_id
+	FROM user_contact_matches m
+	JOIN user_contact_matches b ON b.user_id = m.contact_user_id AND b.contact_user_id = m.user_id
+	WHERE m.user_id = $1
+)`
+
+// contactHash keys a contact value with the server secret, so stored hashes
+// can't be reversed by hashing every phone number or Telegram id.
+func contactHash(secret, kind, value string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(kind + ":" + value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// PhoneContactHash returns the stored form of a client phone hash.
+func PhoneContactHash(secret, phoneHash string) string {
+	return contactHash(secret, "phone", phoneHash)
+}
+
+// TelegramContactHash returns the stored form of a Telegram id uploaded as a contact.
+func TelegramContactHash(secret string, telegramID int64) string {
+	return contactHash(secret, "telegram", strconv.FormatInt(telegramID, 10))
+}
+
+// SaveContacts stores uploaded contact hashes of the user and links the user to
+// everyone already registered with one of them. Phone hashes and Telegram ids
+// are stored keyed with secret. It returns the number of mutual friends.
+func (r *Repository) SaveContacts(ctx context.Context, userID int64, phoneHashes []string, telegramIDs []int64, secret string) (int, error) {
+	phoneKeys := make([]string, 0, len(phoneHashes))
+	for _, hash := range phoneHashes {
+		phoneKeys = append(phoneKeys, PhoneContactHash(secret, hash))
+	}
+	telegramHashes := make([]string, 0, len(telegramIDs))
+	for _, id := range telegramIDs {
+		telegramHashes = append(telegramHashes, TelegramContactHash(secret, id))
+	}
+	var friends int
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `
+INSERT INTO user_contact_hashes (user_id, kind, value_hash)
+SELECT $1, 'phone', h FROM unnest($2::text[]) AS h
+UNION
+SELECT $1, 'telegram', h FROM unnest($3::text[]) AS h
+ON CONFLICT DO NOTHING;`, userID, phoneKeys, telegramHashes); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+INSERT INTO user_contact_matches (user_id, contact_user_id)
+SELECT $1, u.id
+FROM users u
+WHERE u.id <> $1
+	AND (u.phone_hash = ANY($2::text[]) OR u.telegram_id = ANY($3::bigint[]))
+ON CONFLICT DO NOTHING;`, userID, phoneKeys, telegramIDs); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx, friendsCTE+`
+SELECT count(*) FROM friends;`, userID).Scan(&friends)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return friends, nil
+}
+
+// ClearContacts removes uploaded contacts of the user and the links made from them.
+// Links made by other users to this user stay.
+func (r *Repository) ClearContacts(ctx context.Context, userID int64) error {
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM user_contact_hashes WHERE user_id = $1`, userID); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM user_contact_matches WHERE user_id = $1`, userID)
+		return err
+	})
+}
+
+// SetUserPhoneHash stores the verified phone of the user, keyed like uploaded
+// contacts, and links the user to everyone who uploaded it. A number verified
+// by a new account moves to it from the previous owner.
+func (r *Repository) SetUserPhoneHash(ctx context.Context, userID int64, phoneKey string) error {
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `UPDATE users SET phone_hash = NULL, updated_at = now() WHERE phone_hash = $2 AND id <> $1`, userID, phoneKey); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `UPDATE users SET phone_hash = $2, updated_at = now() WHERE id = $1`, userID, phoneKey); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `
+INSERT INTO user_contact_matches (user_id, contact_user_id)
+SELECT user_id, $1
+FROM user_contact_hashes
+WHERE kind = 'phone' AND value_hash = $2 AND user_id <> $1
+ON CONFLICT DO NOTHING;`, userID, phoneKey)
+		return err
+	})
+}
+
+// MatchNewUserContacts links a newly registered user to everyone who uploaded
+// their Telegram id before they joined.
+func (r *Repository) MatchNewUserContacts(ctx context.Context, userID, telegramID int64, secret string) (int, error) {
+	tag, err := r.pool.Exec(ctx, `
+INSERT INTO user_contact_matches (user_id, contact_user_id)
+SELECT user_id, $1
+FROM user_contact_hashes
+WHERE kind = 'telegram' AND value_hash = $2 AND user_id <> $1
+ON CONFLICT DO NOTHING;`, userID, TelegramContactHash(secret, telegramID))
+	if err != nil {
+		return 0, err
+	}
+	return int(tag.RowsAffected()), nil
+}
+
+// ListFriends lists mutual contacts of the user.
+func (r *Repository) ListFriends(ctx context.Context, userID int64, limit, offset int) ([]models.Friend, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, friendsCTE+`
+SELECT count(*)
+FROM friends f
+JOIN users u ON u.id = f.friend_id
+WHERE u.is_blocked = false;`, userID).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+	rows, err := r.pool.Query(ctx, friendsCTE+`
+SELECT u.id, COALESCE(u.first_name || ' ' || u.last_name, u.first_name), u.username, u.photo_url
+FROM friends f
+JOIN users u ON u.id = f.friend_id
+WHERE u.is_blocked = false
+ORDER BY u.first_name ASC, u.id ASC
+LIMIT $2 OFFSET $3;`, userID, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.Friend, 0)
+	for rows.Next() {
+		friend, err := scanFriend(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, friend)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ListFriendsGoing returns up to perEvent friends of the user who joined each
+// event, most recent first, and the number of such friends per event. Friends
+// who hide their attendance are left out.
+func (r *Repository) ListFriendsGoing(ctx context.Context, userID int64, eventIDs []int64, perEvent int) (map[int64][]models.Friend, map[int64]int, error) {
+	friends := make(map[int64][]models.Friend)
+	counts := make(map[int64]int)
+	if userID == 0 || len(eventIDs) == 0 {
+		return friends, counts, nil
+	}
+	rows, err := r.pool.Query(ctx, friendsCTE+`, going AS (
+	SELECT p.event_id, u.id, COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS name, u.username, u.photo_url,
+		row_number() OVER (PARTITION BY p.event_id ORDER BY p.joined_at DESC, u.id) AS rn,
+		count(*) OVER (PARTITION BY p.event_id) AS total
+	FROM event_participants p
+	JOIN friends f ON f.friend_id = p.user_id
+	JOIN users u ON u.id = p.user_id
+	WHERE p.event_id = ANY($2) AND u.hide_attendance = false AND u.is_blocked = false
+)
+SELECT event_id, id, name, username, photo_url, total
+FROM going
+WHERE rn <= $3
+ORDER BY event_id, rn;`, userID, eventIDs, perEvent)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var eventID int64
+		var friend models.Friend
+		var username sql.NullString
+		var photoURL sql.NullString
+		var total int
+		if err := rows.Scan(&eventID, &friend.UserID, &friend.Name, &username, &photoURL, &total); err != nil {
+			return nil, nil, err
+		}
+		friend.Username = username.String
+		friend.PhotoURL = photoURL.String
+		friends[eventID] = append(friends[eventID], friend)
+		counts[eventID] = total
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return friends, counts, nil
+}
+
+// ListFriendsUpcomingEvents lists upcoming events the user's friends joined,
+// soonest first. Events the user can't see and attendance of friends who hide
+// it are left out.
+func (r *Repository) ListFriendsUpcomingEvents(ctx context.Context, userID int64, limit, offset int) ([]models.Event, error) {
+	rows, err := r.pool.Query(ctx, feedEventSelect+`
+WHERE e.is_hidden = false
+	AND e.status = 'published'
+	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()
+	AND (e.is_private = false OR e.creator_user_id = $1 OR ep.user_id IS NOT NULL)
+	AND EXISTS (
+		SELECT 1
+		FROM event_participants fp
+		JOIN user_contact_matches m ON m.user_id = $1 AND m.contact_user_id = fp.user_id
+		JOIN user_contact_matches b ON b.user_id = fp.user_id AND b.contact_user_id = $1
+		JOIN users fu ON fu.id = fp.user_id
+		WHERE fp.event_id = e.id AND fu.hide_attendance = false AND fu.is_blocked = false
+	)
+ORDER BY e.starts_at ASC, e.id ASC
+LIMIT $2 OFFSET $3;`, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.Event, 0)
+	for rows.Next() {
+		e, err := scanFeedEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// GetPrivacySettings returns privacy settings of the user.
+func (r *Repository) GetPrivacySettings(ctx context.Context, userID int64) (models.PrivacySettings, error) {
+	var out models.PrivacySettings
+	err := r.pool.QueryRow(ctx, `SELECT hide_attendance, phone_hash IS NOT NULL FROM users WHERE id = $1`, userID).Scan(&out.HideAttendance, &out.HasPhone)
+	return out, err
+}
+
+// SetHideAttendance sets whether the user's attendance is hidden from friends and participant lists.
+func (r *Repository) SetHideAttendance(ctx context.Context, userID int64, hide bool) error {
+	_, err := r.pool.Exec(ctx, `UPDATE users SET hide_attendance = $2, updated_at = now() WHERE id = $1`, userID, hide)
+	return err
+}
+
+// scanFriend scans a friend row.
+func scanFriend(row pgx.Row) (models.Friend, error) {
+	var friend models.Friend
+	var username sql.NullString
+	var photoURL sql.NullString
+	if err := row.Scan(&friend.UserID, &friend.Name, &username, &photoURL); err != nil {
+		return models.Friend{}, err
+	}
+	friend.Username = username.String
+	friend.PhotoURL = photoURL.String
+	return friend, nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"gigme/backend/internal/models"
+)
+
+// publicMapEventsQuery selects public, upcoming events inside a bbox ($1..$4).
+// Only the next occurrence of a series is kept, like in the feed.
+var publicMapEventsQuery = `
+SELECT e.id, e.title, e.starts_at, e.filters,
+	(e.promoted_until IS NOT NULL AND e.promoted_until > now()) AS is_promoted,
+	e.location::geometry AS geom
+FROM events e
+WHERE e.is_hidden = false
+	AND e.status = 'published'
+	AND e.is_private = false
+	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()
+	AND e.location::geometry && ST_MakeEnvelope($1, $2, $3, $4, 4326)
+	AND ` + nextSeriesOccurrenceCondition(prevOccurrenceRunning)
+
+// GetMapClusters aggregates public events inside bbox into Web Mercator grid cells of cellSize meters.
+func (r *Repository) GetMapClusters(ctx context.Context, bbox models.BoundingBox, cellSize float64, filters []string, topFilters int) ([]models.MapCluster, error) {
+	args := []interface{}{bbox.MinLng, bbox.MinLat, bbox.MaxLng, bbox.MaxLat, cellSize, topFilters}
+	visible := publicMapEventsQuery
+	if len(filters) > 0 {
+		visible += fmt.Sprintf(`
+	AND e.filters && $%d`, len(args)+1)
+		args = append(args, filters)
+	}
+	query := `
+WITH visible AS (` + visible + `
+), cells AS (
+	SELECT v.id, v.filters, v.is_promoted, v.geom,
+		floor(ST_X(ST_Transform(v.geom, 3857)) / $5)::bigint AS cx,
+		floor(ST_Y(ST_Transform(v.geom, 3857)) / $5)::bigint AS cy
+	FROM visible v
+), grouped AS (
+	SELECT cx, cy,
+		count(*) AS events_count,
+		ST_Centroid(ST_Collect(geom)) AS centroid,
+		bool_or(is_promoted) AS is_promoted,
+		min(id) AS first_id
+	FROM cells
+	GROUP BY cx, cy
+), tags AS (
+	SELECT cx, cy, tag,
+		row_number() OVER (PARTITION BY cx, cy ORDER BY count(*) DESC, tag ASC) AS rn
+	FROM cells, unnest(filters) AS tag
+	GROUP BY cx, cy, tag
+)
+SELECT ST_Y(g.centroid) AS lat,
+	ST_X(g.centroid) AS lng,
+	g.events_count,
+	g.is_promoted,
+	COALESCE(array_agg(t.tag ORDER BY t.rn) FILTER (WHERE t.tag IS NOT NULL), '{}'::text[]) AS top_filters,
+	g.first_id
+FROM grouped g
+LEFT JOIN tags t ON t.cx = g.cx AND t.cy = g.cy AND t.rn <= $6
+GROUP BY g.cx, g.cy, g.centroid, g.events_count, g.is_promoted, g.first_id
+ORDER BY g.events_count DESC, g.cx ASC, g.cy ASC;`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.MapCluster, 0)
+	for rows.Next() {
+		var c models.MapCluster
+		var firstID int64
+		if err := rows.Scan(&c.Lat, &c.Lng, &c.Count, &c.IsPromoted, &c.TopFilters, &firstID); err != nil {
+			return nil, err
+		}
+		if c.Count == 1 {
+			c.EventID = &firstID
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// GetMapMarkers returns individual public event markers inside bbox.
+func (r *Repository) GetMapMarkers(ctx context.Context, bbox models.BoundingBox, filters []string, limit int) ([]models.EventMarker, error) {
+	args := []interface{}{bbox.MinLng, bbox.MinLat, bbox.MaxLng, bbox.MaxLat, limit}
+	visible := publicMapEventsQuery
+	if len(filters) > 0 {
+		visible += fmt.Sprintf(`
+	AND e.filters && $%d`, len(args)+1)
+		args = append(args, filters)
+	}
+	query := `
+WITH visible AS (` + visible + `
+)
+SELECT id, title, starts_at, ST_Y(geom) AS lat, ST_X(geom) AS lng, is_promoted, filters
+FROM visible
+ORDER BY is_promoted DESC, starts_at ASC, id ASC
+LIMIT $5;`
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	markers := make([]models.EventMarker, 0)
+	for rows.Next() {
+		var m models.EventMarker
+		if err := rows.Scan(&m.ID, &m.Title, &m.StartsAt, &m.Lat, &m.Lng, &m.IsPromoted, &m.Filters); err != nil {
+			return nil, err
+		}
+		markers = append(markers, m)
+	}
+	return markers, rows.Err()
+}
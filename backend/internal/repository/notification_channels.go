@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gigme/backend/internal/models"
+)
+
+// ErrWebPushSubscriptionTaken is returned when a Web Push endpoint is
+// registered to another user.
+var ErrWebPushSubscriptionTaken = errors.New("web push subscription belongs to another user")
+
+// NotificationRecipient holds the identities and channel preferences of a
+// notification recipient. TelegramID is negative for VK users.
+// TelegramUnreachable is set once the user blocked the bot. Email is only
+// set once the address is confirmed.
+type NotificationRecipient struct {
+	UserID              int64
+	TelegramID          int64
+	TelegramUnreachable bool
+	Email               string
+	Channels            []string
+}
+
+// notifiableUserCondition matches users (aliased u) some channel can reach.
+// Users unreachable on Telegram only qualify with a confirmed email, an
+// active push token or an active Web Push subscription, so fan-outs don't
+// queue jobs that can only fail with no delivery channel.
+const notifiableUserCondition = `(u.telegram_unreachable_at IS NULL
+		OR u.email IS NOT NULL
+		OR EXISTS (SELECT 1 FROM user_push_tokens pt WHERE pt.user_id = u.id AND pt.is_active = true)
+		OR EXISTS (SELECT 1 FROM user_web_push_subscriptions ws WHERE ws.user_id = u.id AND ws.is_active = true))`
+
+// GetNotificationRecipient returns the identities and channel preferences of the user.
+func (r *Repository) GetNotificationRecipient(ctx context.Context, userID int64) (NotificationRecipient, error) {
+	out := NotificationRecipient{UserID: userID}
+	var email sql.NullString
+	if err := r.pool.QueryRow(ctx, `
+SELECT telegram_id, telegram_unreachable_at IS NOT NULL, email, notify_channels
+FROM users
+WHERE id = $1;`, userID).Scan(&out.TelegramID, &out.TelegramUnreachable, &email, &out.Channels); err != nil {
+		return NotificationRecipient{}, err
+	}
+	out.Email = email.String
+	return out, nil
+}
+
+// RecordNotificationJobResult updates the status of a notification job
+// together with per-channel results of the last delivery attempt.
+func (r *Repository) RecordNotificationJobResult(ctx context.Context, jobID int64, status string, attempts int, lastError string, nextRun *time.Time, deliveries []models.NotificationDelivery) error {
+	if deliveries == nil {
+		deliveries = []models.NotificationDelivery{}
+	}
+	deliveriesBytes, err := json.Marshal(deliveries)
+	if err != nil {
+		return err
+	}
+	_, err = r.pool.Exec(ctx, `
+UPDATE notification_jobs
+SET status = $2,
+	attempts = $3,
+	last_error = $4,
+	run_at = COALESCE($5, run_at),
+	deliveries = $6,
+	updated_at = now()
+WHERE id = $1;`, jobID, status, attempts, nullString(lastError), nextRun, deliveriesBytes)
+	return err
+}
+
+// ListAdminNotificationJobs lists notification jobs for admins, newest first,
+// optionally filtered by user, status and kind.
+func (r *Repository) ListAdminNotificationJobs(ctx context.Context, userID *int64, status, kind *string, limit, offset int) ([]models.AdminNotificationJob, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+SELECT count(*)
+FROM notification_jobs
+WHERE ($1::bigint IS NULL OR user_id = $1)
+	AND ($2::text IS NULL OR status = $2)
+	AND ($3::text IS NULL OR kind = $3);`, userID, status, kind).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, `
+SELECT id, user_id, event_id, kind, run_at, payload, status, attempts, COALESCE(last_error, ''),
+	deliveries, created_at, updated_at
+FROM notification_jobs
+WHERE ($1::bigint IS NULL OR user_id = $1)
+	AND ($2::text IS NULL OR status = $2)
+	AND ($3::text IS NULL OR kind = $3)
+ORDER BY created_at DESC, id DESC
+LIMIT $4 OFFSET $5;`, userID, status, kind, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.AdminNotificationJob, 0)
+	for rows.Next() {
+		var item models.AdminNotificationJob
+		var payloadBytes []byte
+		var deliveriesBytes []byte
+		if err := rows.Scan(
+			&item.ID,
+			&item.UserID,
+			&item.EventID,
+			&item.Kind,
+			&item.RunAt,
+			&payloadBytes,
+			&item.Status,
+			&item.Attempts,
+			&item.LastError,
+			&deliveriesBytes,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		); err != nil {
+			return nil, 0, err
+		}
+		if len(payloadBytes) > 0 {
+			_ = json.Unmarshal(payloadBytes, &item.Payload)
+		}
+		if len(deliveriesBytes) > 0 {
+			_ = json.Unmarshal(deliveriesBytes, &item.Deliveries)
+		}
+		if item.Deliveries == nil {
+			item.Deliveries = []models.NotificationDelivery{}
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// UpsertWebPushSubscription stores a Web Push subscription of the user and
+// reactivates it. It returns ErrWebPushSubscriptionTaken when the endpoint
+// belongs to another user.
+func (r *Repository) UpsertWebPushSubscription(ctx context.Context, sub models.WebPushSubscription) error {
+	command, err := r.pool.Exec(ctx, `
+INSERT INTO user_web_push_subscriptions (user_id, endpoint, p256dh, auth, is_active, created_at, updated_at)
+VALUES ($1, $2, $3, $4, true, now(), now())
+ON CONFLICT (endpoint) DO UPDATE SET
+	p256dh = EXCLUDED.p256dh,
+	auth = EXCLUDED.auth,
+	is_active = true,
+	updated_at = now()
+WHERE user_web_push_subscriptions.user_id = EXCLUDED.user_id;`, sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return ErrWebPushSubscriptionTaken
+	}
+	return nil
+}
+
+// DeleteWebPushSubscription removes a Web Push subscription of the user and reports whether it existed.
+func (r *Repository) DeleteWebPushSubscription(ctx context.Context, userID int64, endpoint string) (bool, error) {
+	command, err := r.pool.Exec(ctx, `DELETE FROM user_web_push_subscriptions WHERE user_id = $1 AND endpoint = $2`, userID, endpoint)
+	if err != nil {
+		return false, err
+	}
+	return command.RowsAffected() > 0, nil
+}
+
+// ListActiveWebPushSubscriptions lists active Web Push subscriptions of the user.
+func (r *Repository) ListActiveWebPushSubscriptions(ctx context.Context, userID int64) ([]models.WebPushSubscription, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT user_id, endpoint, p256dh, auth
+FROM user_web_push_subscriptions
+WHERE user_id = $1 AND is_active = true
+ORDER BY updated_at DESC;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.WebPushSubscription, 0)
+	for rows.Next() {
+		var sub models.WebPushSubscription
+		if err := rows.Scan(&sub.UserID, &sub.Endpoint, &sub.P256dh, &sub.Auth); err != nil {
+			return nil, err
+		}
+		out = append(out, sub)
+	}
+	return out, rows.Err()
+}
+
+// DeactivateWebPushSubscription marks a subscription the push service reported as gone as inactive.
+func (r *Repository) DeactivateWebPushSubscription(ctx context.Context, endpoint string) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE user_web_push_subscriptions
+SET is_active = false,
+	updated_at = now()
+WHERE endpoint = $1 AND is_active = true;`, endpoint)
+	return err
+}
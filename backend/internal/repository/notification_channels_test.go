@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TestUpsertWebPushSubscriptionKeepsOwner verifies another user can't take over a subscription.
+func TestUpsertWebPushSubscriptionKeepsOwner(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	ownerID, err := insertTicketingTestUser(ctx, pool, 778401)
+	if err != nil {
+		t.Fatalf("insert owner: %v", err)
+	}
+	otherID, err := insertTicketingTestUser(ctx, pool, 778402)
+	if err != nil {
+		t.Fatalf("insert other user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, []int64{ownerID, otherID})
+	})
+
+	sub := models.WebPushSubscription{UserID: ownerID, Endpoint: "https://fcm.googleapis.com/fcm/send/owner-test", P256dh: "key", Auth: "auth"}
+	if err := repo.UpsertWebPushSubscription(ctx, sub); err != nil {
+		t.Fatalf("upsert: %v", err)
+	}
+	if err := repo.UpsertWebPushSubscription(ctx, sub); err != nil {
+		t.Fatalf("upsert by the owner: %v", err)
+	}
+	sub.UserID = otherID
+	if err := repo.UpsertWebPushSubscription(ctx, sub); !errors.Is(err, ErrWebPushSubscriptionTaken) {
+		t.Fatalf("expected ErrWebPushSubscriptionTaken, got %v", err)
+	}
+	subs, err := repo.ListActiveWebPushSubscriptions(ctx, ownerID)
+	if err != nil || len(subs) != 1 {
+		t.Fatalf("expected the owner to keep the subscription, got %v %v", subs, err)
+	}
+}
+
+// TestConfirmEmail verifies a new email is only used once it is confirmed.
+func TestConfirmEmail(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778403)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	now := time.Now()
+	email := "confirm-test@example.com"
+	update := NotificationSettingsUpdate{Email: &email, EmailTokenHash: "confirm-test-hash", EmailTokenTTL: time.Hour}
+	if err := repo.UpdateNotificationSettings(ctx, userID, update, now); err != nil {
+		t.Fatalf("update settings: %v", err)
+	}
+	recipient, err := repo.GetNotificationRecipient(ctx, userID)
+	if err != nil || recipient.Email != "" {
+		t.Fatalf("expected no email before confirmation, got %q %v", recipient.Email, err)
+	}
+	if _, err := repo.ConfirmEmail(ctx, "confirm-test-hash", now.Add(2*time.Hour)); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected expired token to be rejected, got %v", err)
+	}
+	confirmed, err := repo.ConfirmEmail(ctx, "confirm-test-hash", now)
+	if err != nil || confirmed != email {
+		t.Fatalf("confirm: %q %v", confirmed, err)
+	}
+	settings, err := repo.GetNotificationSettings(ctx, userID)
+	if err != nil || settings.Email != email || settings.PendingEmail != "" {
+		t.Fatalf("unexpected settings after confirmation: %+v %v", settings, err)
+	}
+	if _, err := repo.ConfirmEmail(ctx, "confirm-test-hash", now); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected used token to be rejected, got %v", err)
+	}
+}
+
+// TestEventAudienceSkipsUnreachableUsers verifies users only reachable on a blocked Telegram get no job.
+func TestEventAudienceSkipsUnreachableUsers(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	ownerID, err := insertTicketingTestUser(ctx, pool, 778404)
+	if err != nil {
+		t.Fatalf("insert owner: %v", err)
+	}
+	blockedID, err := insertTicketingTestUser(ctx, pool, 778405)
+	if err != nil {
+		t.Fatalf("insert blocked user: %v", err)
+	}
+	pushID, err := insertTicketingTestUser(ctx, pool, 778406)
+	if err != nil {
+		t.Fatalf("insert push user: %v", err)
+	}
+	eventID, err := insertTicketingTestEvent(ctx, pool, ownerID)
+	if err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = ANY($1)`, []int64{ownerID, blockedID, pushID})
+	})
+
+	for _, userID := range []int64{blockedID, pushID} {
+		if _, err := pool.Exec(ctx, `INSERT INTO event_participants (event_id, user_id) VALUES ($1, $2)`, eventID, userID); err != nil {
+			t.Fatalf("insert participant: %v", err)
+		}
+		if err := repo.MarkTelegramUnreachable(ctx, userID, "blocked"); err != nil {
+			t.Fatalf("mark unreachable: %v", err)
+		}
+	}
+	if _, err := pool.Exec(ctx, `INSERT INTO user_push_tokens (user_id, platform, token) VALUES ($1, 'android', 'audience-test-token')`, pushID); err != nil {
+		t.Fatalf("insert push token: %v", err)
+	}
+
+	created, err := repo.CreateEventAudienceNotificationJobs(ctx, eventID, "event_updated", time.Now(), map[string]interface{}{}, ownerID)
+	if err != nil {
+		t.Fatalf("CreateEventAudienceNotificationJobs(): %v", err)
+	}
+	var userIDs []int64
+	if err := pool.QueryRow(ctx, `SELECT array_agg(user_id) FROM notification_jobs WHERE event_id = $1`, eventID).Scan(&userIDs); err != nil {
+		t.Fatalf("list jobs: %v", err)
+	}
+	if created != 1 || len(userIDs) != 1 || userIDs[0] != pushID {
+		t.Fatalf("expected a job only for the push user, got %d %v", created, userIDs)
+	}
+}
@@ -0,0 +1,256 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrInvalidTimezone is returned when a timezone is not known to Postgres.
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// DefaultReminderOffsets are reminder offsets in minutes of users who never changed them.
+var DefaultReminderOffsets = []int{60}
+
+// NotificationSettingsUpdate holds changed notification settings; nil fields
+// are left as is. QuietHoursStart, QuietHoursEnd and DigestTime are minutes
+// since midnight. Quiet hours are applied when SetQuietHours is true, nil
+// disabling them; a zero DigestWeekday turns the weekly digest off. Channels
+// is the preferred delivery order, empty for the default. An empty Email
+// removes the address; a new one is kept pending with EmailTokenHash until
+// it is confirmed.
+type NotificationSettingsUpdate struct {
+	AllNewEvents    *bool
+	Muted           []string
+	ReminderOffsets []int
+	SetQuietHours   bool
+	QuietHoursStart *int
+	QuietHoursEnd   *int
+	Timezone        *string
+	DigestWeekday   *int
+	DigestTime      *int
+	Channels        []string
+	Email           *string
+	EmailTokenHash  string
+	EmailTokenTTL   time.Duration
+}
+
+// reminderJobsInsert schedules a reminder for every reminder offset of the
+// selected participants whose time is still after $1.
+const reminderJobsInsert = `
+INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
+SELECT p.user_id, e.id, 'reminder', e.starts_at - make_interval(mins => o.minutes),
+	jsonb_build_object('eventId', e.id, 'title', e.title, 'startsAt', e.starts_at, 'offsetMinutes', o.minutes,
+		'timezone', COALESCE(e.timezone, s.timezone)),
+	'pending'
+FROM event_participants p
+JOIN events e ON e.id = p.event_id
+LEFT JOIN event_series s ON s.id = e.series_id
+JOIN users u ON u.id = p.user_id
+CROSS JOIN LATERAL unnest(u.reminder_offsets) AS o(minutes)
+WHERE e.status <> 'canceled'
+	AND e.starts_at - make_interval(mins => o.minutes) > $1`
+
+// ParseClockMinutes parses "HH:MM" into minutes since midnight.
+func ParseClockMinutes(value string) (int, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, false
+	}
+	return parsed.Hour()*60 + parsed.Minute(), true
+}
+
+// FormatClockMinutes formats minutes since midnight as "HH:MM".
+func FormatClockMinutes(minutes int) string {
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+// GetNotificationSettings returns notification settings of the user.
+func (r *Repository) GetNotificationSettings(ctx context.Context, userID int64) (models.NotificationSettings, error) {
+	var out models.NotificationSettings
+	var offsets []int32
+	var quietStart sql.NullInt16
+	var quietEnd sql.NullInt16
+	var timezone sql.NullString
+	var digestWeekday sql.NullInt16
+	var digestTime int16
+	var email sql.NullString
+	var pendingEmail sql.NullString
+	err := r.pool.QueryRow(ctx, `
+SELECT notify_all_events, notify_muted, reminder_offsets, quiet_hours_start, quiet_hours_end, timezone,
+	digest_weekday, digest_time, notify_channels, email, pending_email
+FROM users
+WHERE id = $1;`, userID).Scan(
+		&out.AllNewEvents,
+		&out.Muted,
+		&offsets,
+		&quietStart,
+		&quietEnd,
+		&timezone,
+		&digestWeekday,
+		&digestTime,
+		&out.Channels,
+		&email,
+		&pendingEmail,
+	)
+	if err != nil {
+		return models.NotificationSettings{}, err
+	}
+	if out.Muted == nil {
+		out.Muted = []string{}
+	}
+	if out.Channels == nil {
+		out.Channels = []string{}
+	}
+	out.Email = email.String
+	out.PendingEmail = pendingEmail.String
+	out.ReminderOffsets = make([]int, 0, len(offsets))
+	for _, offset := range offsets {
+		out.ReminderOffsets = append(out.ReminderOffsets, int(offset))
+	}
+	if quietStart.Valid && quietEnd.Valid {
+		out.QuietHoursStart = FormatClockMinutes(int(quietStart.Int16))
+		out.QuietHoursEnd = FormatClockMinutes(int(quietEnd.Int16))
+	}
+	out.Timezone = timezone.String
+	out.DigestWeekday = int(digestWeekday.Int16)
+	out.DigestTime = FormatClockMinutes(int(digestTime))
+	return out, nil
+}
+
+// UpdateNotificationSettings applies changed notification settings. Changing
+// reminder offsets reschedules pending reminders of the user's upcoming events.
+// A timezone unknown to Postgres fails with ErrInvalidTimezone.
+func (r *Repository) UpdateNotificationSettings(ctx context.Context, userID int64, update NotificationSettingsUpdate, now time.Time) error {
+	return r.WithTx(ctx, func(tx pgx.Tx) error {
+		if update.Timezone != nil && *update.Timezone != "" {
+			// Digests are scheduled in SQL, so the name must be one Postgres knows.
+			var known bool
+			if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM pg_timezone_names WHERE name = $1)`, *update.Timezone).Scan(&known); err != nil {
+				return err
+			}
+			if !known {
+				return ErrInvalidTimezone
+			}
+		}
+		command, err := tx.Exec(ctx, `
+UPDATE users
+SET notify_all_events = COALESCE($2, notify_all_events),
+	notify_muted = COALESCE($3, notify_muted),
+	reminder_offsets = COALESCE($4, reminder_offsets),
+	quiet_hours_start = CASE WHEN $5 THEN $6 ELSE quiet_hours_start END,
+	quiet_hours_end = CASE WHEN $5 THEN $7 ELSE quiet_hours_end END,
+	timezone = COALESCE($8, timezone),
+	digest_weekday = CASE WHEN $9::int IS NULL THEN digest_weekday ELSE NULLIF($9::int, 0) END,
+	digest_time = COALESCE($10, digest_time),
+	notify_channels = COALESCE($11, notify_channels),
+	email = CASE WHEN $12::text = '' THEN NULL ELSE email END,
+	pending_email = CASE
+		WHEN $12::text IS NULL THEN pending_email
+		WHEN $12::text = '' OR $12::text = email THEN NULL
+		ELSE $12::text
+	END,
+	email_token_hash = CASE
+		WHEN $12::text IS NULL THEN email_token_hash
+		WHEN $12::text = '' OR $12::text = email THEN NULL
+		ELSE $13::text
+	END,
+	email_token_expires_at = CASE
+		WHEN $12::text IS NULL THEN email_token_expires_at
+		WHEN $12::text = '' OR $12::text = email THEN NULL
+		ELSE $14::timestamptz
+	END,
+	updated_at = now()
+WHERE id = $1;`,
+			userID,
+			update.AllNewEvents,
+			update.Muted,
+			update.ReminderOffsets,
+			update.SetQuietHours,
+			update.QuietHoursStart,
+			update.QuietHoursEnd,
+			update.Timezone,
+			update.DigestWeekday,
+			update.DigestTime,
+			update.Channels,
+			update.Email,
+			nullString(update.EmailTokenHash),
+			now.Add(update.EmailTokenTTL),
+		)
+		if err != nil {
+			return err
+		}
+		if command.RowsAffected() == 0 {
+			return pgx.ErrNoRows
+		}
+		if update.ReminderOffsets == nil {
+			return nil
+		}
+		if _, err := tx.Exec(ctx, `
+DELETE FROM notification_jobs
+WHERE user_id = $1 AND kind IN ('reminder', 'reminder_60m') AND status = 'pending';`, userID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, reminderJobsInsert+`
+	AND p.user_id = $2;`, now, userID)
+		return err
+	})
+}
+
+// ConfirmEmail makes the pending email with the given token hash the user's
+// address. It returns the confirmed address, or pgx.ErrNoRows when the token
+// is unknown or expired.
+func (r *Repository) ConfirmEmail(ctx context.Context, tokenHash string, now time.Time) (string, error) {
+	var email string
+	err := r.pool.QueryRow(ctx, `
+UPDATE users
+SET email = pending_email,
+	pending_email = NULL,
+	email_token_hash = NULL,
+	email_token_expires_at = NULL,
+	updated_at = now()
+WHERE email_token_hash = $1
+	AND email_token_expires_at > $2
+	AND pending_email IS NOT NULL
+RETURNING email;`, tokenHash, now).Scan(&email)
+	return email, err
+}
+
+// MuteNotificationCategory mutes a notification category for the user with the
+// given Telegram id. It returns false when no such user exists.
+func (r *Repository) MuteNotificationCategory(ctx context.Context, telegramID int64, category string) (bool, error) {
+	command, err := r.pool.Exec(ctx, `
+UPDATE users
+SET notify_muted = CASE WHEN $2 = ANY(notify_muted) THEN notify_muted ELSE array_append(notify_muted, $2) END,
+	updated_at = now()
+WHERE telegram_id = $1;`, telegramID, category)
+	if err != nil {
+		return false, err
+	}
+	return command.RowsAffected() > 0, nil
+}
+
+// ScheduleEventReminders schedules reminders of an event for a participant
+// according to their reminder offsets and returns how many were scheduled.
+func (r *Repository) ScheduleEventReminders(ctx context.Context, eventID, userID int64, now time.Time) (int64, error) {
+	command, err := r.pool.Exec(ctx, reminderJobsInsert+`
+	AND p.event_id = $2
+	AND p.user_id = $3
+	AND NOT EXISTS (
+		SELECT 1 FROM notification_jobs nj
+		WHERE nj.user_id = p.user_id AND nj.event_id = e.id
+			AND nj.kind = 'reminder' AND nj.status = 'pending'
+			AND (nj.payload->>'offsetMinutes')::int = o.minutes
+	);`, now, eventID, userID)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
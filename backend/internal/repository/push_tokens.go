@@ -46,3 +46,48 @@ WHERE user_id = $1
 
 	return nil
 }
+
+// ListActivePushTokens lists active push tokens of the user, most recently seen first.
+func (r *Repository) ListActivePushTokens(ctx context.Context, userID int64) ([]models.UserPushToken, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT user_id, platform, token, COALESCE(device_id, ''), COALESCE(app_version, ''), COALESCE(locale, ''),
+	is_active, last_seen_at, created_at, updated_at
+FROM user_push_tokens
+WHERE user_id = $1 AND is_active = true
+ORDER BY last_seen_at DESC;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.UserPushToken, 0)
+	for rows.Next() {
+		var token models.UserPushToken
+		if err := rows.Scan(
+			&token.UserID,
+			&token.Platform,
+			&token.Token,
+			&token.DeviceID,
+			&token.AppVersion,
+			&token.Locale,
+			&token.IsActive,
+			&token.LastSeenAt,
+			&token.CreatedAt,
+			&token.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, token)
+	}
+	return out, rows.Err()
+}
+
+// DeactivatePushToken marks a push token the push service no longer accepts as inactive.
+func (r *Repository) DeactivatePushToken(ctx context.Context, token string) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE user_push_tokens
+SET is_active = false,
+	updated_at = now()
+WHERE token = $1 AND is_active = true;`, token)
+	return err
+}
@@ -75,7 +75,7 @@ func (r *Repository) ClaimReferral(ctx context.Context, inviteeID, eventID int64
 	}()
 
 	var eventExists bool
-	if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1 AND is_hidden = false)`, eventID).Scan(&eventExists); err != nil {
+	if err := tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM events WHERE id = $1 AND is_hidden = false AND status = 'published')`, eventID).Scan(&eventExists); err != nil {
 		return false, 0, 0, err
 	}
 	if !eventExists {
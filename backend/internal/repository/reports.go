@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrSelfReport = errors.New("cannot report own content")
+
+// reportedTargetSelect selects report targets with counters and a preview of the content.
+const reportedTargetSelect = `
+SELECT t.target_type, t.target_id, COALESCE(e.id, c.event_id),
+	COALESCE(e.title, c.body, COALESCE(u.first_name || ' ' || u.last_name, u.first_name), ''),
+	t.status, COALESCE(e.is_hidden, c.is_hidden, u.is_blocked, false), t.auto_hidden_at IS NOT NULL,
+	s.reports_count, s.open_count, COALESCE(s.reasons, '{}'::jsonb), s.last_reported_at,
+	t.resolved_at, t.resolution_note
+FROM report_targets t
+LEFT JOIN events e ON t.target_type = 'event' AND e.id = t.target_id
+LEFT JOIN event_comments c ON t.target_type = 'comment' AND c.id = t.target_id
+LEFT JOIN users u ON t.target_type = 'user' AND u.id = t.target_id
+JOIN LATERAL (
+	SELECT count(*) AS reports_count,
+		count(*) FILTER (WHERE rp.status = 'open') AS open_count,
+		max(rp.created_at) AS last_reported_at,
+		(SELECT jsonb_object_agg(x.reason, x.n)
+			FROM (
+				SELECT reason, count(*) AS n
+				FROM reports
+				WHERE target_type = t.target_type AND target_id = t.target_id
+				GROUP BY reason
+			) x) AS reasons
+	FROM reports rp
+	WHERE rp.target_type = t.target_type AND rp.target_id = t.target_id
+) s ON true`
+
+// CreateReport records the reporter's report about a target and reopens it in the
+// moderation queue. Events and comments reported by autoHideThreshold distinct
+// users are hidden until a moderator resolves them; a zero threshold disables it.
+// It returns whether the target was hidden by this report.
+func (r *Repository) CreateReport(ctx context.Context, targetType string, targetID, reporterID int64, reason, details string, autoHideThreshold int) (bool, error) {
+	details = strings.TrimSpace(details)
+	autoHidden := false
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		ownerID, err := reportTargetOwner(ctx, tx, targetType, targetID)
+		if err != nil {
+			return err
+		}
+		if ownerID == reporterID {
+			return ErrSelfReport
+		}
+
+		if _, err := tx.Exec(ctx, `
+INSERT INTO report_targets (target_type, target_id)
+VALUES ($1, $2)
+ON CONFLICT (target_type, target_id) DO UPDATE
+SET status = 'open',
+	resolved_by = CASE WHEN report_targets.status = 'open' THEN report_targets.resolved_by END,
+	resolved_at = CASE WHEN report_targets.status = 'open' THEN report_targets.resolved_at END,
+	resolution_note = CASE WHEN report_targets.status = 'open' THEN report_targets.resolution_note END,
+	updated_at = now();`, targetType, targetID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+INSERT INTO reports (target_type, target_id, reporter_user_id, reason, details)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (target_type, target_id, reporter_user_id) DO UPDATE
+SET reason = EXCLUDED.reason,
+	details = EXCLUDED.details,
+	status = 'open',
+	created_at = now(),
+	resolved_at = NULL;`, targetType, targetID, reporterID, reason, nullString(details)); err != nil {
+			return err
+		}
+
+		if autoHideThreshold <= 0 || targetType == models.ReportTargetUser {
+			return nil
+		}
+		var reporters int
+		var alreadyHidden bool
+		if err := tx.QueryRow(ctx, `
+SELECT count(*), bool_or(t.auto_hidden_at IS NOT NULL)
+FROM reports rp
+JOIN report_targets t ON t.target_type = rp.target_type AND t.target_id = rp.target_id
+WHERE rp.target_type = $1 AND rp.target_id = $2 AND rp.status = 'open';`, targetType, targetID).Scan(&reporters, &alreadyHidden); err != nil {
+			return err
+		}
+		if alreadyHidden || reporters < autoHideThreshold {
+			return nil
+		}
+		hidden, err := setReportTargetHidden(ctx, tx, targetType, targetID, true)
+		if err != nil || !hidden {
+			return err
+		}
+		// Only content hidden here is remembered, so dismissing the reports never
+		// restores content an admin hid on purpose.
+		if _, err := tx.Exec(ctx, `
+UPDATE report_targets
+SET auto_hidden_at = now(),
+	updated_at = now()
+WHERE target_type = $1 AND target_id = $2;`, targetType, targetID); err != nil {
+			return err
+		}
+		autoHidden = true
+		return nil
+	})
+	return autoHidden, err
+}
+
+// ListReportedTargets lists reported targets for moderation, optionally filtered by status and type.
+func (r *Repository) ListReportedTargets(ctx context.Context, status, targetType *string, limit, offset int) ([]models.ReportedTarget, int, error) {
+	var total int
+	if err := r.pool.QueryRow(ctx, `
+SELECT count(*)
+FROM report_targets t
+WHERE ($1::text IS NULL OR t.status = $1)
+	AND ($2::text IS NULL OR t.target_type = $2);`, status, targetType).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := r.pool.Query(ctx, reportedTargetSelect+`
+WHERE ($1::text IS NULL OR t.status = $1)
+	AND ($2::text IS NULL OR t.target_type = $2)
+ORDER BY s.open_count DESC, s.last_reported_at DESC
+LIMIT $3 OFFSET $4;`, status, targetType, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	items := make([]models.ReportedTarget, 0)
+	for rows.Next() {
+		item, err := scanReportedTarget(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// ResolveReportTarget closes open reports about a target. Actioned events and
+// comments are hidden and actioned users are blocked; dismissing restores
+// content hidden by the auto-hide threshold. Every reporter of an open report
+// gets a report_resolved notification.
+func (r *Repository) ResolveReportTarget(ctx context.Context, targetType string, targetID int64, status string, moderatorID int64, note string) (models.ReportedTarget, error) {
+	note = strings.TrimSpace(note)
+	var out models.ReportedTarget
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var autoHidden bool
+		if err := tx.QueryRow(ctx, `
+SELECT auto_hidden_at IS NOT NULL
+FROM report_targets
+WHERE target_type = $1 AND target_id = $2
+FOR UPDATE;`, targetType, targetID).Scan(&autoHidden); err != nil {
+			return err
+		}
+
+		switch {
+		case status == models.ReportStatusActioned && targetType == models.ReportTargetUser:
+			reason := note
+			if reason == "" {
+				reason = "reports"
+			}
+			tag, err := tx.Exec(ctx, `
+UPDATE users
+SET is_blocked = true, blocked_reason = $2, blocked_at = now(), updated_at = now()
+WHERE id = $1 AND is_blocked = false;`, targetID, reason)
+			if err != nil {
+				return err
+			}
+			if tag.RowsAffected() > 0 {
+				if _, err := tx.Exec(ctx, `
+INSERT INTO user_bans (user_id, scope, reason, created_by)
+VALUES ($1, 'full', $2, $3);`, targetID, reason, moderatorID); err != nil {
+					return err
+				}
+			}
+		case status == models.ReportStatusActioned:
+			if _, err := setReportTargetHidden(ctx, tx, targetType, targetID, true); err != nil {
+				return err
+			}
+		case autoHidden:
+			if _, err := setReportTargetHidden(ctx, tx, targetType, targetID, false); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(ctx, `
+UPDATE report_targets
+SET status = $3,
+	auto_hidden_at = NULL,
+	resolved_by = $4,
+	resolved_at = now(),
+	resolution_note = $5,
+	updated_at = now()
+WHERE target_type = $1 AND target_id = $2;`, targetType, targetID, status, moderatorID, nullString(note)); err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(map[string]interface{}{
+			"targetType": targetType,
+			"targetId":   targetID,
+			"status":     status,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+WITH resolved AS (
+	UPDATE reports
+	SET status = $3,
+		resolved_at = now()
+	WHERE target_type = $1 AND target_id = $2 AND status = 'open'
+	RETURNING reporter_user_id
+)
+INSERT INTO notification_jobs (user_id, kind, run_at, payload, status)
+SELECT reporter_user_id, 'report_resolved', now(), $4, 'pending'
+FROM resolved;`, targetType, targetID, status, payload); err != nil {
+			return err
+		}
+
+		out, err = scanReportedTarget(tx.QueryRow(ctx, reportedTargetSelect+`
+WHERE t.target_type = $1 AND t.target_id = $2;`, targetType, targetID))
+		return err
+	})
+	if err != nil {
+		return models.ReportedTarget{}, err
+	}
+	return out, nil
+}
+
+// reportTargetOwner returns the author of a reported event or comment, or the reported user itself.
+func reportTargetOwner(ctx context.Context, tx pgx.Tx, targetType string, targetID int64) (int64, error) {
+	var query string
+	switch targetType {
+	case models.ReportTargetEvent:
+		query = `SELECT creator_user_id FROM events WHERE id = $1`
+	case models.ReportTargetComment:
+		query = `SELECT user_id FROM event_comments WHERE id = $1`
+	case models.ReportTargetUser:
+		query = `SELECT id FROM users WHERE id = $1`
+	default:
+		return 0, pgx.ErrNoRows
+	}
+	var ownerID int64
+	if err := tx.QueryRow(ctx, query, targetID).Scan(&ownerID); err != nil {
+		return 0, err
+	}
+	return ownerID, nil
+}
+
+// setReportTargetHidden hides or restores a reported event or comment and
+// reports whether its visibility changed.
+func setReportTargetHidden(ctx context.Context, tx pgx.Tx, targetType string, targetID int64, hidden bool) (bool, error) {
+	var query string
+	switch targetType {
+	case models.ReportTargetEvent:
+		query = `UPDATE events SET is_hidden = $2, revision = revision + 1, updated_at = now() WHERE id = $1 AND is_hidden <> $2`
+	case models.ReportTargetComment:
+		query = `UPDATE event_comments SET is_hidden = $2 WHERE id = $1 AND is_hidden <> $2`
+	default:
+		return false, nil
+	}
+	command, err := tx.Exec(ctx, query, targetID, hidden)
+	if err != nil {
+		return false, err
+	}
+	return command.RowsAffected() > 0, nil
+}
+
+// scanReportedTarget scans a row selected with reportedTargetSelect.
+func scanReportedTarget(row pgx.Row) (models.ReportedTarget, error) {
+	var item models.ReportedTarget
+	var eventID sql.NullInt64
+	var reasons []byte
+	var resolvedAt sql.NullTime
+	var note sql.NullString
+	if err := row.Scan(
+		&item.TargetType,
+		&item.TargetID,
+		&eventID,
+		&item.Preview,
+		&item.Status,
+		&item.IsHidden,
+		&item.AutoHidden,
+		&item.ReportsCount,
+		&item.OpenCount,
+		&reasons,
+		&item.LastReportedAt,
+		&resolvedAt,
+		&note,
+	); err != nil {
+		return models.ReportedTarget{}, err
+	}
+	if eventID.Valid {
+		item.EventID = &eventID.Int64
+	}
+	item.Reasons = map[string]int{}
+	if err := json.Unmarshal(reasons, &item.Reasons); err != nil {
+		return models.ReportedTarget{}, err
+	}
+	if resolvedAt.Valid {
+		item.ResolvedAt = &resolvedAt.Time
+	}
+	item.ResolutionNote = note.String
+	return item, nil
+}
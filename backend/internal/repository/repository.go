@@ -102,7 +102,9 @@ RETURNING balance_tokens;`, userID, amount)
 	return balance, nil
 }
 
-// GetNearbyUserIDs returns nearby user i ds.
+// GetNearbyUserIDs returns users last seen within radiusMeters of the point,
+// most recently active first. A nil seenAfter skips the activity check and a
+// zero limit returns everyone.
 func (r *Repository) GetNearbyUserIDs(ctx context.Context, lat, lng float64, radiusMeters int, excludeUserID int64, seenAfter *time.Time, limit int) ([]int64, error) {
 	query := `
 SELECT id
@@ -140,7 +142,7 @@ LIMIT $6;`
 
 // CountUserEventsLastHour handles count user events last hour.
 func (r *Repository) CountUserEventsLastHour(ctx context.Context, userID int64) (int, error) {
-	row := r.pool.QueryRow(ctx, `SELECT count(*) FROM events WHERE creator_user_id = $1 AND created_at >= now() - interval '1 hour'`, userID)
+	row := r.pool.QueryRow(ctx, `SELECT count(*) FROM events WHERE creator_user_id = $1 AND created_at >= now() - interval '1 hour' AND COALESCE(series_index, 0) = 0`, userID)
 	var count int
 	if err := row.Scan(&count); err != nil {
 		return 0, err
@@ -230,8 +232,10 @@ SELECT e.id, e.title, e.starts_at,
 FROM events e
 LEFT JOIN event_participants ep ON ep.event_id = e.id AND ep.user_id = $1
 WHERE e.is_hidden = false
+	AND e.status = 'published'
 	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= COALESCE($2, now())
-	AND (e.starts_at <= $3 OR $3 IS NULL)`
+	AND (e.starts_at <= $3 OR $3 IS NULL)
+	AND ` + nextSeriesOccurrenceCondition("COALESCE(prev.ends_at, prev.starts_at + interval '2 hours') >= COALESCE($2, now())")
 	args := []interface{}{userID, from, to}
 	privacy := "e.is_private = false OR e.creator_user_id = $1 OR ep.user_id IS NOT NULL"
 	if len(accessKeys) > 0 {
@@ -280,7 +284,17 @@ LIMIT 500;`
 
 // GetFeed returns feed.
 func (r *Repository) GetFeed(ctx context.Context, userID int64, limit, offset int, lat, lng *float64, radiusMeters int, filters []string, accessKeys []string) ([]models.Event, error) {
-	query := `
+	return r.queryFeed(ctx, userID, limit, offset, lat, lng, radiusMeters, filters, accessKeys, nil)
+}
+
+// GetRankedFeed returns feed ordered by a personalized score.
+// Currently promoted events still come first.
+func (r *Repository) GetRankedFeed(ctx context.Context, userID int64, limit, offset int, lat, lng *float64, radiusMeters int, filters []string, accessKeys []string, ranking FeedRanking) ([]models.Event, error) {
+	return r.queryFeed(ctx, userID, limit, offset, lat, lng, radiusMeters, filters, accessKeys, &ranking)
+}
+
+// feedEventSelect selects feed rows for scanFeedEvent; $1 is the viewer id.
+const feedEventSelect = `
 SELECT e.id, e.title, e.description, e.starts_at, e.ends_at,
 	ST_Y(e.location::geometry) AS lat,
 	ST_X(e.location::geometry) AS lng,
@@ -290,14 +304,27 @@ SELECT e.id, e.title, e.description, e.starts_at, e.ends_at,
 	(SELECT url FROM event_media WHERE event_id = e.id ORDER BY id ASC LIMIT 1) AS thumbnail_url,
 	(SELECT count(*) FROM event_participants WHERE event_id = e.id) AS participants_count,
 	(SELECT count(*) FROM event_likes WHERE event_id = e.id) AS likes_count,
-	(SELECT count(*) FROM event_comments WHERE event_id = e.id) AS comments_count,
+	(SELECT count(*) FROM event_comments WHERE event_id = e.id AND is_hidden = false) AS comments_count,
 	(ep.user_id IS NOT NULL) AS is_joined,
-	(SELECT EXISTS(SELECT 1 FROM event_likes WHERE event_id = e.id AND user_id = $1)) AS is_liked
+	(SELECT EXISTS(SELECT 1 FROM event_likes WHERE event_id = e.id AND user_id = $1)) AS is_liked,
+	e.series_id
 FROM events e
 JOIN users u ON u.id = e.creator_user_id
-LEFT JOIN event_participants ep ON ep.event_id = e.id AND ep.user_id = $1
+LEFT JOIN event_participants ep ON ep.event_id = e.id AND ep.user_id = $1`
+
+// queryFeed runs the feed query, chronological when ranking is nil.
+func (r *Repository) queryFeed(ctx context.Context, userID int64, limit, offset int, lat, lng *float64, radiusMeters int, filters []string, accessKeys []string, ranking *FeedRanking) ([]models.Event, error) {
+	prefix := ""
+	joins := ""
+	if ranking != nil {
+		prefix = feedAffinityCTE
+		joins = feedStatsJoin
+	}
+	query := prefix + feedEventSelect + joins + `
 WHERE e.is_hidden = false
-	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()`
+	AND e.status = 'published'
+	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()
+	AND ` + nextSeriesOccurrenceCondition(prevOccurrenceRunning)
 	args := []interface{}{userID, limit, offset}
 	privacy := "e.is_private = false OR e.creator_user_id = $1 OR ep.user_id IS NOT NULL"
 	if len(accessKeys) > 0 {
@@ -321,11 +348,22 @@ WHERE e.is_hidden = false
 	AND e.filters && $%d`, filterIdx)
 		args = append(args, filters)
 	}
-	query += `
+	if ranking != nil {
+		var score string
+		score, args = feedScoreExpression(*ranking, lat, lng, args)
+		query += fmt.Sprintf(`
+ORDER BY
+	(e.promoted_until IS NOT NULL AND e.promoted_until > now()) DESC,
+	%s DESC,
+	e.starts_at ASC
+LIMIT $2 OFFSET $3;`, score)
+	} else {
+		query += `
 ORDER BY
 	(e.promoted_until IS NOT NULL AND e.promoted_until > now()) DESC,
 	e.starts_at ASC
 LIMIT $2 OFFSET $3;`
+	}
 
 	rows, err := r.pool.Query(ctx, query, args...)
 	if err != nil {
@@ -335,65 +373,75 @@ LIMIT $2 OFFSET $3;`
 
 	out := make([]models.Event, 0)
 	for rows.Next() {
-		var e models.Event
-		var thumb sql.NullString
-		var contactTelegram sql.NullString
-		var contactWhatsapp sql.NullString
-		var contactWechat sql.NullString
-		var contactFbMessenger sql.NullString
-		var contactSnapchat sql.NullString
-		if err := rows.Scan(
-			&e.ID,
-			&e.Title,
-			&e.Description,
-			&e.StartsAt,
-			&e.EndsAt,
-			&e.Lat,
-			&e.Lng,
-			&e.Capacity,
-			&e.PromotedUntil,
-			&e.Filters,
-			&e.Links,
-			&e.IsPrivate,
-			&e.IsLandingPublished,
-			&contactTelegram,
-			&contactWhatsapp,
-			&contactWechat,
-			&contactFbMessenger,
-			&contactSnapchat,
-			&e.CreatorName,
-			&thumb,
-			&e.Participants,
-			&e.LikesCount,
-			&e.CommentsCount,
-			&e.IsJoined,
-			&e.IsLiked,
-		); err != nil {
+		e, err := scanFeedEvent(rows)
+		if err != nil {
 			return nil, err
 		}
-		if contactTelegram.Valid {
-			e.ContactTelegram = contactTelegram.String
-		}
-		if contactWhatsapp.Valid {
-			e.ContactWhatsapp = contactWhatsapp.String
-		}
-		if contactWechat.Valid {
-			e.ContactWechat = contactWechat.String
-		}
-		if contactFbMessenger.Valid {
-			e.ContactFbMessenger = contactFbMessenger.String
-		}
-		if contactSnapchat.Valid {
-			e.ContactSnapchat = contactSnapchat.String
-		}
-		if thumb.Valid {
-			e.ThumbnailURL = thumb.String
-		}
 		out = append(out, e)
 	}
 	return out, rows.Err()
 }
 
+// scanFeedEvent scans a feed row selected with the GetFeed column list.
+func scanFeedEvent(rows pgx.Rows) (models.Event, error) {
+	var e models.Event
+	var thumb sql.NullString
+	var contactTelegram sql.NullString
+	var contactWhatsapp sql.NullString
+	var contactWechat sql.NullString
+	var contactFbMessenger sql.NullString
+	var contactSnapchat sql.NullString
+	if err := rows.Scan(
+		&e.ID,
+		&e.Title,
+		&e.Description,
+		&e.StartsAt,
+		&e.EndsAt,
+		&e.Lat,
+		&e.Lng,
+		&e.Capacity,
+		&e.PromotedUntil,
+		&e.Filters,
+		&e.Links,
+		&e.IsPrivate,
+		&e.IsLandingPublished,
+		&contactTelegram,
+		&contactWhatsapp,
+		&contactWechat,
+		&contactFbMessenger,
+		&contactSnapchat,
+		&e.CreatorName,
+		&thumb,
+		&e.Participants,
+		&e.LikesCount,
+		&e.CommentsCount,
+		&e.IsJoined,
+		&e.IsLiked,
+		&e.SeriesID,
+	); err != nil {
+		return models.Event{}, err
+	}
+	if contactTelegram.Valid {
+		e.ContactTelegram = contactTelegram.String
+	}
+	if contactWhatsapp.Valid {
+		e.ContactWhatsapp = contactWhatsapp.String
+	}
+	if contactWechat.Valid {
+		e.ContactWechat = contactWechat.String
+	}
+	if contactFbMessenger.Valid {
+		e.ContactFbMessenger = contactFbMessenger.String
+	}
+	if contactSnapchat.Valid {
+		e.ContactSnapchat = contactSnapchat.String
+	}
+	if thumb.Valid {
+		e.ThumbnailURL = thumb.String
+	}
+	return e, nil
+}
+
 // ListLandingEvents lists landing events.
 func (r *Repository) ListLandingEvents(ctx context.Context, limit, offset int) ([]models.Event, int, error) {
 	if limit <= 0 {
@@ -415,6 +463,7 @@ SELECT e.id, e.title, e.description, e.starts_at, e.ends_at,
 FROM events e
 JOIN users u ON u.id = e.creator_user_id
 WHERE e.is_hidden = false
+	AND e.status = 'published'
 	AND e.is_private = false
 	AND e.is_landing_published = true
 	AND COALESCE(e.ends_at, e.starts_at + interval '2 hours') >= now()
@@ -545,6 +594,7 @@ func (r *Repository) ListUserEvents(ctx context.Context, userID int64, limit, of
 SELECT e.id, e.title, e.starts_at,
 	(SELECT count(*) FROM event_participants WHERE event_id = e.id) AS participants_count,
 	(SELECT url FROM event_media WHERE event_id = e.id ORDER BY id ASC LIMIT 1) AS thumbnail_url,
+	e.status, e.publish_at,
 	COUNT(*) OVER() AS total
 FROM events e
 WHERE e.creator_user_id = $1
@@ -561,7 +611,7 @@ LIMIT $2 OFFSET $3;`, userID, limit, offset)
 		var item models.UserEvent
 		var thumb sql.NullString
 		var rowTotal int
-		if err := rows.Scan(&item.ID, &item.Title, &item.StartsAt, &item.ParticipantsCount, &thumb, &rowTotal); err != nil {
+		if err := rows.Scan(&item.ID, &item.Title, &item.StartsAt, &item.ParticipantsCount, &thumb, &item.Status, &item.PublishAt, &rowTotal); err != nil {
 			return nil, 0, err
 		}
 		if thumb.Valid {
@@ -590,9 +640,13 @@ SELECT e.id, e.creator_user_id, e.title, e.description, e.starts_at, e.ends_at,
 	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS creator_name,
 	(SELECT count(*) FROM event_participants WHERE event_id = e.id) AS participants_count,
 	(SELECT count(*) FROM event_likes WHERE event_id = e.id) AS likes_count,
-	(SELECT count(*) FROM event_comments WHERE event_id = e.id) AS comments_count
+	(SELECT count(*) FROM event_comments WHERE event_id = e.id AND is_hidden = false) AS comments_count,
+	e.series_id, e.series_index, e.is_series_exception, s.rrule,
+	COALESCE(e.timezone, s.timezone), e.revision, e.status, e.publish_at,
+	e.canceled_at, e.cancel_reason
 FROM events e
 JOIN users u ON u.id = e.creator_user_id
+LEFT JOIN event_series s ON s.id = e.series_id
 WHERE e.id = $1;`
 
 	row := r.pool.QueryRow(ctx, query, eventID)
@@ -604,6 +658,9 @@ WHERE e.id = $1;`
 	var contactFbMessenger sql.NullString
 	var contactSnapchat sql.NullString
 	var accessKey sql.NullString
+	var rrule sql.NullString
+	var timezone sql.NullString
+	var cancelReason sql.NullString
 	if err := row.Scan(
 		&e.ID,
 		&e.CreatorUserID,
@@ -633,9 +690,20 @@ WHERE e.id = $1;`
 		&e.Participants,
 		&e.LikesCount,
 		&e.CommentsCount,
+		&e.SeriesID,
+		&e.SeriesIndex,
+		&e.IsSeriesException,
+		&rrule,
+		&timezone,
+		&e.Revision,
+		&e.Status,
+		&e.PublishAt,
+		&e.CanceledAt,
+		&cancelReason,
 	); err != nil {
 		return models.Event{}, err
 	}
+	e.CancelReason = cancelReason.String
 	if address.Valid {
 		e.AddressLabel = address.String
 	}
@@ -657,16 +725,22 @@ WHERE e.id = $1;`
 	if accessKey.Valid {
 		e.AccessKey = accessKey.String
 	}
+	if rrule.Valid {
+		e.RecurrenceRule = rrule.String
+	}
+	if timezone.Valid {
+		e.Timezone = timezone.String
+	}
 	return e, nil
 }
 
-// GetParticipantsPreview returns participants preview.
+// GetParticipantsPreview returns participants preview without users who hide their attendance.
 func (r *Repository) GetParticipantsPreview(ctx context.Context, eventID int64, limit int) ([]models.Participant, error) {
 	query := `
 SELECT p.user_id, COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS name, p.joined_at
 FROM event_participants p
 JOIN users u ON u.id = p.user_id
-WHERE p.event_id = $1
+WHERE p.event_id = $1 AND u.hide_attendance = false
 ORDER BY p.joined_at ASC
 LIMIT $2;`
 
@@ -709,6 +783,23 @@ func (r *Repository) LeaveEvent(ctx context.Context, eventID, userID int64) erro
 	return err
 }
 
+// CheckInParticipant marks a participant as present at the event.
+// It returns pgx.ErrNoRows when the user has not joined the event.
+func (r *Repository) CheckInParticipant(ctx context.Context, eventID, userID, staffID int64) error {
+	command, err := r.pool.Exec(ctx, `
+UPDATE event_participants
+SET checked_in_at = COALESCE(checked_in_at, now()),
+	checked_in_by = COALESCE(checked_in_by, $3)
+WHERE event_id = $1 AND user_id = $2;`, eventID, userID, staffID)
+	if err != nil {
+		return err
+	}
+	if command.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
 // LikeEvent likes event.
 func (r *Repository) LikeEvent(ctx context.Context, eventID, userID int64) error {
 	_, err := r.pool.Exec(ctx, `INSERT INTO event_likes (event_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, eventID, userID)
@@ -743,7 +834,7 @@ func (r *Repository) CountEventLikes(ctx context.Context, eventID int64) (int, e
 
 // CountEventComments handles count event comments.
 func (r *Repository) CountEventComments(ctx context.Context, eventID int64) (int, error) {
-	row := r.pool.QueryRow(ctx, `SELECT count(*) FROM event_comments WHERE event_id = $1`, eventID)
+	row := r.pool.QueryRow(ctx, `SELECT count(*) FROM event_comments WHERE event_id = $1 AND is_hidden = false`, eventID)
 	var count int
 	if err := row.Scan(&count); err != nil {
 		return 0, err
@@ -752,33 +843,30 @@ func (r *Repository) CountEventComments(ctx context.Context, eventID int64) (int
 }
 
 // AddEventComment handles add event comment.
-func (r *Repository) AddEventComment(ctx context.Context, eventID, userID int64, body string) (models.EventComment, error) {
+// Hidden comments are held for moderation and left out of lists.
+func (r *Repository) AddEventComment(ctx context.Context, eventID, userID int64, parentID *int64, body string, hidden bool) (models.EventComment, error) {
 	query := `
 WITH inserted AS (
-	INSERT INTO event_comments (event_id, user_id, body)
-	VALUES ($1, $2, $3)
-	RETURNING id, event_id, user_id, body, created_at
+	INSERT INTO event_comments (event_id, user_id, parent_id, body, is_hidden)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id, event_id, parent_id, user_id, body, created_at, edited_at
 )
-SELECT inserted.id, inserted.event_id, inserted.user_id, inserted.body, inserted.created_at,
+SELECT inserted.id, inserted.event_id, inserted.parent_id, inserted.user_id, inserted.body, inserted.created_at, inserted.edited_at,
 	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS user_name
 FROM inserted
 JOIN users u ON u.id = inserted.user_id;`
-	row := r.pool.QueryRow(ctx, query, eventID, userID, body)
-	var comment models.EventComment
-	if err := row.Scan(&comment.ID, &comment.EventID, &comment.UserID, &comment.Body, &comment.CreatedAt, &comment.UserName); err != nil {
+	comment, err := scanEventComment(r.pool.QueryRow(ctx, query, eventID, userID, nullInt64Ptr(parentID), body, hidden))
+	if err != nil {
 		return models.EventComment{}, err
 	}
+	comment.Reactions = []models.CommentReaction{}
 	return comment, nil
 }
 
-// ListEventComments lists event comments.
-func (r *Repository) ListEventComments(ctx context.Context, eventID int64, limit, offset int) ([]models.EventComment, error) {
-	rows, err := r.pool.Query(ctx, `
-SELECT c.id, c.event_id, c.user_id, c.body, c.created_at,
-	COALESCE(u.first_name || ' ' || u.last_name, u.first_name) AS user_name
-FROM event_comments c
-JOIN users u ON u.id = c.user_id
-WHERE c.event_id = $1
+// ListEventComments lists event comments with reactions as seen by viewerID.
+func (r *Repository) ListEventComments(ctx context.Context, eventID, viewerID int64, limit, offset int) ([]models.EventComment, error) {
+	rows, err := r.pool.Query(ctx, eventCommentSelect+`
+WHERE c.event_id = $1 AND c.is_hidden = false
 ORDER BY c.created_at ASC
 LIMIT $2 OFFSET $3;`, eventID, limit, offset)
 	if err != nil {
@@ -787,13 +875,20 @@ LIMIT $2 OFFSET $3;`, eventID, limit, offset)
 	defer rows.Close()
 	comments := make([]models.EventComment, 0)
 	for rows.Next() {
-		var comment models.EventComment
-		if err := rows.Scan(&comment.ID, &comment.EventID, &comment.UserID, &comment.Body, &comment.CreatedAt, &comment.UserName); err != nil {
+		comment, err := scanEventComment(rows)
+		if err != nil {
 			return nil, err
 		}
 		comments = append(comments, comment)
 	}
-	return comments, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	if err := r.attachCommentReactions(ctx, comments, viewerID); err != nil {
+		return nil, err
+	}
+	return comments, nil
 }
 
 // DeleteEventComment deletes event comment.
@@ -833,22 +928,6 @@ RETURNING id;`, job.UserID, job.EventID, job.Kind, job.RunAt, payload, job.Statu
 	return id, nil
 }
 
-// CreateNotificationJobsForAllUsers creates notification jobs for all users.
-func (r *Repository) CreateNotificationJobsForAllUsers(ctx context.Context, eventID int64, kind string, runAt time.Time, payload map[string]interface{}) (int64, error) {
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return 0, err
-	}
-	command, err := r.pool.Exec(ctx, `
-INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
-SELECT id, $1, $2, $3, $4, 'pending'
-FROM users;`, eventID, kind, runAt, payloadBytes)
-	if err != nil {
-		return 0, err
-	}
-	return command.RowsAffected(), nil
-}
-
 // FetchDueNotificationJobs handles fetch due notification jobs.
 func (r *Repository) FetchDueNotificationJobs(ctx context.Context, limit int) ([]models.NotificationJob, error) {
 	query := `
@@ -1003,11 +1082,13 @@ func (r *Repository) CreateEventWithMedia(ctx context.Context, event models.Even
 INSERT INTO events (
 	creator_user_id, title, description, starts_at, ends_at, location, address_label,
 	contact_telegram, contact_whatsapp, contact_wechat, contact_fb_messenger, contact_snapchat,
-	capacity, is_hidden, is_private, access_key, promoted_until, filters, links
+	capacity, is_hidden, is_private, access_key, promoted_until, filters, links, timezone,
+	status, publish_at, published_at
 ) VALUES (
 	$1, $2, $3, $4, $5,
 	ST_SetSRID(ST_MakePoint($6, $7), 4326)::geography,
-	$8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20
+	$8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21,
+	$22, $23, CASE WHEN $22 = 'published' THEN now() END
 ) RETURNING id;`,
 			event.CreatorUserID,
 			event.Title,
@@ -1029,6 +1110,9 @@ INSERT INTO events (
 			event.PromotedUntil,
 			filters,
 			links,
+			nullString(event.Timezone),
+			eventStatus(event.Status),
+			event.PublishAt,
 		)
 		if err := row.Scan(&eventID); err != nil {
 			return err
@@ -1062,7 +1146,7 @@ func (r *Repository) ListEventMedia(ctx context.Context, eventID int64) ([]strin
 
 // SetEventHidden sets event hidden.
 func (r *Repository) SetEventHidden(ctx context.Context, eventID int64, hidden bool) error {
-	_, err := r.pool.Exec(ctx, `UPDATE events SET is_hidden = $1, updated_at = now() WHERE id = $2`, hidden, eventID)
+	_, err := r.pool.Exec(ctx, `UPDATE events SET is_hidden = $1, revision = revision + 1, updated_at = now() WHERE id = $2`, hidden, eventID)
 	return err
 }
 
@@ -1104,6 +1188,7 @@ UPDATE events SET
 	contact_snapchat = $12,
 	capacity = $13,
 	filters = $14,
+	revision = revision + 1,
 	updated_at = now()
 WHERE id = $15;`,
 			event.Title,
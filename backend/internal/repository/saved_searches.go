@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrTooManySavedSearches = errors.New("too many saved searches")
+
+// SavedSearchUpdate holds changed settings of a saved search; nil fields are left as is.
+type SavedSearchUpdate struct {
+	Name          *string
+	AlertsEnabled *bool
+	DailyLimit    *int
+}
+
+const savedSearchSelect = `
+SELECT id, name, query, filters, ST_Y(location::geometry), ST_X(location::geometry), radius_m,
+	starts_after, starts_before, within_days, price, alerts_enabled, daily_limit, created_at, updated_at
+FROM saved_searches`
+
+// savedSearchMatchCondition matches saved searches s against event e at time $2.
+// Paid events are events with an active ticket above zero.
+const savedSearchMatchCondition = `
+	(cardinality(s.filters) = 0 OR s.filters && e.filters)
+	AND (s.location IS NULL OR ST_DWithin(e.location, s.location, s.radius_m))
+	AND (s.starts_after IS NULL OR e.starts_at >= s.starts_after)
+	AND (s.starts_before IS NULL OR e.starts_at < s.starts_before)
+	AND (s.within_days IS NULL OR e.starts_at < $2::timestamptz + make_interval(days => s.within_days))
+	AND (s.price = 'any' OR (s.price = 'paid') = EXISTS (
+		SELECT 1 FROM ticket_products tp
+		WHERE tp.event_id = e.id AND tp.is_active = true AND tp.price_cents > 0
+	))
+	AND (s.query IS NULL OR e.search_vector @@ (
+		websearch_to_tsquery('russian', s.query)
+		|| websearch_to_tsquery('english', s.query)
+		|| websearch_to_tsquery('simple', s.query)
+	))`
+
+// ListSavedSearches lists saved searches of the user, newest first.
+func (r *Repository) ListSavedSearches(ctx context.Context, userID int64) ([]models.SavedSearch, error) {
+	rows, err := r.pool.Query(ctx, savedSearchSelect+`
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]models.SavedSearch, 0)
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, search)
+	}
+	return out, rows.Err()
+}
+
+// CreateSavedSearch stores a saved search of the user. It returns
+// ErrTooManySavedSearches when the user already has maxPerUser searches.
+func (r *Repository) CreateSavedSearch(ctx context.Context, userID int64, search models.SavedSearch, maxPerUser int) (models.SavedSearch, error) {
+	var out models.SavedSearch
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID); err != nil {
+			return err
+		}
+		var count int
+		if err := tx.QueryRow(ctx, `SELECT count(*) FROM saved_searches WHERE user_id = $1`, userID).Scan(&count); err != nil {
+			return err
+		}
+		if count >= maxPerUser {
+			return ErrTooManySavedSearches
+		}
+		filters := search.Filters
+		if filters == nil {
+			filters = []string{}
+		}
+		var id int64
+		if err := tx.QueryRow(ctx, `
+INSERT INTO saved_searches (
+	user_id, name, query, filters, location, radius_m,
+	starts_after, starts_before, within_days, price, alerts_enabled, daily_limit
+) VALUES (
+	$1, $2, $3, $4,
+	CASE WHEN $5::float8 IS NULL OR $6::float8 IS NULL THEN NULL ELSE ST_SetSRID(ST_MakePoint($6, $5), 4326)::geography END,
+	$7, $8, $9, $10, $11, $12, $13
+)
+RETURNING id;`,
+			userID,
+			search.Name,
+			nullString(search.Query),
+			filters,
+			search.Lat,
+			search.Lng,
+			search.RadiusMeters,
+			search.StartsAfter,
+			search.StartsBefore,
+			search.WithinDays,
+			search.Price,
+			search.AlertsEnabled,
+			search.DailyLimit,
+		).Scan(&id); err != nil {
+			return err
+		}
+		var err error
+		out, err = scanSavedSearch(tx.QueryRow(ctx, savedSearchSelect+`
+WHERE id = $1;`, id))
+		return err
+	})
+	if err != nil {
+		return models.SavedSearch{}, err
+	}
+	return out, nil
+}
+
+// UpdateSavedSearch changes settings of a saved search owned by the user.
+// It returns pgx.ErrNoRows when the user has no such search.
+func (r *Repository) UpdateSavedSearch(ctx context.Context, userID, searchID int64, update SavedSearchUpdate) (models.SavedSearch, error) {
+	return scanSavedSearch(r.pool.QueryRow(ctx, `
+WITH updated AS (
+	UPDATE saved_searches
+	SET name = COALESCE($3, name),
+		alerts_enabled = COALESCE($4, alerts_enabled),
+		daily_limit = COALESCE($5, daily_limit),
+		updated_at = now()
+	WHERE id = $1 AND user_id = $2
+	RETURNING *
+)
+SELECT id, name, query, filters, ST_Y(location::geometry), ST_X(location::geometry), radius_m,
+	starts_after, starts_before, within_days, price, alerts_enabled, daily_limit, created_at, updated_at
+FROM updated;`, searchID, userID, update.Name, update.AlertsEnabled, update.DailyLimit))
+}
+
+// DeleteSavedSearch removes a saved search of the user and reports whether it existed.
+func (r *Repository) DeleteSavedSearch(ctx context.Context, userID, searchID int64) (bool, error) {
+	command, err := r.pool.Exec(ctx, `DELETE FROM saved_searches WHERE id = $1 AND user_id = $2`, searchID, userID)
+	if err != nil {
+		return false, err
+	}
+	return command.RowsAffected() > 0, nil
+}
+
+// EnqueueSavedSearchAlerts evaluates a published public event against saved
+// searches with alerts enabled and enqueues a saved_search_alert job for each
+// matching user. A user is alerted about an event once, even when several of
+// their searches match, and not at all when they already got an announcement
+// of it, muted saved search alerts, reached the daily limit of the search or
+// can't be reached by any channel.
+func (r *Repository) EnqueueSavedSearchAlerts(ctx context.Context, eventID int64, now time.Time, payload map[string]interface{}) (int64, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+	command, err := r.pool.Exec(ctx, `
+WITH matched AS (
+	INSERT INTO saved_search_alerts (search_id, event_id, user_id, created_at)
+	SELECT DISTINCT ON (s.user_id) s.id, e.id, s.user_id, $2
+	FROM events e
+	JOIN saved_searches s ON s.alerts_enabled = true AND s.user_id <> e.creator_user_id
+	JOIN users u ON u.id = s.user_id AND u.is_blocked = false
+	WHERE e.id = $1
+		AND e.status = 'published'
+		AND e.is_hidden = false
+		AND e.is_private = false
+		AND e.starts_at > $2
+		AND NOT 'saved_searches' = ANY(u.notify_muted)
+		AND `+notifiableUserCondition+`
+		AND `+savedSearchMatchCondition+`
+		AND (
+			SELECT count(*) FROM saved_search_alerts a
+			WHERE a.search_id = s.id AND a.created_at > $2::timestamptz - interval '24 hours'
+		) < s.daily_limit
+		AND NOT EXISTS (
+			SELECT 1 FROM notification_jobs nj
+			WHERE nj.user_id = s.user_id AND nj.event_id = e.id
+				AND nj.kind IN ('event_followed', 'event_created', 'event_nearby')
+		)
+	ORDER BY s.user_id, s.id
+	ON CONFLICT DO NOTHING
+	RETURNING search_id, user_id
+)
+INSERT INTO notification_jobs (user_id, event_id, kind, run_at, payload, status)
+SELECT m.user_id, $1, 'saved_search_alert', $2,
+	$3::jsonb || jsonb_build_object('searchId', s.id, 'searchName', s.name),
+	'pending'
+FROM matched m
+JOIN saved_searches s ON s.id = m.search_id;`, eventID, now, payloadBytes)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
+
+// scanSavedSearch scans a saved search row.
+func scanSavedSearch(row pgx.Row) (models.SavedSearch, error) {
+	var out models.SavedSearch
+	var query sql.NullString
+	var lat sql.NullFloat64
+	var lng sql.NullFloat64
+	var radius sql.NullInt32
+	var withinDays sql.NullInt32
+	if err := row.Scan(
+		&out.ID,
+		&out.Name,
+		&query,
+		&out.Filters,
+		&lat,
+		&lng,
+		&radius,
+		&out.StartsAfter,
+		&out.StartsBefore,
+		&withinDays,
+		&out.Price,
+		&out.AlertsEnabled,
+		&out.DailyLimit,
+		&out.CreatedAt,
+		&out.UpdatedAt,
+	); err != nil {
+		return models.SavedSearch{}, err
+	}
+	out.Query = query.String
+	if out.Filters == nil {
+		out.Filters = []string{}
+	}
+	if lat.Valid && lng.Valid {
+		out.Lat = &lat.Float64
+		out.Lng = &lng.Float64
+	}
+	out.RadiusMeters = nullInt32ToIntPtr(radius)
+	out.WithinDays = nullInt32ToIntPtr(withinDays)
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+)
+
+// MarkTelegramUnreachable records that the bot can't message the user, which
+// leaves them out of broadcasts and Telegram notification delivery.
+func (r *Repository) MarkTelegramUnreachable(ctx context.Context, userID int64, reason string) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE users
+SET telegram_unreachable_at = COALESCE(telegram_unreachable_at, now()),
+	telegram_unreachable_reason = $2,
+	updated_at = now()
+WHERE id = $1;`, userID, nullString(reason))
+	return err
+}
+
+// MarkTelegramChatUnreachable records that the bot can't message the user with the telegram id.
+func (r *Repository) MarkTelegramChatUnreachable(ctx context.Context, telegramID int64, reason string) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE users
+SET telegram_unreachable_at = COALESCE(telegram_unreachable_at, now()),
+	telegram_unreachable_reason = $2,
+	updated_at = now()
+WHERE telegram_id = $1;`, telegramID, nullString(reason))
+	return err
+}
+
+// ClearTelegramUnreachable marks the user with the telegram id as reachable
+// again after they started or unblocked the bot.
+func (r *Repository) ClearTelegramUnreachable(ctx context.Context, telegramID int64) error {
+	_, err := r.pool.Exec(ctx, `
+UPDATE users
+SET telegram_unreachable_at = NULL,
+	telegram_unreachable_reason = NULL,
+	updated_at = now()
+WHERE telegram_id = $1 AND telegram_unreachable_at IS NOT NULL;`, telegramID)
+	return err
+}
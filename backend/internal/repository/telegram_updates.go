@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// ClaimTelegramUpdate records a webhook update id and reports whether it is
+// new; redelivered updates return false.
+func (r *Repository) ClaimTelegramUpdate(ctx context.Context, updateID int64) (bool, error) {
+	command, err := r.pool.Exec(ctx, `
+INSERT INTO telegram_updates (update_id)
+VALUES ($1)
+ON CONFLICT (update_id) DO NOTHING;`, updateID)
+	if err != nil {
+		return false, err
+	}
+	return command.RowsAffected() == 1, nil
+}
+
+// ReleaseTelegramUpdate forgets a claimed update id so its redelivery is
+// processed again.
+func (r *Repository) ReleaseTelegramUpdate(ctx context.Context, updateID int64) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM telegram_updates WHERE update_id = $1`, updateID)
+	return err
+}
+
+// PruneTelegramUpdates deletes update ids received before the cutoff.
+func (r *Repository) PruneTelegramUpdates(ctx context.Context, before time.Time) (int64, error) {
+	command, err := r.pool.Exec(ctx, `DELETE FROM telegram_updates WHERE received_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return command.RowsAffected(), nil
+}
@@ -28,6 +28,15 @@ var (
 	ErrTicketNotFound        = errors.New("ticket not found")
 )
 
+// ownedEventCondition restricts rows to events created by the user in parameter ownerArg.
+// A NULL owner parameter disables the restriction.
+func ownedEventCondition(eventExpr string, ownerArg int) string {
+	return fmt.Sprintf(
+		"($%d::bigint IS NULL OR EXISTS (SELECT 1 FROM events owned WHERE owned.id = %s AND owned.creator_user_id = $%d))",
+		ownerArg, eventExpr, ownerArg,
+	)
+}
+
 // queryRunner represents query runner.
 type queryRunner interface {
 	Query(context.Context, string, ...interface{}) (pgx.Rows, error)
@@ -100,13 +109,15 @@ RETURNING phone_number, usdt_wallet, usdt_network, usdt_memo,
 }
 
 // ListTicketProducts lists ticket products.
-func (r *Repository) ListTicketProducts(ctx context.Context, eventID *int64, active *bool) ([]models.TicketProduct, error) {
+// A non-nil ownerID limits the list to events created by that user.
+func (r *Repository) ListTicketProducts(ctx context.Context, eventID *int64, active *bool, ownerID *int64) ([]models.TicketProduct, error) {
 	rows, err := r.pool.Query(ctx, `
 SELECT id::text, event_id, name, type, price_cents, inventory_limit, sold_count, is_active, created_by, created_at, updated_at
 FROM ticket_products
 WHERE ($1::bigint IS NULL OR event_id = $1)
 	AND ($2::boolean IS NULL OR is_active = $2)
-ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
+	AND `+ownedEventCondition("event_id", 3)+`
+ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active), nullInt64Ptr(ownerID))
 	if err != nil {
 		return nil, err
 	}
@@ -124,10 +135,12 @@ ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
 }
 
 // CreateTicketProduct creates ticket product.
-func (r *Repository) CreateTicketProduct(ctx context.Context, createdBy int64, in models.TicketProductInput) (models.TicketProduct, error) {
+// A non-nil ownerID requires the event to be created by that user, otherwise pgx.ErrNoRows is returned.
+func (r *Repository) CreateTicketProduct(ctx context.Context, createdBy int64, in models.TicketProductInput, ownerID *int64) (models.TicketProduct, error) {
 	row := r.pool.QueryRow(ctx, `
 INSERT INTO ticket_products (event_id, name, type, price_cents, inventory_limit, is_active, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7)
+SELECT $1::bigint, $2::text, $3::text, $4::bigint, $5::int, $6::boolean, $7::bigint
+WHERE `+ownedEventCondition("$1::bigint", 8)+`
 RETURNING id::text, event_id, name, type, price_cents, inventory_limit, sold_count, is_active, created_by, created_at, updated_at;`,
 		in.EventID,
 		strings.TrimSpace(in.Name),
@@ -136,12 +149,14 @@ RETURNING id::text, event_id, name, type, price_cents, inventory_limit, sold_cou
 		nullIntPtr(in.InventoryLimit),
 		in.IsActive,
 		nullInt64Ptr(&createdBy),
+		nullInt64Ptr(ownerID),
 	)
 	return scanTicketProduct(row)
 }
 
 // UpdateTicketProduct updates ticket product.
-func (r *Repository) UpdateTicketProduct(ctx context.Context, id string, patch models.TicketProductPatch) (models.TicketProduct, error) {
+// A non-nil ownerID limits the update to events created by that user.
+func (r *Repository) UpdateTicketProduct(ctx context.Context, id string, patch models.TicketProductPatch, ownerID *int64) (models.TicketProduct, error) {
 	row := r.pool.QueryRow(ctx, `
 UPDATE ticket_products
 SET price_cents = COALESCE($2, price_cents),
@@ -149,18 +164,21 @@ SET price_cents = COALESCE($2, price_cents),
 	is_active = COALESCE($4, is_active),
 	updated_at = now()
 WHERE id = $1::uuid
+	AND `+ownedEventCondition("event_id", 5)+`
 RETURNING id::text, event_id, name, type, price_cents, inventory_limit, sold_count, is_active, created_by, created_at, updated_at;`,
 		id,
 		int64PtrOrNil(patch.PriceCents),
 		nullIntPtr(patch.InventoryLimit),
 		boolPtrOrNil(patch.IsActive),
+		nullInt64Ptr(ownerID),
 	)
 	return scanTicketProduct(row)
 }
 
 // DeleteTicketProduct deletes ticket product.
-func (r *Repository) DeleteTicketProduct(ctx context.Context, id string) error {
-	cmd, err := r.pool.Exec(ctx, `DELETE FROM ticket_products WHERE id = $1::uuid`, id)
+// A non-nil ownerID limits the delete to events created by that user.
+func (r *Repository) DeleteTicketProduct(ctx context.Context, id string, ownerID *int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM ticket_products WHERE id = $1::uuid AND `+ownedEventCondition("event_id", 2), id, nullInt64Ptr(ownerID))
 	if err != nil {
 		return err
 	}
@@ -171,13 +189,15 @@ func (r *Repository) DeleteTicketProduct(ctx context.Context, id string) error {
 }
 
 // ListTransferProducts lists transfer products.
-func (r *Repository) ListTransferProducts(ctx context.Context, eventID *int64, active *bool) ([]models.TransferProduct, error) {
+// A non-nil ownerID limits the list to events created by that user.
+func (r *Repository) ListTransferProducts(ctx context.Context, eventID *int64, active *bool, ownerID *int64) ([]models.TransferProduct, error) {
 	rows, err := r.pool.Query(ctx, `
 SELECT id::text, event_id, name, direction, price_cents, info_json, inventory_limit, sold_count, is_active, created_by, created_at, updated_at
 FROM transfer_products
 WHERE ($1::bigint IS NULL OR event_id = $1)
 	AND ($2::boolean IS NULL OR is_active = $2)
-ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
+	AND `+ownedEventCondition("event_id", 3)+`
+ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active), nullInt64Ptr(ownerID))
 	if err != nil {
 		return nil, err
 	}
@@ -195,11 +215,13 @@ ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
 }
 
 // CreateTransferProduct creates transfer product.
-func (r *Repository) CreateTransferProduct(ctx context.Context, createdBy int64, in models.TransferProductInput) (models.TransferProduct, error) {
+// A non-nil ownerID requires the event to be created by that user, otherwise pgx.ErrNoRows is returned.
+func (r *Repository) CreateTransferProduct(ctx context.Context, createdBy int64, in models.TransferProductInput, ownerID *int64) (models.TransferProduct, error) {
 	infoJSON, _ := json.Marshal(safeMap(in.Info))
 	row := r.pool.QueryRow(ctx, `
 INSERT INTO transfer_products (event_id, name, direction, price_cents, info_json, inventory_limit, is_active, created_by)
-VALUES ($1, $2, $3, $4, $5::jsonb, $6, $7, $8)
+SELECT $1::bigint, $2::text, $3::text, $4::bigint, $5::jsonb, $6::int, $7::boolean, $8::bigint
+WHERE `+ownedEventCondition("$1::bigint", 9)+`
 RETURNING id::text, event_id, name, direction, price_cents, info_json, inventory_limit, sold_count, is_active, created_by, created_at, updated_at;`,
 		in.EventID,
 		strings.TrimSpace(in.Name),
@@ -209,12 +231,14 @@ RETURNING id::text, event_id, name, direction, price_cents, info_json, inventory
 		nullIntPtr(in.InventoryLimit),
 		in.IsActive,
 		nullInt64Ptr(&createdBy),
+		nullInt64Ptr(ownerID),
 	)
 	return scanTransferProduct(row)
 }
 
 // UpdateTransferProduct updates transfer product.
-func (r *Repository) UpdateTransferProduct(ctx context.Context, id string, patch models.TransferProductPatch) (models.TransferProduct, error) {
+// A non-nil ownerID limits the update to events created by that user.
+func (r *Repository) UpdateTransferProduct(ctx context.Context, id string, patch models.TransferProductPatch, ownerID *int64) (models.TransferProduct, error) {
 	var infoRaw interface{}
 	if patch.Info != nil {
 		buf, _ := json.Marshal(patch.Info)
@@ -229,19 +253,22 @@ SET price_cents = COALESCE($2, price_cents),
 	is_active = COALESCE($5, is_active),
 	updated_at = now()
 WHERE id = $1::uuid
+	AND `+ownedEventCondition("event_id", 6)+`
 RETURNING id::text, event_id, name, direction, price_cents, info_json, inventory_limit, sold_count, is_active, created_by, created_at, updated_at;`,
 		id,
 		int64PtrOrNil(patch.PriceCents),
 		infoRaw,
 		nullIntPtr(patch.InventoryLimit),
 		boolPtrOrNil(patch.IsActive),
+		nullInt64Ptr(ownerID),
 	)
 	return scanTransferProduct(row)
 }
 
 // DeleteTransferProduct deletes transfer product.
-func (r *Repository) DeleteTransferProduct(ctx context.Context, id string) error {
-	cmd, err := r.pool.Exec(ctx, `DELETE FROM transfer_products WHERE id = $1::uuid`, id)
+// A non-nil ownerID limits the delete to events created by that user.
+func (r *Repository) DeleteTransferProduct(ctx context.Context, id string, ownerID *int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM transfer_products WHERE id = $1::uuid AND `+ownedEventCondition("event_id", 2), id, nullInt64Ptr(ownerID))
 	if err != nil {
 		return err
 	}
@@ -252,13 +279,15 @@ func (r *Repository) DeleteTransferProduct(ctx context.Context, id string) error
 }
 
 // ListPromoCodes lists promo codes.
-func (r *Repository) ListPromoCodes(ctx context.Context, eventID *int64, active *bool) ([]models.PromoCode, error) {
+// A non-nil ownerID limits the list to codes bound to events created by that user; global codes are skipped.
+func (r *Repository) ListPromoCodes(ctx context.Context, eventID *int64, active *bool, ownerID *int64) ([]models.PromoCode, error) {
 	rows, err := r.pool.Query(ctx, `
 SELECT id::text, code, discount_type, value, usage_limit, used_count, active_from, active_to, event_id, is_active, created_by, created_at, updated_at
 FROM promo_codes
 WHERE ($1::bigint IS NULL OR event_id = $1 OR event_id IS NULL)
 	AND ($2::boolean IS NULL OR is_active = $2)
-ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
+	AND `+ownedEventCondition("event_id", 3)+`
+ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active), nullInt64Ptr(ownerID))
 	if err != nil {
 		return nil, err
 	}
@@ -276,10 +305,12 @@ ORDER BY created_at DESC;`, nullInt64Ptr(eventID), boolPtrOrNil(active))
 }
 
 // CreatePromoCode creates promo code.
-func (r *Repository) CreatePromoCode(ctx context.Context, createdBy int64, in models.PromoCodeInput) (models.PromoCode, error) {
+// A non-nil ownerID requires an event created by that user, otherwise pgx.ErrNoRows is returned.
+func (r *Repository) CreatePromoCode(ctx context.Context, createdBy int64, in models.PromoCodeInput, ownerID *int64) (models.PromoCode, error) {
 	row := r.pool.QueryRow(ctx, `
 INSERT INTO promo_codes (code, discount_type, value, usage_limit, active_from, active_to, event_id, is_active, created_by)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+SELECT $1::text, $2::text, $3::bigint, $4::int, $5::timestamptz, $6::timestamptz, $7::bigint, $8::boolean, $9::bigint
+WHERE `+ownedEventCondition("$7::bigint", 10)+`
 RETURNING id::text, code, discount_type, value, usage_limit, used_count, active_from, active_to, event_id, is_active, created_by, created_at, updated_at;`,
 		strings.ToUpper(strings.TrimSpace(in.Code)),
 		strings.ToUpper(strings.TrimSpace(in.DiscountType)),
@@ -290,12 +321,15 @@ RETURNING id::text, code, discount_type, value, usage_limit, used_count, active_
 		nullInt64Ptr(in.EventID),
 		in.IsActive,
 		nullInt64Ptr(&createdBy),
+		nullInt64Ptr(ownerID),
 	)
 	return scanPromoCode(row)
 }
 
 // UpdatePromoCode updates promo code.
-func (r *Repository) UpdatePromoCode(ctx context.Context, id string, patch models.PromoCodePatch) (models.PromoCode, error) {
+// A non-nil ownerID limits the update to codes of events created by that user and
+// only allows moving a code to another event of the same user.
+func (r *Repository) UpdatePromoCode(ctx context.Context, id string, patch models.PromoCodePatch, ownerID *int64) (models.PromoCode, error) {
 	var discountType interface{}
 	if patch.DiscountType != nil {
 		discountType = strings.ToUpper(strings.TrimSpace(*patch.DiscountType))
@@ -312,6 +346,8 @@ SET discount_type = COALESCE($2, discount_type),
 	is_active = COALESCE($8, is_active),
 	updated_at = now()
 WHERE id = $1::uuid
+	AND `+ownedEventCondition("event_id", 9)+`
+	AND ($7::bigint IS NULL OR `+ownedEventCondition("$7::bigint", 9)+`)
 RETURNING id::text, code, discount_type, value, usage_limit, used_count, active_from, active_to, event_id, is_active, created_by, created_at, updated_at;`,
 		id,
 		discountType,
@@ -321,13 +357,15 @@ RETURNING id::text, code, discount_type, value, usage_limit, used_count, active_
 		patch.ActiveTo,
 		nullInt64Ptr(patch.EventID),
 		boolPtrOrNil(patch.IsActive),
+		nullInt64Ptr(ownerID),
 	)
 	return scanPromoCode(row)
 }
 
 // DeletePromoCode deletes promo code.
-func (r *Repository) DeletePromoCode(ctx context.Context, id string) error {
-	cmd, err := r.pool.Exec(ctx, `DELETE FROM promo_codes WHERE id = $1::uuid`, id)
+// A non-nil ownerID limits the delete to codes of events created by that user.
+func (r *Repository) DeletePromoCode(ctx context.Context, id string, ownerID *int64) error {
+	cmd, err := r.pool.Exec(ctx, `DELETE FROM promo_codes WHERE id = $1::uuid AND `+ownedEventCondition("event_id", 2), id, nullInt64Ptr(ownerID))
 	if err != nil {
 		return err
 	}
@@ -341,11 +379,11 @@ func (r *Repository) DeletePromoCode(ctx context.Context, id string) error {
 func (r *Repository) ListEventProductsForPurchase(ctx context.Context, eventID int64) ([]models.TicketProduct, []models.TransferProduct, error) {
 	eid := eventID
 	onlyActive := true
-	tickets, err := r.ListTicketProducts(ctx, &eid, &onlyActive)
+	tickets, err := r.ListTicketProducts(ctx, &eid, &onlyActive, nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	transfers, err := r.ListTransferProducts(ctx, &eid, &onlyActive)
+	transfers, err := r.ListTransferProducts(ctx, &eid, &onlyActive, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -449,7 +487,7 @@ func (r *Repository) createOrderTx(
 	out *models.OrderDetail,
 ) error {
 	var eventTitle string
-	if err := tx.QueryRow(ctx, `SELECT title FROM events WHERE id = $1`, params.EventID).Scan(&eventTitle); err != nil {
+	if err := tx.QueryRow(ctx, `SELECT title FROM events WHERE id = $1 AND status = 'published'`, params.EventID).Scan(&eventTitle); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrInvalidProduct
 		}
@@ -729,7 +767,8 @@ RETURNING id::text, order_id::text, user_id, event_id, ticket_type, quantity, qr
 }
 
 // ListOrders lists orders.
-func (r *Repository) ListOrders(ctx context.Context, eventID *int64, status string, from, to *time.Time, limit, offset int) ([]models.OrderSummary, int, error) {
+// A non-nil ownerID limits the list to events created by that user.
+func (r *Repository) ListOrders(ctx context.Context, eventID *int64, status string, from, to *time.Time, limit, offset int, ownerID *int64) ([]models.OrderSummary, int, error) {
 	status = strings.ToUpper(strings.TrimSpace(status))
 	if limit <= 0 {
 		limit = 50
@@ -748,7 +787,8 @@ FROM orders o
 WHERE ($1::bigint IS NULL OR o.event_id = $1)
 	AND ($2::text = '' OR o.status = $2)
 	AND ($3::timestamptz IS NULL OR o.created_at >= $3)
-	AND ($4::timestamptz IS NULL OR o.created_at <= $4);`, nullInt64Ptr(eventID), status, from, to).Scan(&total); err != nil {
+	AND ($4::timestamptz IS NULL OR o.created_at <= $4)
+	AND `+ownedEventCondition("o.event_id", 5)+`;`, nullInt64Ptr(eventID), status, from, to, nullInt64Ptr(ownerID)).Scan(&total); err != nil {
 		return nil, 0, err
 	}
 
@@ -787,8 +827,9 @@ WHERE ($1::bigint IS NULL OR o.event_id = $1)
 	AND ($2::text = '' OR o.status = $2)
 	AND ($3::timestamptz IS NULL OR o.created_at >= $3)
 	AND ($4::timestamptz IS NULL OR o.created_at <= $4)
+	AND `+ownedEventCondition("o.event_id", 5)+`
 ORDER BY o.created_at DESC
-LIMIT $5 OFFSET $6;`, nullInt64Ptr(eventID), status, from, to, limit, offset)
+LIMIT $6 OFFSET $7;`, nullInt64Ptr(eventID), status, from, to, nullInt64Ptr(ownerID), limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -1179,92 +1220,107 @@ func (r *Repository) CancelOrder(ctx context.Context, orderID string, adminID in
 	var detail models.OrderDetail
 	reason = strings.TrimSpace(reason)
 	err := r.WithTx(ctx, func(tx pgx.Tx) error {
-		var status string
-		var promoCodeID sql.NullString
-		if err := tx.QueryRow(ctx, `
+		if err := r.cancelOrderTx(ctx, tx, orderID, adminID, reason); err != nil {
+			return err
+		}
+		var err error
+		detail, err = r.fetchOrderDetail(ctx, tx, orderID, true)
+		return err
+	})
+	if err != nil {
+		return models.OrderDetail{}, err
+	}
+	return detail, nil
+}
+
+// cancelOrderTx cancels an order inside tx, releasing inventory and promo usage.
+func (r *Repository) cancelOrderTx(ctx context.Context, tx pgx.Tx, orderID string, adminID int64, reason string) error {
+	var status string
+	var promoCodeID sql.NullString
+	if err := tx.QueryRow(ctx, `
 SELECT status, promo_code_id::text
 FROM orders
 WHERE id = $1::uuid
 FOR UPDATE;`, orderID).Scan(&status, &promoCodeID); err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				return ErrOrderNotFound
-			}
-			return err
-		}
-		if status == models.OrderStatusRedeemed || status == models.OrderStatusCanceled {
-			return ErrOrderStateNotAllowed
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrOrderNotFound
 		}
+		return err
+	}
+	if status == models.OrderStatusRedeemed || status == models.OrderStatusCanceled {
+		return ErrOrderStateNotAllowed
+	}
 
-		if isPaidOrderStatus(status) {
-			// lockedOrderItem represents locked order item.
-			type lockedOrderItem struct {
-				itemType string
-				product  string
-				quantity int
-			}
-			rows, err := tx.Query(ctx, `
+	if isPaidOrderStatus(status) {
+		// lockedOrderItem represents locked order item.
+		type lockedOrderItem struct {
+			itemType string
+			product  string
+			quantity int
+		}
+		rows, err := tx.Query(ctx, `
 SELECT item_type, product_id::text, quantity
 FROM order_items
 WHERE order_id = $1::uuid
 ORDER BY id ASC
 FOR UPDATE;`, orderID)
-			if err != nil {
-				return err
-			}
-			lockedItems := make([]lockedOrderItem, 0, 8)
-			for rows.Next() {
-				var itemType string
-				var productID string
-				var quantity int
-				if err := rows.Scan(&itemType, &productID, &quantity); err != nil {
-					rows.Close()
-					return err
-				}
-				lockedItems = append(lockedItems, lockedOrderItem{
-					itemType: itemType,
-					product:  productID,
-					quantity: quantity,
-				})
-			}
-			if err := rows.Err(); err != nil {
+		if err != nil {
+			return err
+		}
+		lockedItems := make([]lockedOrderItem, 0, 8)
+		for rows.Next() {
+			var itemType string
+			var productID string
+			var quantity int
+			if err := rows.Scan(&itemType, &productID, &quantity); err != nil {
 				rows.Close()
 				return err
 			}
+			lockedItems = append(lockedItems, lockedOrderItem{
+				itemType: itemType,
+				product:  productID,
+				quantity: quantity,
+			})
+		}
+		if err := rows.Err(); err != nil {
 			rows.Close()
+			return err
+		}
+		rows.Close()
 
-			for _, item := range lockedItems {
-				switch item.itemType {
-				case models.ItemTypeTicket:
-					if _, err := tx.Exec(ctx, `
+		for _, item := range lockedItems {
+			switch item.itemType {
+			case models.ItemTypeTicket:
+				if _, err := tx.Exec(ctx, `
 UPDATE ticket_products
 SET sold_count = GREATEST(0, sold_count - $2),
 	updated_at = now()
 WHERE id = $1::uuid;`, item.product, item.quantity); err != nil {
-						return err
-					}
-				case models.ItemTypeTransfer:
-					if _, err := tx.Exec(ctx, `
+					return err
+				}
+			case models.ItemTypeTransfer:
+				if _, err := tx.Exec(ctx, `
 UPDATE transfer_products
 SET sold_count = GREATEST(0, sold_count - $2),
 	updated_at = now()
 WHERE id = $1::uuid;`, item.product, item.quantity); err != nil {
-						return err
-					}
+					return err
 				}
 			}
 		}
+	}
 
-		if promoCodeID.Valid && promoCodeID.String != "" {
-			if _, err := tx.Exec(ctx, `
+	if promoCodeID.Valid && promoCodeID.String != "" {
+		if _, err := tx.Exec(ctx, `
 UPDATE promo_codes
 SET used_count = GREATEST(0, used_count - 1),
 	updated_at = now()
 WHERE id = $1::uuid;`, promoCodeID.String); err != nil {
-				return err
-			}
+			return err
 		}
+	}
 
-		cmd, err := tx.Exec(ctx, `
+	cmd, err := tx.Exec(ctx, `
 UPDATE orders
 SET status = $2,
 	canceled_at = now(),
@@ -1272,20 +1328,13 @@ SET status = $2,
 	canceled_reason = $4,
 	updated_at = now()
 WHERE id = $1::uuid;`, orderID, models.OrderStatusCanceled, adminID, nullString(reason))
-		if err != nil {
-			return err
-		}
-		if cmd.RowsAffected() == 0 {
-			return ErrOrderNotFound
-		}
-
-		detail, err = r.fetchOrderDetail(ctx, tx, orderID, true)
-		return err
-	})
 	if err != nil {
-		return models.OrderDetail{}, err
+		return err
 	}
-	return detail, nil
+	if cmd.RowsAffected() == 0 {
+		return ErrOrderNotFound
+	}
+	return nil
 }
 
 // DeleteOrder deletes order.
@@ -1592,7 +1641,8 @@ func scanMyTicketWithOrderStatus(row pgx.Row) (models.Ticket, error) {
 }
 
 // GetTicketStats returns ticket stats.
-func (r *Repository) GetTicketStats(ctx context.Context, eventID *int64) (models.TicketStats, error) {
+// A non-nil ownerID limits the stats to events created by that user.
+func (r *Repository) GetTicketStats(ctx context.Context, eventID *int64, ownerID *int64) (models.TicketStats, error) {
 	rows, err := r.pool.Query(ctx, `
 SELECT
 	o.id::text,
@@ -1607,7 +1657,8 @@ FROM orders o
 JOIN events e ON e.id = o.event_id
 LEFT JOIN order_items oi ON oi.order_id = o.id
 WHERE ($1::bigint IS NULL OR o.event_id = $1)
-ORDER BY o.created_at DESC;`, nullInt64Ptr(eventID))
+	AND ($2::bigint IS NULL OR e.creator_user_id = $2)
+ORDER BY o.created_at DESC;`, nullInt64Ptr(eventID), nullInt64Ptr(ownerID))
 	if err != nil {
 		return models.TicketStats{}, err
 	}
@@ -1680,9 +1731,10 @@ FROM tickets t
 JOIN orders o ON o.id = t.order_id
 LEFT JOIN events e ON e.id = t.event_id
 WHERE ($1::bigint IS NULL OR t.event_id = $1)
+  AND ($2::bigint IS NULL OR e.creator_user_id = $2)
   AND UPPER(TRIM(o.status)) IN ('PAID', 'CONFIRMED', 'REDEEMED')
 GROUP BY t.event_id, e.title
-ORDER BY t.event_id ASC;`, nullInt64Ptr(eventID))
+ORDER BY t.event_id ASC;`, nullInt64Ptr(eventID), nullInt64Ptr(ownerID))
 	if err != nil {
 		return models.TicketStats{}, err
 	}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
 	"testing"
 
@@ -149,3 +150,62 @@ VALUES ($1::uuid, $2, $3::uuid, $4, $5, 1000, $6);`,
 
 	return orderID, productID, nil
 }
+
+// TestCreateOrderRequiresPublishedEvent verifies create order requires published event behavior.
+func TestCreateOrderRequiresPublishedEvent(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+	userID, err := insertTicketingTestUser(ctx, pool, 778303)
+	if err != nil {
+		t.Fatalf("insert user: %v", err)
+	}
+	eventID, err := insertTicketingTestEvent(ctx, pool, userID)
+	if err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM orders WHERE event_id = $1`, eventID)
+		_, _ = pool.Exec(ctx, `DELETE FROM ticket_products WHERE event_id = $1`, eventID)
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, userID)
+	})
+
+	var productID string
+	if err := pool.QueryRow(ctx, `
+INSERT INTO ticket_products (event_id, type, price_cents, sold_count, is_active)
+VALUES ($1, $2, 1000, 0, true)
+RETURNING id::text;`, eventID, models.TicketTypeSingle).Scan(&productID); err != nil {
+		t.Fatalf("insert product: %v", err)
+	}
+	if _, err := pool.Exec(ctx, `UPDATE events SET status = $2, publish_at = now() + interval '1 hour' WHERE id = $1`, eventID, models.EventStatusDraft); err != nil {
+		t.Fatalf("make event draft: %v", err)
+	}
+
+	params := models.CreateOrderParams{
+		UserID:        userID,
+		EventID:       eventID,
+		PaymentMethod: models.PaymentMethodPhone,
+		TicketItems:   []models.OrderProductSelection{{ProductID: productID, Quantity: 1}},
+	}
+	if _, err := repo.CreateOrder(ctx, params); !errors.Is(err, ErrInvalidProduct) {
+		t.Fatalf("expected ErrInvalidProduct for draft event, got %v", err)
+	}
+
+	if _, err := pool.Exec(ctx, `UPDATE events SET status = $2, publish_at = NULL, published_at = now() WHERE id = $1`, eventID, models.EventStatusPublished); err != nil {
+		t.Fatalf("publish event: %v", err)
+	}
+	if _, err := repo.CreateOrder(ctx, params); err != nil {
+		t.Fatalf("CreateOrder() on published event: %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"gigme/backend/internal/db"
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TestTicketProductsOwnerScope verifies ticket products owner scope behavior.
+func TestTicketProductsOwnerScope(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if dsn == "" {
+		t.Skip("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := db.NewPool(ctx, dsn)
+	if err != nil {
+		t.Fatalf("db connection: %v", err)
+	}
+	defer pool.Close()
+
+	repo := New(pool)
+
+	ownerID, err := insertTicketingTestUser(ctx, pool, 778101)
+	if err != nil {
+		t.Fatalf("insert owner: %v", err)
+	}
+	otherID, err := insertTicketingTestUser(ctx, pool, 778102)
+	if err != nil {
+		t.Fatalf("insert other user: %v", err)
+	}
+	eventID, err := insertTicketingTestEvent(ctx, pool, ownerID)
+	if err != nil {
+		t.Fatalf("insert event: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = pool.Exec(ctx, `DELETE FROM events WHERE id = $1`, eventID)
+		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id IN ($1, $2)`, ownerID, otherID)
+	})
+
+	input := models.TicketProductInput{EventID: eventID, Name: "Entry", Type: models.TicketTypeSingle, PriceCents: 500, IsActive: true}
+	if _, err := repo.CreateTicketProduct(ctx, otherID, input, &otherID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected ErrNoRows for foreign event, got %v", err)
+	}
+	product, err := repo.CreateTicketProduct(ctx, ownerID, input, &ownerID)
+	if err != nil {
+		t.Fatalf("CreateTicketProduct: %v", err)
+	}
+
+	items, err := repo.ListTicketProducts(ctx, nil, nil, &otherID)
+	if err != nil {
+		t.Fatalf("ListTicketProducts: %v", err)
+	}
+	for _, item := range items {
+		if item.ID == product.ID {
+			t.Fatalf("expected product to be hidden from other user")
+		}
+	}
+	price := int64(700)
+	if _, err := repo.UpdateTicketProduct(ctx, product.ID, models.TicketProductPatch{PriceCents: &price}, &otherID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected ErrNoRows for foreign update, got %v", err)
+	}
+	if err := repo.DeleteTicketProduct(ctx, product.ID, &otherID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("expected ErrNoRows for foreign delete, got %v", err)
+	}
+	if err := repo.DeleteTicketProduct(ctx, product.ID, &ownerID); err != nil {
+		t.Fatalf("DeleteTicketProduct: %v", err)
+	}
+}
@@ -68,7 +68,7 @@ VALUES ($1::uuid, $2, $3, $4, 3, now());`, canceledOrderID, ownerID, eventID, mo
 		_, _ = pool.Exec(ctx, `DELETE FROM users WHERE id = $1`, ownerID)
 	})
 
-	stats, err := repo.GetTicketStats(ctx, &eventID)
+	stats, err := repo.GetTicketStats(ctx, &eventID, nil)
 	if err != nil {
 		t.Fatalf("GetTicketStats: %v", err)
 	}
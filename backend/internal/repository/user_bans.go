@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gigme/backend/internal/models"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrBanLifted = errors.New("ban already lifted")
+
+// userBanSelect selects bans with a flag telling whether the ban is in force.
+const userBanSelect = `
+SELECT id, user_id, scope, reason, expires_at, created_by, created_at, lifted_at, lifted_by,
+	lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now()) AS active
+FROM user_bans`
+
+// activeFullBanCondition matches users with a full ban in force; $1 is the user id.
+const activeFullBanCondition = `EXISTS (
+	SELECT 1 FROM user_bans
+	WHERE user_id = $1 AND scope = 'full' AND lifted_at IS NULL
+		AND (expires_at IS NULL OR expires_at > now())
+)`
+
+// GetActiveUserBans returns bans currently in force for the user. Users blocked
+// directly through users.is_blocked without a ban record get a permanent full ban.
+func (r *Repository) GetActiveUserBans(ctx context.Context, userID int64) ([]models.UserBan, error) {
+	rows, err := r.pool.Query(ctx, userBanSelect+`
+WHERE user_id = $1 AND lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+UNION ALL
+SELECT 0, id, 'full', blocked_reason, NULL, NULL, COALESCE(blocked_at, created_at), NULL, NULL, true
+FROM users
+WHERE id = $1 AND is_blocked = true
+	AND NOT EXISTS (SELECT 1 FROM user_bans WHERE user_id = $1 AND scope = 'full' AND lifted_at IS NULL)
+ORDER BY created_at DESC;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUserBans(rows)
+}
+
+// ListUserBans returns the ban history of the user, newest first.
+func (r *Repository) ListUserBans(ctx context.Context, userID int64) ([]models.UserBan, error) {
+	rows, err := r.pool.Query(ctx, userBanSelect+`
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return scanUserBans(rows)
+}
+
+// CreateUserBan stores a ban. Full bans also mark the user as blocked so that
+// broadcasts and admin filters keep working with users.is_blocked. It returns
+// pgx.ErrNoRows when the user does not exist.
+func (r *Repository) CreateUserBan(ctx context.Context, ban models.UserBan) (models.UserBan, error) {
+	var out models.UserBan
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var id int64
+		if err := tx.QueryRow(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, ban.UserID).Scan(&id); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(ctx, `
+INSERT INTO user_bans (user_id, scope, reason, expires_at, created_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id;`, ban.UserID, ban.Scope, nullString(ban.Reason), ban.ExpiresAt, nullInt64Ptr(ban.CreatedBy)).Scan(&id); err != nil {
+			return err
+		}
+		if ban.Scope == models.BanScopeFull {
+			if _, err := tx.Exec(ctx, `
+UPDATE users
+SET is_blocked = true, blocked_reason = $2, blocked_at = now(), updated_at = now()
+WHERE id = $1;`, ban.UserID, nullString(ban.Reason)); err != nil {
+				return err
+			}
+		}
+		var err error
+		out, err = scanUserBan(tx.QueryRow(ctx, userBanSelect+`
+WHERE id = $1;`, id))
+		return err
+	})
+	if err != nil {
+		return models.UserBan{}, err
+	}
+	return out, nil
+}
+
+// LiftUserBan lifts one ban of the user. It returns ErrBanLifted when the ban
+// was already lifted or has expired.
+func (r *Repository) LiftUserBan(ctx context.Context, userID, banID, liftedBy int64) (models.UserBan, error) {
+	var out models.UserBan
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		var active bool
+		if err := tx.QueryRow(ctx, `
+SELECT lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now())
+FROM user_bans
+WHERE id = $1 AND user_id = $2
+FOR UPDATE;`, banID, userID).Scan(&active); err != nil {
+			return err
+		}
+		if !active {
+			return ErrBanLifted
+		}
+		if _, err := tx.Exec(ctx, `
+UPDATE user_bans
+SET lifted_at = now(), lifted_by = $2
+WHERE id = $1;`, banID, liftedBy); err != nil {
+			return err
+		}
+		if err := syncUserBlocked(ctx, tx, []int64{userID}); err != nil {
+			return err
+		}
+		var err error
+		out, err = scanUserBan(tx.QueryRow(ctx, userBanSelect+`
+WHERE id = $1;`, banID))
+		return err
+	})
+	if err != nil {
+		return models.UserBan{}, err
+	}
+	return out, nil
+}
+
+// LiftUserBans lifts all bans of the user with the given scope and returns how many were lifted.
+func (r *Repository) LiftUserBans(ctx context.Context, userID int64, scope string, liftedBy int64) (int, error) {
+	var lifted int
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, `
+UPDATE user_bans
+SET lifted_at = now(), lifted_by = $3
+WHERE user_id = $1 AND scope = $2 AND lifted_at IS NULL;`, userID, scope, liftedBy)
+		if err != nil {
+			return err
+		}
+		lifted = int(tag.RowsAffected())
+		return syncUserBlocked(ctx, tx, []int64{userID})
+	})
+	return lifted, err
+}
+
+// LiftExpiredBans marks bans that expired before now as lifted at their expiry
+// time and unblocks users left without a full ban. It returns the number of
+// lifted bans.
+func (r *Repository) LiftExpiredBans(ctx context.Context, now time.Time) (int, error) {
+	var lifted int
+	err := r.WithTx(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, `
+UPDATE user_bans
+SET lifted_at = expires_at
+WHERE lifted_at IS NULL AND expires_at IS NOT NULL AND expires_at <= $1
+RETURNING user_id;`, now)
+		if err != nil {
+			return err
+		}
+		userIDs := make([]int64, 0)
+		for rows.Next() {
+			var userID int64
+			if err := rows.Scan(&userID); err != nil {
+				rows.Close()
+				return err
+			}
+			userIDs = append(userIDs, userID)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		lifted = len(userIDs)
+		if lifted == 0 {
+			return nil
+		}
+		return syncUserBlocked(ctx, tx, userIDs)
+	})
+	return lifted, err
+}
+
+// syncUserBlocked clears users.is_blocked for users without a full ban in force.
+func syncUserBlocked(ctx context.Context, tx pgx.Tx, userIDs []int64) error {
+	_, err := tx.Exec(ctx, `
+UPDATE users u
+SET is_blocked = false, blocked_reason = NULL, blocked_at = NULL, updated_at = now()
+WHERE u.id = ANY($1) AND u.is_blocked = true
+	AND NOT EXISTS (
+		SELECT 1 FROM user_bans b
+		WHERE b.user_id = u.id AND b.scope = 'full' AND b.lifted_at IS NULL
+			AND (b.expires_at IS NULL OR b.expires_at > now())
+	);`, userIDs)
+	return err
+}
+
+// scanUserBans scans rows selected with userBanSelect.
+func scanUserBans(rows pgx.Rows) ([]models.UserBan, error) {
+	items := make([]models.UserBan, 0)
+	for rows.Next() {
+		item, err := scanUserBan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// scanUserBan scans a row selected with userBanSelect.
+func scanUserBan(row pgx.Row) (models.UserBan, error) {
+	var item models.UserBan
+	var reason sql.NullString
+	var expiresAt sql.NullTime
+	var createdBy sql.NullInt64
+	var liftedAt sql.NullTime
+	var liftedBy sql.NullInt64
+	if err := row.Scan(
+		&item.ID,
+		&item.UserID,
+		&item.Scope,
+		&reason,
+		&expiresAt,
+		&createdBy,
+		&item.CreatedAt,
+		&liftedAt,
+		&liftedBy,
+		&item.Active,
+	); err != nil {
+		return models.UserBan{}, err
+	}
+	item.Reason = reason.String
+	if expiresAt.Valid {
+		item.ExpiresAt = &expiresAt.Time
+	}
+	if createdBy.Valid {
+		item.CreatedBy = &createdBy.Int64
+	}
+	if liftedAt.Valid {
+		item.LiftedAt = &liftedAt.Time
+	}
+	if liftedBy.Valid {
+		item.LiftedBy = &liftedBy.Int64
+	}
+	return item, nil
+}
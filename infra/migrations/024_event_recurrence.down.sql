@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS events_series_index_uix;
+
+ALTER TABLE events
+  DROP COLUMN IF EXISTS is_series_exception,
+  DROP COLUMN IF EXISTS series_index,
+  DROP COLUMN IF EXISTS series_id;
+
+DROP INDEX IF EXISTS event_series_materialize_ix;
+DROP TABLE IF EXISTS event_series;
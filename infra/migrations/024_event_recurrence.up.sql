@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS event_series (
+  id bigserial PRIMARY KEY,
+  creator_user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  rrule text NOT NULL,
+  timezone text NOT NULL DEFAULT 'UTC',
+  starts_at timestamptz NOT NULL,
+  duration_seconds int NULL CHECK (duration_seconds >= 0),
+  materialized_until timestamptz NOT NULL,
+  materialized_count int NOT NULL DEFAULT 0,
+  is_active boolean NOT NULL DEFAULT true,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS event_series_materialize_ix
+  ON event_series(materialized_until)
+  WHERE is_active = true;
+
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS series_id bigint NULL REFERENCES event_series(id) ON DELETE SET NULL,
+  ADD COLUMN IF NOT EXISTS series_index int NULL,
+  ADD COLUMN IF NOT EXISTS is_series_exception boolean NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS events_series_index_uix
+  ON events(series_id, series_index)
+  WHERE series_id IS NOT NULL;
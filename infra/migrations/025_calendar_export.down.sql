@@ -0,0 +1,8 @@
+ALTER TABLE events
+  DROP COLUMN IF EXISTS revision,
+  DROP COLUMN IF EXISTS timezone;
+
+DROP INDEX IF EXISTS users_calendar_token_uix;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS calendar_token;
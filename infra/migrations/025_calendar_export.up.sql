@@ -0,0 +1,10 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS calendar_token text NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_calendar_token_uix
+  ON users(calendar_token)
+  WHERE calendar_token IS NOT NULL;
+
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS timezone text NULL,
+  ADD COLUMN IF NOT EXISTS revision int NOT NULL DEFAULT 0;
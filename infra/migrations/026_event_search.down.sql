@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS events_address_label_trgm_ix;
+DROP INDEX IF EXISTS events_title_trgm_ix;
+DROP INDEX IF EXISTS events_search_vector_ix;
+
+ALTER TABLE events
+  DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS events_search_vector(text, text, text, text[]);
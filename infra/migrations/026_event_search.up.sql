@@ -0,0 +1,23 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE OR REPLACE FUNCTION events_search_vector(title text, description text, address_label text, links text[])
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+  SELECT
+    setweight(to_tsvector('russian'::regconfig, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english'::regconfig, COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('russian'::regconfig, COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english'::regconfig, COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('simple'::regconfig, COALESCE(address_label, '')), 'C') ||
+    setweight(to_tsvector('simple'::regconfig, COALESCE(array_to_string(links, ' '), '')), 'D')
+$$;
+
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS search_vector tsvector
+  GENERATED ALWAYS AS (events_search_vector(title, description, address_label, links)) STORED;
+
+CREATE INDEX IF NOT EXISTS events_search_vector_ix ON events USING gin(search_vector);
+CREATE INDEX IF NOT EXISTS events_title_trgm_ix ON events USING gin(title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS events_address_label_trgm_ix ON events USING gin(address_label gin_trgm_ops);
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS events_location_geom_ix ON events USING gist((location::geometry));
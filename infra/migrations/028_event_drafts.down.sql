@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS events_publish_due_ix;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+
+ALTER TABLE events
+  DROP COLUMN IF EXISTS published_at,
+  DROP COLUMN IF EXISTS publish_at,
+  DROP COLUMN IF EXISTS status;
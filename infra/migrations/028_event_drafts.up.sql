@@ -0,0 +1,14 @@
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS status text NOT NULL DEFAULT 'published',
+  ADD COLUMN IF NOT EXISTS publish_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS published_at timestamptz NULL;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+ALTER TABLE events
+  ADD CONSTRAINT events_status_check CHECK (status IN ('draft', 'published'));
+
+UPDATE events SET published_at = created_at WHERE published_at IS NULL AND status = 'published';
+
+CREATE INDEX IF NOT EXISTS events_publish_due_ix
+  ON events(publish_at)
+  WHERE status = 'draft' AND publish_at IS NOT NULL;
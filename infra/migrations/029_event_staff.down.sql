@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_username_lower_ix;
+DROP INDEX IF EXISTS event_staff_user_ix;
+DROP TABLE IF EXISTS event_staff;
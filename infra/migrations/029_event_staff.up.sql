@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS event_staff (
+  event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  role text NOT NULL CHECK (role IN ('cohost', 'cashier', 'scanner')),
+  invited_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_staff_user_ix ON event_staff(user_id);
+CREATE INDEX IF NOT EXISTS users_username_lower_ix ON users(lower(username));
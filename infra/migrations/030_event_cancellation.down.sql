@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS order_refunds_event_ix;
+DROP INDEX IF EXISTS order_refunds_status_ix;
+DROP TABLE IF EXISTS order_refunds;
+
+UPDATE events SET status = 'published', is_hidden = true WHERE status = 'canceled';
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+ALTER TABLE events
+  ADD CONSTRAINT events_status_check CHECK (status IN ('draft', 'published'));
+
+ALTER TABLE events
+  DROP COLUMN IF EXISTS cancel_reason,
+  DROP COLUMN IF EXISTS canceled_at;
@@ -0,0 +1,23 @@
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS canceled_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS cancel_reason text NULL;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+ALTER TABLE events
+  ADD CONSTRAINT events_status_check CHECK (status IN ('draft', 'published', 'canceled'));
+
+CREATE TABLE IF NOT EXISTS order_refunds (
+  order_id uuid PRIMARY KEY REFERENCES orders(id) ON DELETE CASCADE,
+  event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'refunded', 'rejected')),
+  reason text NULL,
+  amount_cents bigint NOT NULL DEFAULT 0,
+  currency text NOT NULL DEFAULT 'USD',
+  resolved_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  resolved_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS order_refunds_status_ix ON order_refunds(status, created_at);
+CREATE INDEX IF NOT EXISTS order_refunds_event_ix ON order_refunds(event_id);
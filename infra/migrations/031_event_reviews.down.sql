@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS events_review_request_due_ix;
+
+ALTER TABLE events
+  DROP COLUMN IF EXISTS review_requested_at;
+
+DROP INDEX IF EXISTS event_reviews_moderation_ix;
+DROP INDEX IF EXISTS event_reviews_event_ix;
+DROP TABLE IF EXISTS event_reviews;
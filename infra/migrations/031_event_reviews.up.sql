@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS event_reviews (
+  id bigserial PRIMARY KEY,
+  event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  rating smallint NOT NULL CHECK (rating BETWEEN 1 AND 5),
+  body text NULL,
+  is_hidden boolean NOT NULL DEFAULT false,
+  hidden_reason text NULL,
+  moderated_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  moderated_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  UNIQUE (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_reviews_event_ix ON event_reviews(event_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS event_reviews_moderation_ix ON event_reviews(is_hidden, created_at DESC);
+
+ALTER TABLE events
+  ADD COLUMN IF NOT EXISTS review_requested_at timestamptz NULL;
+
+UPDATE events
+SET review_requested_at = now()
+WHERE COALESCE(ends_at, starts_at + interval '2 hours') < now();
+
+CREATE INDEX IF NOT EXISTS events_review_request_due_ix
+  ON events(starts_at)
+  WHERE status = 'published' AND review_requested_at IS NULL;
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS event_comment_reactions_user_ix;
+DROP TABLE IF EXISTS event_comment_reactions;
+
+DROP INDEX IF EXISTS event_comments_parent_ix;
+
+ALTER TABLE event_comments
+  DROP COLUMN IF EXISTS edited_at,
+  DROP COLUMN IF EXISTS parent_id;
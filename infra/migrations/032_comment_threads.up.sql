@@ -0,0 +1,15 @@
+ALTER TABLE event_comments
+  ADD COLUMN IF NOT EXISTS parent_id bigint NULL REFERENCES event_comments(id) ON DELETE CASCADE,
+  ADD COLUMN IF NOT EXISTS edited_at timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS event_comments_parent_ix ON event_comments(parent_id) WHERE parent_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS event_comment_reactions (
+  comment_id bigint NOT NULL REFERENCES event_comments(id) ON DELETE CASCADE,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  emoji text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (comment_id, user_id, emoji)
+);
+
+CREATE INDEX IF NOT EXISTS event_comment_reactions_user_ix ON event_comment_reactions(user_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS reports_target_status_ix;
+DROP TABLE IF EXISTS reports;
+
+DROP INDEX IF EXISTS report_targets_status_ix;
+DROP TABLE IF EXISTS report_targets;
+
+ALTER TABLE event_comments
+  DROP COLUMN IF EXISTS is_hidden;
@@ -0,0 +1,33 @@
+ALTER TABLE event_comments
+  ADD COLUMN IF NOT EXISTS is_hidden boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS report_targets (
+  target_type text NOT NULL CHECK (target_type IN ('event', 'comment', 'user')),
+  target_id bigint NOT NULL,
+  status text NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+  auto_hidden_at timestamptz NULL,
+  resolved_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  resolved_at timestamptz NULL,
+  resolution_note text NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (target_type, target_id)
+);
+
+CREATE INDEX IF NOT EXISTS report_targets_status_ix ON report_targets(status, updated_at DESC);
+
+CREATE TABLE IF NOT EXISTS reports (
+  id bigserial PRIMARY KEY,
+  target_type text NOT NULL,
+  target_id bigint NOT NULL,
+  reporter_user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  reason text NOT NULL CHECK (reason IN ('spam', 'abuse', 'fraud', 'inappropriate', 'other')),
+  details text NULL,
+  status text NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'actioned', 'dismissed')),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  resolved_at timestamptz NULL,
+  FOREIGN KEY (target_type, target_id) REFERENCES report_targets(target_type, target_id) ON DELETE CASCADE,
+  UNIQUE (target_type, target_id, reporter_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS reports_target_status_ix ON reports(target_type, target_id, status);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS event_comments_user_created_ix;
+DROP INDEX IF EXISTS content_filter_log_user_ix;
+DROP INDEX IF EXISTS content_filter_log_status_ix;
+DROP TABLE IF EXISTS content_filter_log;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS content_filter_log (
+  id bigserial PRIMARY KEY,
+  kind text NOT NULL CHECK (kind IN ('comment', 'event')),
+  target_id bigint NULL,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  event_id bigint NULL REFERENCES events(id) ON DELETE CASCADE,
+  content text NOT NULL,
+  decision text NOT NULL CHECK (decision IN ('hold', 'reject')),
+  rule text NOT NULL,
+  match text NULL,
+  status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+  reviewed_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  reviewed_at timestamptz NULL,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS content_filter_log_status_ix ON content_filter_log(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS content_filter_log_user_ix ON content_filter_log(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS event_comments_user_created_ix ON event_comments(user_id, created_at DESC);
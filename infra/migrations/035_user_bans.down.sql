@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS user_bans_expiring_ix;
+DROP INDEX IF EXISTS user_bans_user_ix;
+DROP TABLE IF EXISTS user_bans;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS user_bans (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  scope text NOT NULL CHECK (scope IN ('full', 'comments', 'events', 'purchases')),
+  reason text NULL,
+  expires_at timestamptz NULL,
+  created_by bigint NULL REFERENCES users(id) ON DELETE SET NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  lifted_at timestamptz NULL,
+  lifted_by bigint NULL REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS user_bans_user_ix ON user_bans(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS user_bans_expiring_ix ON user_bans(expires_at) WHERE lifted_at IS NULL AND expires_at IS NOT NULL;
+
+INSERT INTO user_bans (user_id, scope, reason, created_at)
+SELECT id, 'full', blocked_reason, COALESCE(blocked_at, now())
+FROM users
+WHERE is_blocked = true;
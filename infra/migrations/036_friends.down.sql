@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS user_contact_hashes_value_ix;
+DROP TABLE IF EXISTS user_contact_hashes;
+
+DROP INDEX IF EXISTS users_phone_hash_uq;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS hide_attendance,
+  DROP COLUMN IF EXISTS phone_hash;
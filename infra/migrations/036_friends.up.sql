@@ -0,0 +1,15 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS phone_hash text NULL,
+  ADD COLUMN IF NOT EXISTS hide_attendance boolean NOT NULL DEFAULT false;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_phone_hash_uq ON users(phone_hash) WHERE phone_hash IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS user_contact_hashes (
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  kind text NOT NULL CHECK (kind IN ('phone', 'telegram')),
+  value_hash text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_id, kind, value_hash)
+);
+
+CREATE INDEX IF NOT EXISTS user_contact_hashes_value_ix ON user_contact_hashes(kind, value_hash);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+  DROP COLUMN IF EXISTS notify_all_events;
+
+DROP INDEX IF EXISTS user_follows_followee_ix;
+DROP TABLE IF EXISTS user_follows;
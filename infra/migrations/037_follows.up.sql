@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS user_follows (
+  follower_user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  followee_user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (follower_user_id, followee_user_id),
+  CHECK (follower_user_id <> followee_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS user_follows_followee_ix ON user_follows(followee_user_id, created_at DESC);
+
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS notify_all_events boolean NOT NULL DEFAULT false;
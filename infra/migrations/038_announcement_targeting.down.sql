@@ -0,0 +1 @@
+DROP INDEX IF EXISTS notification_jobs_user_kind_created_ix;
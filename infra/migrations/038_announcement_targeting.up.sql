@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS notification_jobs_user_kind_created_ix ON notification_jobs(user_id, kind, created_at DESC);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS notification_jobs_user_pending_ix;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS timezone,
+  DROP COLUMN IF EXISTS quiet_hours_end,
+  DROP COLUMN IF EXISTS quiet_hours_start,
+  DROP COLUMN IF EXISTS reminder_offsets,
+  DROP COLUMN IF EXISTS notify_muted;
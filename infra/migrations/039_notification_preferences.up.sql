@@ -0,0 +1,8 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS notify_muted text[] NOT NULL DEFAULT '{}',
+  ADD COLUMN IF NOT EXISTS reminder_offsets integer[] NOT NULL DEFAULT '{60}',
+  ADD COLUMN IF NOT EXISTS quiet_hours_start smallint,
+  ADD COLUMN IF NOT EXISTS quiet_hours_end smallint,
+  ADD COLUMN IF NOT EXISTS timezone text;
+
+CREATE INDEX IF NOT EXISTS notification_jobs_user_pending_ix ON notification_jobs(user_id, kind) WHERE status = 'pending';
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS saved_search_alerts_search_created_ix;
+DROP INDEX IF EXISTS saved_search_alerts_user_event_ux;
+DROP TABLE IF EXISTS saved_search_alerts;
+
+DROP INDEX IF EXISTS saved_searches_user_ix;
+DROP TABLE IF EXISTS saved_searches;
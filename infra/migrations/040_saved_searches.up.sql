@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS saved_searches (
+  id bigserial PRIMARY KEY,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name text NOT NULL,
+  query text,
+  filters text[] NOT NULL DEFAULT '{}',
+  location geography(Point, 4326),
+  radius_m integer,
+  starts_after timestamptz,
+  starts_before timestamptz,
+  within_days integer,
+  price text NOT NULL DEFAULT 'any' CHECK (price IN ('any', 'free', 'paid')),
+  alerts_enabled boolean NOT NULL DEFAULT true,
+  daily_limit integer NOT NULL DEFAULT 3,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  CHECK ((location IS NULL) = (radius_m IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS saved_searches_user_ix ON saved_searches(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS saved_search_alerts (
+  search_id bigint NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+  event_id bigint NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+  user_id bigint NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  PRIMARY KEY (search_id, event_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS saved_search_alerts_user_event_ux ON saved_search_alerts(user_id, event_id);
+CREATE INDEX IF NOT EXISTS saved_search_alerts_search_created_ix ON saved_search_alerts(search_id, created_at DESC);
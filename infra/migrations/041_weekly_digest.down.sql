@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_digest_weekday_ix;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS digest_last_sent_at,
+  DROP COLUMN IF EXISTS digest_time,
+  DROP COLUMN IF EXISTS digest_weekday;
@@ -0,0 +1,6 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS digest_weekday smallint CHECK (digest_weekday BETWEEN 1 AND 7),
+  ADD COLUMN IF NOT EXISTS digest_time smallint NOT NULL DEFAULT 600,
+  ADD COLUMN IF NOT EXISTS digest_last_sent_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS users_digest_weekday_ix ON users(digest_weekday) WHERE digest_weekday IS NOT NULL;
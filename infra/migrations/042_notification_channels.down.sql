@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS user_web_push_subscriptions_user_active_ix;
+DROP TABLE IF EXISTS user_web_push_subscriptions;
+
+ALTER TABLE notification_jobs
+  DROP COLUMN IF EXISTS deliveries;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS notify_channels,
+  DROP COLUMN IF EXISTS email;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+  DROP COLUMN IF EXISTS telegram_unreachable_reason,
+  DROP COLUMN IF EXISTS telegram_unreachable_at;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS telegram_unreachable_at timestamptz,
+  ADD COLUMN IF NOT EXISTS telegram_unreachable_reason text;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS telegram_updates_received_at_ix;
+DROP TABLE IF EXISTS telegram_updates;
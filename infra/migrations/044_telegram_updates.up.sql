@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS telegram_updates (
+  update_id bigint PRIMARY KEY,
+  received_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS telegram_updates_received_at_ix
+  ON telegram_updates(received_at);
@@ -0,0 +1,3 @@
+ALTER TABLE event_participants
+  DROP COLUMN IF EXISTS checked_in_by,
+  DROP COLUMN IF EXISTS checked_in_at;
@@ -0,0 +1,3 @@
+ALTER TABLE event_participants
+  ADD COLUMN IF NOT EXISTS checked_in_at timestamptz NULL,
+  ADD COLUMN IF NOT EXISTS checked_in_by bigint NULL REFERENCES users(id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+-- Dropped contact hashes and phones can't be restored; clients upload contacts again.
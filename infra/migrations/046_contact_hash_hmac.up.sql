@@ -0,0 +1,6 @@
+-- Contact hashes are now keyed with the server secret and phones must be
+-- verified through Telegram, so hashes and matches stored before are dropped.
+-- Clients upload their contacts again.
+DELETE FROM user_contact_matches;
+DELETE FROM user_contact_hashes;
+UPDATE users SET phone_hash = NULL WHERE phone_hash IS NOT NULL;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS users_email_token_hash_uidx;
+
+ALTER TABLE users
+  DROP COLUMN IF EXISTS email_token_expires_at,
+  DROP COLUMN IF EXISTS email_token_hash,
+  DROP COLUMN IF EXISTS pending_email;
@@ -0,0 +1,14 @@
+ALTER TABLE users
+  ADD COLUMN IF NOT EXISTS pending_email text,
+  ADD COLUMN IF NOT EXISTS email_token_hash text,
+  ADD COLUMN IF NOT EXISTS email_token_expires_at timestamptz;
+
+-- Addresses saved before verification existed were never confirmed.
+UPDATE users
+SET pending_email = email,
+  email = NULL
+WHERE email IS NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_token_hash_uidx
+  ON users(email_token_hash)
+  WHERE email_token_hash IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS events_access_key_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS events_access_key_uix
+  ON events(access_key)
+  WHERE access_key IS NOT NULL;
@@ -0,0 +1,8 @@
+-- Occurrences of a private series share the access key of the series, so one
+-- invite link opens every date. Keys are always checked together with the
+-- event id, so they no longer need to be unique.
+DROP INDEX IF EXISTS events_access_key_uix;
+
+CREATE INDEX IF NOT EXISTS events_access_key_idx
+  ON events(access_key)
+  WHERE access_key IS NOT NULL;
@@ -93,10 +93,14 @@ set_kv "$FRONT_ENV" "VITE_API_URL" "$api_url"
 set_kv "$INFRA_ENV" "BASE_URL" "$webapp_url"
 
 bot_token=$(get_var "$INFRA_ENV" "TELEGRAM_BOT_TOKEN")
+webhook_secret=$(get_var "$INFRA_ENV" "TELEGRAM_WEBHOOK_SECRET")
 if [[ -n "$bot_token" ]]; then
   webhook_url="${api_url}/telegram/webhook"
-  curl -sS -X POST "https://api.telegram.org/bot${bot_token}/setWebhook" \
-    -d "url=${webhook_url}" >/dev/null
+  webhook_args=(-d "url=${webhook_url}" --data-urlencode 'allowed_updates=["message","callback_query","my_chat_member"]')
+  if [[ -n "$webhook_secret" ]]; then
+    webhook_args+=(-d "secret_token=${webhook_secret}")
+  fi
+  curl -sS -X POST "https://api.telegram.org/bot${bot_token}/setWebhook" "${webhook_args[@]}" >/dev/null
   echo "Webhook set to ${webhook_url}"
 else
   echo "TELEGRAM_BOT_TOKEN missing in infra/.env; skipped webhook update."